@@ -0,0 +1,102 @@
+package app
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/report"
+)
+
+// APIEvent is a lifecycle event of a container, e.g. its death.
+type APIEvent struct {
+	Time          time.Time `json:"time"`
+	Type          string    `json:"type"`
+	Detail        string    `json:"detail,omitempty"`
+	NodeID        string    `json:"nodeId"`
+	ContainerName string    `json:"containerName"`
+}
+
+type eventsByTime []APIEvent
+
+func (e eventsByTime) Len() int      { return len(e) }
+func (e eventsByTime) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e eventsByTime) Less(i, j int) bool {
+	if !e[i].Time.Equal(e[j].Time) {
+		return e[i].Time.Before(e[j].Time)
+	}
+	return e[i].NodeID < e[j].NodeID
+}
+
+// Lifecycle events of containers, oldest first. The node parameter
+// restricts them to a container, or to the containers of a node they
+// belong to, directly or not, e.g. a pod, its deployment or a host.
+func handleEvents(ctx context.Context, rep Reporter, w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondWith(w, http.StatusBadRequest, err)
+		return
+	}
+	rpt, err := rep.Report(ctx, deserializeTimestamp(r.Form.Get("timestamp")))
+	if err != nil {
+		respondWith(w, http.StatusInternalServerError, err)
+		return
+	}
+	respondWith(w, http.StatusOK, containerEvents(rpt, r.Form.Get("node")))
+}
+
+// containerEvents extracts the events of the containers of rpt which
+// are, or belong to, the node with nodeID, or of all of them if nodeID
+// is empty.
+func containerEvents(rpt report.Report, nodeID string) []APIEvent {
+	template := docker.ContainerTableTemplates[docker.ContainerEventsPrefix]
+	events := []APIEvent{}
+	for id, node := range rpt.Container.Nodes {
+		if nodeID != "" && id != nodeID && !hasAncestor(rpt, node, nodeID, map[string]bool{}) {
+			continue
+		}
+		name, _ := node.Latest.Lookup(docker.ContainerName)
+		for _, row := range node.ExtractMulticolumnTable(template) {
+			t, err := time.Parse(time.RFC3339Nano, row.Entries[docker.ContainerEventTime])
+			if err != nil {
+				continue
+			}
+			events = append(events, APIEvent{
+				Time:          t,
+				Type:          row.Entries[docker.ContainerEventType],
+				Detail:        row.Entries[docker.ContainerEventDetail],
+				NodeID:        id,
+				ContainerName: name,
+			})
+		}
+	}
+	sort.Sort(eventsByTime(events))
+	return events
+}
+
+// hasAncestor tells whether the node with ancestorID is a parent of node,
+// or a parent of one of its parents, e.g. the deployment of its pod.
+func hasAncestor(rpt report.Report, node report.Node, ancestorID string, visited map[string]bool) bool {
+	for _, topologyID := range node.Parents.Keys() {
+		parents, _ := node.Parents.Lookup(topologyID)
+		if parents.Contains(ancestorID) {
+			return true
+		}
+		topology, ok := rpt.Topology(topologyID)
+		if !ok {
+			continue
+		}
+		for _, parentID := range parents {
+			if visited[parentID] {
+				continue
+			}
+			visited[parentID] = true
+			if parent, ok := topology.Nodes[parentID]; ok && hasAncestor(rpt, parent, ancestorID, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
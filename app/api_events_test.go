@@ -0,0 +1,67 @@
+package app_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/fixture"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func TestAPIEvents(t *testing.T) {
+	var (
+		died = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+		oom  = died.Add(-time.Second)
+		rpt  = fixture.Report.Copy()
+	)
+	event := func(t time.Time, eventType, detail string) report.Row {
+		return report.Row{
+			ID: t.Format(time.RFC3339Nano),
+			Entries: map[string]string{
+				docker.ContainerEventTime:   t.Format(time.RFC3339Nano),
+				docker.ContainerEventType:   eventType,
+				docker.ContainerEventDetail: detail,
+			},
+		}
+	}
+	rpt.Container.Nodes[fixture.ClientContainerNodeID] = rpt.Container.Nodes[fixture.ClientContainerNodeID].
+		AddPrefixMulticolumnTable(docker.ContainerEventsPrefix, []report.Row{
+			event(died, docker.DieEvent, "exit code 137"),
+			event(oom, docker.OOMEvent, ""),
+		})
+	router := mux.NewRouter().SkipClean(true)
+	app.RegisterTopologyRoutes(router, app.StaticCollector(rpt), map[string]bool{})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	want := []app.APIEvent{
+		{Time: oom, Type: docker.OOMEvent, NodeID: fixture.ClientContainerNodeID, ContainerName: fixture.ClientContainerName},
+		{Time: died, Type: docker.DieEvent, Detail: "exit code 137", NodeID: fixture.ClientContainerNodeID, ContainerName: fixture.ClientContainerName},
+	}
+	for _, node := range []string{"", fixture.ClientContainerNodeID, fixture.ClientPodNodeID, fixture.ClientHostNodeID, fixture.ServiceNodeID} {
+		var have []app.APIEvent
+		if err := json.Unmarshal(getRawJSON(t, ts, "/api/events?node="+url.QueryEscape(node)), &have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%q: %s", node, test.Diff(want, have))
+		}
+	}
+
+	var have []app.APIEvent
+	if err := json.Unmarshal(getRawJSON(t, ts, "/api/events?node="+url.QueryEscape(fixture.ServerPodNodeID)), &have); err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != 0 {
+		t.Errorf("Expected no events for the server pod, got %v", have)
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/scope/render/policy"
+)
+
+const (
+	// Reports are sampled every policyStep between from and to, by
+	// default; the default app window.
+	policyStep = 15 * time.Second
+	// At most maxPolicySamples reports are sampled for a policy.
+	maxPolicySamples = 1000
+)
+
+// policyRequest is a request for the policies of some pods.
+type policyRequest struct {
+	format     string
+	timestamps []time.Time
+}
+
+// parsePolicyRequest parses the format, and the time range to observe
+// flows over: from and to, sampled every step, or the report at
+// timestamp.
+func parsePolicyRequest(r *http.Request) (policyRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return policyRequest{}, err
+	}
+	req := policyRequest{format: r.Form.Get("format")}
+	if req.format == "" {
+		req.format = policy.YAML
+	}
+	if _, ok := policy.ContentType(req.format); !ok {
+		return policyRequest{}, fmt.Errorf("unknown format: %q", req.format)
+	}
+	if r.Form.Get("from") == "" {
+		req.timestamps = []time.Time{deserializeTimestamp(r.Form.Get("timestamp"))}
+		return req, nil
+	}
+
+	from, err := time.Parse(time.RFC3339, r.Form.Get("from"))
+	if err != nil {
+		return policyRequest{}, err
+	}
+	to := time.Now()
+	if t := r.Form.Get("to"); t != "" {
+		if to, err = time.Parse(time.RFC3339, t); err != nil {
+			return policyRequest{}, err
+		}
+	}
+	step := policyStep
+	if s := r.Form.Get("step"); s != "" {
+		if step, err = time.ParseDuration(s); err != nil {
+			return policyRequest{}, err
+		}
+		if step <= 0 {
+			return policyRequest{}, fmt.Errorf("step must be positive, got %v", step)
+		}
+	}
+	if to.Before(from) {
+		return policyRequest{}, fmt.Errorf("'to' is before 'from'")
+	}
+	if to.Sub(from)/step >= maxPolicySamples {
+		return policyRequest{}, fmt.Errorf("too many samples between 'from' and 'to'; use a larger step")
+	}
+	for t := from; t.Before(to); t = t.Add(step) {
+		req.timestamps = append(req.timestamps, t)
+	}
+	req.timestamps = append(req.timestamps, to)
+	return req, nil
+}
+
+// flows accumulates the flows observed in the reports requested.
+func (req policyRequest) flows(ctx context.Context, rep Reporter) (policy.Flows, error) {
+	flows := policy.MakeFlows()
+	for _, timestamp := range req.timestamps {
+		rpt, err := rep.Report(ctx, timestamp)
+		if err != nil {
+			return flows, err
+		}
+		flows.Add(rpt)
+	}
+	return flows, nil
+}
+
+func (req policyRequest) write(w http.ResponseWriter, flows policy.Flows, name string, podIDs []string) {
+	contentType, _ := policy.ContentType(req.format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+req.format))
+	if err := flows.Write(w, req.format, podIDs); err != nil {
+		log.Errorf("Error writing policy %s as %s: %v", name, req.format, err)
+	}
+}
+
+// Network policy allowing the flows observed to and from a pod.
+func handlePodPolicy(ctx context.Context, rep Reporter, w http.ResponseWriter, r *http.Request) {
+	req, err := parsePolicyRequest(r)
+	if err != nil {
+		respondWith(w, http.StatusBadRequest, err)
+		return
+	}
+	flows, err := req.flows(ctx, rep)
+	if err != nil {
+		respondWith(w, http.StatusInternalServerError, err)
+		return
+	}
+	podID := mux.Vars(r)["id"]
+	pod, ok := flows.Pods[podID]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if req.format != policy.IPTables {
+		if err := flows.CheckPolicy(podID); err != nil {
+			respondWith(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	req.write(w, flows, pod.Name, []string{podID})
+}
+
+// Network policies allowing the flows observed to and from the pods of
+// a namespace.
+func handleNamespacePolicy(ctx context.Context, rep Reporter, w http.ResponseWriter, r *http.Request) {
+	req, err := parsePolicyRequest(r)
+	if err != nil {
+		respondWith(w, http.StatusBadRequest, err)
+		return
+	}
+	flows, err := req.flows(ctx, rep)
+	if err != nil {
+		respondWith(w, http.StatusInternalServerError, err)
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	req.write(w, flows, namespace, flows.PodIDs(namespace))
+}
@@ -0,0 +1,37 @@
+package app_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/weaveworks/scope/test/fixture"
+)
+
+func TestAPIPodPolicy(t *testing.T) {
+	ts := topologyServer()
+	defer ts.Close()
+	var (
+		podPath       = "/api/topology/pods/" + url.QueryEscape(fixture.ServerPodNodeID) + "/policy"
+		namespacePath = "/api/topology/pods/policy/" + fixture.KubernetesNamespace
+	)
+
+	is404(t, ts, "/api/topology/pods/foo/policy")
+	is400(t, ts, podPath+"?format=foo")
+	is400(t, ts, podPath+"?from=2017-01-01T00:00:00Z&to=2016-01-01T00:00:00Z")
+	is400(t, ts, podPath+"?from=2017-01-01T00:00:00Z&to=2017-01-02T00:00:00Z&step=1s")
+	// The fixture pods have no labels for a NetworkPolicy to select them by
+	is400(t, ts, podPath)
+
+	body := string(is200(t, ts, podPath+"?format=iptables"))
+	rule := "-s " + fixture.ClientIP + "/32 -d " + fixture.ServerIP + "/32 -p tcp -m tcp --dport 80 -j RETURN"
+	if !strings.Contains(body, rule) {
+		t.Errorf("Expected %q in:\n%s", rule, body)
+	}
+
+	body = string(is200(t, ts, namespacePath+"?format=iptables"))
+	if !strings.Contains(body, rule) {
+		t.Errorf("Expected %q in:\n%s", rule, body)
+	}
+	is200(t, ts, namespacePath)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/weaveworks/scope/probe/docker"
 	"github.com/weaveworks/scope/probe/kubernetes"
 	"github.com/weaveworks/scope/render"
+	"github.com/weaveworks/scope/render/expr"
 	"github.com/weaveworks/scope/report"
 )
 
@@ -23,6 +24,7 @@ const (
 	apiTopologyURL         = "/api/topology/"
 	processesID            = "processes"
 	processesByNameID      = "processes-by-name"
+	processesByTreeID      = "processes-by-tree"
 	systemGroupID          = "system"
 	containersID           = "containers"
 	containersByHostnameID = "containers-by-hostname"
@@ -30,7 +32,10 @@ const (
 	podsID                 = "pods"
 	kubeControllersID      = "kube-controllers"
 	servicesID             = "services"
+	ingressesID            = "ingresses"
+	volumesID              = "volumes"
 	hostsID                = "hosts"
+	kubeNodesID            = "kube-nodes"
 	weaveID                = "weave"
 	ecsTasksID             = "ecs-tasks"
 	ecsServicesID          = "ecs-services"
@@ -109,7 +114,7 @@ func updateKubeFilters(rpt report.Report, topologies []APITopologyDesc) []APITop
 	sort.Strings(ns)
 	topologies = append([]APITopologyDesc{}, topologies...) // Make a copy so we can make changes safely
 	for i, t := range topologies {
-		if t.id == containersID || t.id == podsID || t.id == servicesID || t.id == kubeControllersID {
+		if t.id == containersID || t.id == podsID || t.id == servicesID || t.id == ingressesID || t.id == kubeControllersID {
 			topologies[i] = mergeTopologyFilters(t, []APITopologyOptionGroup{
 				namespaceFilters(ns, "All Namespaces"),
 			})
@@ -203,6 +208,13 @@ func MakeRegistry() *Registry {
 			Options:     unconnectedFilter,
 			HideIfEmpty: true,
 		},
+		APITopologyDesc{
+			id:          processesByTreeID,
+			parent:      processesID,
+			renderer:    render.ProcessTreeRenderer,
+			Name:        "by tree",
+			HideIfEmpty: true,
+		},
 		APITopologyDesc{
 			id:       containersID,
 			renderer: render.ContainerWithImageNameRenderer,
@@ -248,6 +260,21 @@ func MakeRegistry() *Registry {
 			Options:     []APITopologyOptionGroup{unmanagedFilter},
 			HideIfEmpty: true,
 		},
+		APITopologyDesc{
+			id:          ingressesID,
+			parent:      podsID,
+			renderer:    render.IngressRenderer,
+			Name:        "Ingresses",
+			Options:     []APITopologyOptionGroup{unmanagedFilter},
+			HideIfEmpty: true,
+		},
+		APITopologyDesc{
+			id:          volumesID,
+			parent:      podsID,
+			renderer:    render.PodVolumeRenderer,
+			Name:        "Volumes",
+			HideIfEmpty: true,
+		},
 		APITopologyDesc{
 			id:          ecsTasksID,
 			renderer:    render.ECSTaskRenderer,
@@ -284,6 +311,13 @@ func MakeRegistry() *Registry {
 			renderer: render.WeaveRenderer,
 			Name:     "Weave Net",
 		},
+		APITopologyDesc{
+			id:          kubeNodesID,
+			parent:      hostsID,
+			renderer:    render.KubernetesNodeRenderer,
+			Name:        "Kubernetes nodes",
+			HideIfEmpty: true,
+		},
 	)
 
 	return registry
@@ -461,6 +495,14 @@ func (r *Registry) walk(f func(APITopologyDesc)) {
 func (r *Registry) makeTopologyList(rep Reporter) CtxHandlerFunc {
 	return func(ctx context.Context, w http.ResponseWriter, req *http.Request) {
 		timestamp := deserializeTimestamp(req.URL.Query().Get("timestamp"))
+		if err := req.ParseForm(); err != nil {
+			respondWith(w, http.StatusBadRequest, err)
+			return
+		}
+		if _, err := filterExpression(req.Form); err != nil {
+			respondWith(w, http.StatusBadRequest, err)
+			return
+		}
 		report, err := rep.Report(ctx, timestamp)
 		if err != nil {
 			respondWith(w, http.StatusInternalServerError, err)
@@ -527,12 +569,40 @@ func (r *Registry) RendererForTopology(topologyID string, values url.Values, rpt
 			filters = append(filters, filter)
 		}
 	}
+	filter, err := filterExpression(values)
+	if err != nil {
+		return nil, nil, err
+	}
+	if filter != nil {
+		filters = append(filters, filter)
+	}
+	var transformers []render.Transformer
+	if collapse := values.Get("collapse"); collapse != "" && topologyID == processesByTreeID {
+		transformers = append(transformers, render.CollapseProcessSubtrees(strings.Split(collapse, ",")))
+	}
 	if len(filters) > 0 {
-		return topology.renderer, render.Transformers([]render.Transformer{render.ComposeFilterFuncs(filters...), render.FilterUnconnectedPseudo}), nil
+		transformers = append(transformers, render.ComposeFilterFuncs(filters...))
+	}
+	if len(transformers) > 0 {
+		return topology.renderer, render.Transformers(append(transformers, render.FilterUnconnectedPseudo)), nil
 	}
 	return topology.renderer, render.FilterUnconnectedPseudo, nil
 }
 
+// filterExpression compiles the expression given as the filter
+// parameter, if any. See render/expr for the syntax.
+func filterExpression(values url.Values) (render.FilterFunc, error) {
+	s := values.Get("filter")
+	if s == "" {
+		return nil, nil
+	}
+	f, err := expr.Compile(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %v", s, err)
+	}
+	return f, nil
+}
+
 type reporterHandler func(context.Context, Reporter, http.ResponseWriter, *http.Request)
 
 func captureReporter(rep Reporter, f reporterHandler) CtxHandlerFunc {
@@ -551,6 +621,14 @@ func (r *Registry) captureRenderer(rep Reporter, f rendererHandler) CtxHandlerFu
 			http.NotFound(w, req)
 			return
 		}
+		if err := req.ParseForm(); err != nil {
+			respondWith(w, http.StatusBadRequest, err)
+			return
+		}
+		if _, err := filterExpression(req.Form); err != nil {
+			respondWith(w, http.StatusBadRequest, err)
+			return
+		}
 		rpt, err := rep.Report(ctx, timestamp)
 		if err != nil {
 			respondWith(w, http.StatusInternalServerError, err)
@@ -1,7 +1,9 @@
 package app
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -11,6 +13,7 @@ import (
 	"github.com/weaveworks/scope/common/xfer"
 	"github.com/weaveworks/scope/render"
 	"github.com/weaveworks/scope/render/detailed"
+	"github.com/weaveworks/scope/render/export"
 	"github.com/weaveworks/scope/report"
 )
 
@@ -28,6 +31,27 @@ type APINode struct {
 	Node detailed.Node `json:"node"`
 }
 
+// APITopologyDiff is returned by the /api/topology/{name}/diff handler.
+type APITopologyDiff struct {
+	From  time.Time     `json:"from"`
+	To    time.Time     `json:"to"`
+	Nodes detailed.Diff `json:"nodes"`
+	Edges APIEdgeDiff   `json:"edges"`
+}
+
+// APIEdge is a directed edge between two rendered nodes.
+type APIEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// APIEdgeDiff represents the edges added and removed between two
+// renderings of a topology.
+type APIEdgeDiff struct {
+	Add    []APIEdge `json:"add"`
+	Remove []APIEdge `json:"remove"`
+}
+
 // RenderContextForReporter creates the rendering context for the given reporter.
 func RenderContextForReporter(rep Reporter, r report.Report) detailed.RenderContext {
 	rc := detailed.RenderContext{Report: r}
@@ -39,11 +63,29 @@ func RenderContextForReporter(rep Reporter, r report.Report) detailed.RenderCont
 
 type rendererHandler func(context.Context, render.Renderer, render.Transformer, detailed.RenderContext, http.ResponseWriter, *http.Request)
 
-// Full topology.
+// Full topology, as JSON or, with format=, as a graph document.
 func handleTopology(ctx context.Context, renderer render.Renderer, transformer render.Transformer, rc detailed.RenderContext, w http.ResponseWriter, r *http.Request) {
-	respondWith(w, http.StatusOK, APITopology{
-		Nodes: detailed.Summaries(rc, render.Render(rc.Report, renderer, transformer).Nodes),
-	})
+	format := r.URL.Query().Get("format")
+	if format == "" || format == "json" {
+		respondWith(w, http.StatusOK, APITopology{
+			Nodes: detailed.Summaries(rc, render.Render(rc.Report, renderer, transformer).Nodes),
+		})
+		return
+	}
+	contentType, ok := export.ContentType(format)
+	if !ok {
+		respondWith(w, http.StatusBadRequest, fmt.Errorf("unknown format: %q", format))
+		return
+	}
+	var (
+		topologyID = mux.Vars(r)["topology"]
+		nodes      = detailed.Summaries(rc, render.Render(rc.Report, renderer, transformer).Nodes)
+	)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", topologyID+"."+format))
+	if err := export.Write(w, format, topologyID, nodes); err != nil {
+		log.Errorf("Error exporting topology %s as %s: %v", topologyID, format, err)
+	}
 }
 
 // Individual nodes.
@@ -93,6 +135,10 @@ func handleWebsocket(
 			return
 		}
 	}
+	if _, err := filterExpression(r.Form); err != nil {
+		respondWith(w, http.StatusBadRequest, err)
+		return
+	}
 
 	conn, err := xfer.Upgrade(w, r, nil)
 	if err != nil {
@@ -164,3 +210,116 @@ func handleWebsocket(
 		}
 	}
 }
+
+// Differences between the topology at two points in time.
+func handleDiff(ctx context.Context, rep Reporter, w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondWith(w, http.StatusBadRequest, err)
+		return
+	}
+	topologyID := mux.Vars(r)["topology"]
+	if _, ok := topologyRegistry.get(topologyID); !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if _, err := filterExpression(r.Form); err != nil {
+		respondWith(w, http.StatusBadRequest, err)
+		return
+	}
+	if r.Form.Get("from") == "" {
+		respondWith(w, http.StatusBadRequest, fmt.Errorf("missing 'from' timestamp"))
+		return
+	}
+	from, err := time.Parse(time.RFC3339, r.Form.Get("from"))
+	if err != nil {
+		respondWith(w, http.StatusBadRequest, err)
+		return
+	}
+	to := time.Now()
+	if t := r.Form.Get("to"); t != "" {
+		if to, err = time.Parse(time.RFC3339, t); err != nil {
+			respondWith(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	renderAt := func(timestamp time.Time) (detailed.NodeSummaries, error) {
+		rpt, err := rep.Report(ctx, timestamp)
+		if err != nil {
+			return nil, err
+		}
+		renderer, filter, err := topologyRegistry.RendererForTopology(topologyID, r.Form, rpt)
+		if err != nil {
+			return nil, err
+		}
+		return detailed.Summaries(RenderContextForReporter(rep, rpt), render.Render(rpt, renderer, filter).Nodes), nil
+	}
+	before, err := renderAt(from)
+	if err != nil {
+		respondWith(w, http.StatusInternalServerError, err)
+		return
+	}
+	after, err := renderAt(to)
+	if err != nil {
+		respondWith(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	nodes := detailed.TopoDiff(before, after)
+	nodes.Reset = false
+	sort.Sort(byID(nodes.Add))
+	sort.Sort(byID(nodes.Update))
+	sort.Strings(nodes.Remove)
+	respondWith(w, http.StatusOK, APITopologyDiff{
+		From:  from,
+		To:    to,
+		Nodes: nodes,
+		Edges: edgeDiff(before, after),
+	})
+}
+
+type byID []detailed.NodeSummary
+
+func (s byID) Len() int           { return len(s) }
+func (s byID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+
+// edgeDiff gives you the edges to add and remove to get from A to B.
+func edgeDiff(a, b detailed.NodeSummaries) APIEdgeDiff {
+	edges := func(ns detailed.NodeSummaries) map[APIEdge]struct{} {
+		result := map[APIEdge]struct{}{}
+		for id, n := range ns {
+			for _, dst := range n.Adjacency {
+				result[APIEdge{Source: id, Target: dst}] = struct{}{}
+			}
+		}
+		return result
+	}
+	before, after := edges(a), edges(b)
+
+	diff := APIEdgeDiff{}
+	for e := range after {
+		if _, ok := before[e]; !ok {
+			diff.Add = append(diff.Add, e)
+		}
+	}
+	for e := range before {
+		if _, ok := after[e]; !ok {
+			diff.Remove = append(diff.Remove, e)
+		}
+	}
+	sort.Sort(byEdge(diff.Add))
+	sort.Sort(byEdge(diff.Remove))
+	return diff
+}
+
+type byEdge []APIEdge
+
+func (s byEdge) Len() int      { return len(s) }
+func (s byEdge) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byEdge) Less(i, j int) bool {
+	if s[i].Source != s[j].Source {
+		return s[i].Source < s[j].Source
+	}
+	return s[i].Target < s[j].Target
+}
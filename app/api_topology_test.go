@@ -2,15 +2,21 @@ package app_test
 
 import (
 	"fmt"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/ugorji/go/codec"
+	"golang.org/x/net/context"
 
 	"github.com/weaveworks/scope/app"
 	"github.com/weaveworks/scope/render/detailed"
 	"github.com/weaveworks/scope/render/expected"
+	"github.com/weaveworks/scope/report"
 	"github.com/weaveworks/scope/test/fixture"
 )
 
@@ -84,6 +90,41 @@ func TestAPITopologyProcesses(t *testing.T) {
 	}
 }
 
+func TestAPITopologyExport(t *testing.T) {
+	ts := topologyServer()
+	defer ts.Close()
+	is400(t, ts, "/api/topology/hosts?format=foo")
+
+	body := is200(t, ts, "/api/topology/hosts?format=dot")
+	for id := range expected.RenderedHosts {
+		if !strings.Contains(string(body), fmt.Sprintf("%q [label=", id)) {
+			t.Errorf("Expected output to include node: %s, but wasn't found", id)
+		}
+	}
+}
+
+func TestAPITopologyFilterExpression(t *testing.T) {
+	ts := topologyServer()
+	defer ts.Close()
+	is400(t, ts, "/api/topology/containers?filter="+url.QueryEscape(`image =~ "(`))
+	is400(t, ts, "/api/topology?filter="+url.QueryEscape("cpu >"))
+	is400(t, ts, "/api/topology/containers/ws?filter="+url.QueryEscape("not"))
+	is400(t, ts, "/api/topology/containers?filter=%zz")
+	is400(t, ts, "/api/topology?filter=%zz")
+
+	body := getRawJSON(t, ts, "/api/topology/containers?filter="+url.QueryEscape(`image = "`+fixture.ServerContainerImageName+`"`))
+	var topo app.APITopology
+	if err := codec.NewDecoderBytes(body, &codec.JsonHandle{}).Decode(&topo); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := topo.Nodes[fixture.ServerContainerNodeID]; !ok {
+		t.Errorf("Expected output to include node: %s, but wasn't found", fixture.ServerContainerNodeID)
+	}
+	if _, ok := topo.Nodes[fixture.ClientContainerNodeID]; ok {
+		t.Errorf("Expected output to not include node: %s", fixture.ClientContainerNodeID)
+	}
+}
+
 func TestAPITopologyHosts(t *testing.T) {
 	ts := topologyServer()
 	defer ts.Close()
@@ -152,4 +193,60 @@ func TestAPITopologyWebsocket(t *testing.T) {
 	equals(t, 0, len(d.Remove))
 }
 
+// appearingReporter returns an empty report before the cutoff, and
+// the fixture afterwards.
+type appearingReporter struct {
+	app.StaticCollector
+	cutoff time.Time
+}
+
+func (r appearingReporter) Report(_ context.Context, timestamp time.Time) (report.Report, error) {
+	if timestamp.Before(r.cutoff) {
+		return report.MakeReport(), nil
+	}
+	return fixture.Report, nil
+}
+
+func TestAPITopologyDiff(t *testing.T) {
+	cutoff := time.Date(2017, 6, 1, 14, 0, 0, 0, time.UTC)
+	router := mux.NewRouter().SkipClean(true)
+	app.RegisterTopologyRoutes(router, appearingReporter{app.StaticCollector(fixture.Report), cutoff}, nil)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	is404(t, ts, "/api/topology-diff/foobar?from=2017-06-01T13:00:00Z")
+	is400(t, ts, "/api/topology-diff/processes")
+	is400(t, ts, "/api/topology-diff/processes?from=yesterday")
+
+	getDiff := func(from, to string) app.APITopologyDiff {
+		body := getRawJSON(t, ts, "/api/topology-diff/processes?from="+from+"&to="+to)
+		var diff app.APITopologyDiff
+		decoder := codec.NewDecoderBytes(body, &codec.JsonHandle{})
+		if err := decoder.Decode(&diff); err != nil {
+			t.Fatalf("JSON parse error: %s", err)
+		}
+		return diff
+	}
+
+	diff := getDiff("2017-06-01T13:00:00Z", "2017-06-01T15:00:00Z")
+	equals(t, len(expected.RenderedProcesses), len(diff.Nodes.Add))
+	equals(t, 0, len(diff.Nodes.Update))
+	equals(t, 0, len(diff.Nodes.Remove))
+	edges := 0
+	for _, n := range expected.RenderedProcesses {
+		edges += len(n.Adjacency)
+	}
+	equals(t, edges, len(diff.Edges.Add))
+	equals(t, 0, len(diff.Edges.Remove))
+
+	diff = getDiff("2017-06-01T15:00:00Z", "2017-06-01T13:00:00Z")
+	equals(t, 0, len(diff.Nodes.Add))
+	equals(t, len(expected.RenderedProcesses), len(diff.Nodes.Remove))
+	equals(t, edges, len(diff.Edges.Remove))
+
+	diff = getDiff("2017-06-01T15:00:00Z", "2017-06-01T16:00:00Z")
+	equals(t, 0, len(diff.Nodes.Add)+len(diff.Nodes.Update)+len(diff.Nodes.Remove))
+	equals(t, 0, len(diff.Edges.Add)+len(diff.Edges.Remove))
+}
+
 func newu64(value uint64) *uint64 { return &value }
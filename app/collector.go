@@ -251,14 +251,24 @@ func (c StaticCollector) WaitOn(context.Context, chan struct{}) {}
 // implements Reporter.
 func (c StaticCollector) UnWait(context.Context, chan struct{}) {}
 
+// ReplayConfig controls how NewFileCollector replays timestamped
+// reports.
+type ReplayConfig struct {
+	Speed float64       // playback speed relative to the recording; <= 0 means 1
+	Start time.Duration // skip reports recorded earlier than this after the first one
+	End   time.Duration // skip reports recorded later than this after the first one; 0 means no limit
+	Loop  bool          // start over after the last report
+}
+
 // NewFileCollector reads and parses the files at path (a file or
 // directory) as reports.  If there are multiple files, and they all
 // have names representing "nanoseconds since epoch" timestamps,
 // e.g. "1488557088545489008.msgpack.gz", then the collector will
-// return merged reports resulting from replaying the file reports in
-// a loop at a sequence and speed determined by the timestamps.
-// Otherwise the collector always returns the merger of all reports.
-func NewFileCollector(path string, window time.Duration) (Collector, error) {
+// return merged reports resulting from replaying the file reports at
+// a sequence and speed determined by the timestamps and the replay
+// config.  Otherwise the collector always returns the merger of all
+// reports.
+func NewFileCollector(path string, window time.Duration, config ReplayConfig) (Collector, error) {
 	var (
 		timestamps []time.Time
 		reports    []report.Report
@@ -288,8 +298,12 @@ func NewFileCollector(path string, window time.Duration) (Collector, error) {
 		return nil, err
 	}
 	if len(reports) > 1 && allTimestamped {
+		timestamps, reports = replaySelection(timestamps, reports, config)
+		if len(reports) == 0 {
+			return nil, fmt.Errorf("no reports between %v and %v into the recording", config.Start, config.End)
+		}
 		collector := NewCollector(window)
-		go replay(collector, timestamps, reports)
+		go replay(collector, timestamps, reports, config)
 		return collector, nil
 	}
 	return StaticCollector(NewSmartMerger().Merge(reports).Upgrade()), nil
@@ -311,7 +325,30 @@ func timestampFromFilepath(path string) (time.Time, error) {
 	return time.Unix(0, nanosecondsSinceEpoch), nil
 }
 
-func replay(a Adder, timestamps []time.Time, reports []report.Report) {
+// replaySelection returns the reports within the start and end offsets
+// of the config, relative to the first report.
+func replaySelection(timestamps []time.Time, reports []report.Report, config ReplayConfig) ([]time.Time, []report.Report) {
+	var (
+		first              = timestamps[0]
+		selectedTimestamps []time.Time
+		selectedReports    []report.Report
+	)
+	for i, t := range timestamps {
+		offset := t.Sub(first)
+		if offset < config.Start || (config.End > 0 && offset > config.End) {
+			continue
+		}
+		selectedTimestamps = append(selectedTimestamps, t)
+		selectedReports = append(selectedReports, reports[i])
+	}
+	return selectedTimestamps, selectedReports
+}
+
+func replay(a Adder, timestamps []time.Time, reports []report.Report, config ReplayConfig) {
+	speed := config.Speed
+	if speed <= 0 {
+		speed = 1
+	}
 	// calculate delays between report n and n+1
 	l := len(timestamps)
 	delays := make([]time.Duration, l, l)
@@ -324,6 +361,9 @@ func replay(a Adder, timestamps []time.Time, reports []report.Report) {
 	// We don't know how long to wait before looping round, so make a
 	// good guess.
 	delays[l-1] = timestamps[l-1].Sub(timestamps[0]) / time.Duration(l)
+	for i := range delays {
+		delays[i] = time.Duration(float64(delays[i]) / speed)
+	}
 
 	due := time.Now()
 	for {
@@ -335,5 +375,8 @@ func replay(a Adder, timestamps []time.Time, reports []report.Report) {
 				time.Sleep(delay)
 			}
 		}
+		if !config.Loop {
+			return
+		}
 	}
 }
@@ -0,0 +1,285 @@
+package app
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/bluele/gcache"
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/scope/report"
+)
+
+const (
+	diskReportExt    = ".msgpack.gz"
+	diskReportTmpPfx = ".tmp-"
+)
+
+// DiskCollectorConfig has everything we need to make a disk collector.
+type DiskCollectorConfig struct {
+	Path             string        // directory in which reports are stored
+	Window           time.Duration // same as the in-memory collector window
+	MaxAge           time.Duration // reports older than this are deleted; 0 means no limit
+	MaxSize          int64         // total bytes kept on disk; 0 means no limit
+	CompressionLevel int
+}
+
+// diskEntry is a quantised, merged report stored on disk.
+type diskEntry struct {
+	timestamp time.Time
+	path      string
+	size      int64
+}
+
+// diskCollector keeps recent reports in memory, like the plain
+// collector, and additionally persists quantised reports to disk so
+// that it can answer for timestamps in the past.
+type diskCollector struct {
+	Collector // answers for reports within the current window
+	config    DiskCollectorConfig
+	merger    Merger
+	cache     gcache.Cache
+
+	mtx          sync.Mutex
+	entries      []diskEntry // ordered by timestamp
+	size         int64
+	pending      []report.Report
+	pendingStart time.Time
+}
+
+// NewDiskCollector returns a collector which stores reports in the
+// directory config.Path, in the format written by report.WriteToFile,
+// one file per reportQuantisationInterval named after its timestamp
+// in nanoseconds since epoch. Reports already in the directory are
+// picked up, so history survives restarts.
+func NewDiskCollector(config DiskCollectorConfig) (Collector, error) {
+	if err := os.MkdirAll(config.Path, 0755); err != nil {
+		return nil, err
+	}
+	if config.CompressionLevel == 0 {
+		config.CompressionLevel = gzip.DefaultCompression
+	}
+	// Enough to serve a couple of windows without hitting the disk.
+	cacheSize := 2*int(config.Window/reportQuantisationInterval) + 1
+	c := &diskCollector{
+		Collector: NewCollector(config.Window),
+		config:    config,
+		merger:    NewSmartMerger(),
+		cache:     gcache.New(cacheSize).LRU().Build(),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	c.mtx.Lock()
+	c.prune(mtime.Now())
+	c.mtx.Unlock()
+	return c, nil
+}
+
+// load indexes the reports present in the directory.
+func (c *diskCollector) load() error {
+	files, err := filepath.Glob(filepath.Join(c.config.Path, "*"))
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		name := filepath.Base(path)
+		if strings.HasPrefix(name, diskReportTmpPfx) {
+			// Left over from an interrupted write
+			os.Remove(path)
+			continue
+		}
+		if !strings.HasSuffix(name, diskReportExt) {
+			continue
+		}
+		t, err := timestampFromFilepath(path)
+		if err != nil {
+			log.Warnf("Ignoring report file %s: %v", path, err)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		c.entries = append(c.entries, diskEntry{timestamp: t, path: path, size: info.Size()})
+		c.size += info.Size()
+	}
+	sort.Slice(c.entries, func(i, j int) bool {
+		return c.entries[i].timestamp.Before(c.entries[j].timestamp)
+	})
+	return nil
+}
+
+// Add adds a report to the collector's internal state and, once a
+// reportQuantisationInterval has passed, writes the merger of the
+// reports received in that interval to disk. It implements Adder.
+func (c *diskCollector) Add(ctx context.Context, rpt report.Report, buf []byte) error {
+	if err := c.Collector.Add(ctx, rpt, buf); err != nil {
+		return err
+	}
+
+	now := mtime.Now()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if len(c.pending) > 0 && now.Sub(c.pendingStart) >= reportQuantisationInterval {
+		if err := c.flush(); err != nil {
+			log.Errorf("Error storing report on disk: %v", err)
+		}
+		c.prune(now)
+	}
+	if len(c.pending) == 0 {
+		c.pendingStart = now
+	}
+	c.pending = append(c.pending, rpt)
+	return nil
+}
+
+// Close writes the pending reports to disk, so that they aren't lost
+// when the app is stopped.
+func (c *diskCollector) Close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if len(c.pending) == 0 {
+		return nil
+	}
+	return c.flush()
+}
+
+// flush writes the pending reports to disk. Must be called with the
+// lock held.
+func (c *diskCollector) flush() error {
+	rpt := c.merger.Merge(c.pending)
+	start := c.pendingStart
+	c.pending = nil
+
+	name := fmt.Sprintf("%d%s", start.UnixNano(), diskReportExt)
+	path := filepath.Join(c.config.Path, name)
+	tmpPath := filepath.Join(c.config.Path, diskReportTmpPfx+name)
+	if err := rpt.WriteToFile(tmpPath, c.config.CompressionLevel); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	c.entries = append(c.entries, diskEntry{timestamp: start, path: path, size: info.Size()})
+	c.size += info.Size()
+	c.cache.Set(path, rpt)
+	return nil
+}
+
+// prune deletes the oldest reports until the store is within its age
+// and size limits. Must be called with the lock held.
+func (c *diskCollector) prune(now time.Time) {
+	oldest := now.Add(-c.config.MaxAge)
+	for len(c.entries) > 0 {
+		e := c.entries[0]
+		tooOld := c.config.MaxAge > 0 && e.timestamp.Before(oldest)
+		tooBig := c.config.MaxSize > 0 && c.size > c.config.MaxSize
+		if !tooOld && !tooBig {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			log.Warnf("Error removing report %s: %v", e.path, err)
+		}
+		c.cache.Remove(e.path)
+		c.entries = c.entries[1:]
+		c.size -= e.size
+	}
+}
+
+// isLive tells whether a request for timestamp should be answered by
+// the in-memory collector.
+func (c *diskCollector) isLive(timestamp time.Time) bool {
+	return mtime.Now().Sub(timestamp) < reportQuantisationInterval
+}
+
+// inRange returns the stored entries in the window ending at
+// timestamp, and whether the pending reports fall into it too. Must
+// be called with the lock held.
+func (c *diskCollector) inRange(timestamp time.Time) ([]diskEntry, bool) {
+	start := timestamp.Add(-c.config.Window)
+	from := sort.Search(len(c.entries), func(i int) bool {
+		return c.entries[i].timestamp.After(start)
+	})
+	to := sort.Search(len(c.entries), func(i int) bool {
+		return c.entries[i].timestamp.After(timestamp)
+	})
+	pending := len(c.pending) > 0 && c.pendingStart.After(start) && !c.pendingStart.After(timestamp)
+	return c.entries[from:to], pending
+}
+
+// Report returns a merged report over the reports received in the
+// window ending at timestamp. It implements Reporter.
+func (c *diskCollector) Report(ctx context.Context, timestamp time.Time) (report.Report, error) {
+	if c.isLive(timestamp) {
+		return c.Collector.Report(ctx, timestamp)
+	}
+
+	c.mtx.Lock()
+	entries, pending := c.inRange(timestamp)
+	entries = append([]diskEntry{}, entries...)
+	reports := make([]report.Report, 0, len(entries)+len(c.pending))
+	if pending {
+		reports = append(reports, c.pending...)
+	}
+	c.mtx.Unlock()
+
+	for _, e := range entries {
+		rpt, err := c.fetch(e.path)
+		if os.IsNotExist(err) {
+			// Pruned since we looked it up
+			continue
+		} else if err != nil {
+			return report.MakeReport(), err
+		}
+		reports = append(reports, rpt)
+	}
+	for i := range reports {
+		reports[i] = reports[i].Upgrade()
+	}
+	return c.merger.Merge(reports), nil
+}
+
+func (c *diskCollector) fetch(path string) (report.Report, error) {
+	if rpt, err := c.cache.Get(path); err == nil {
+		return rpt.(report.Report), nil
+	}
+	rpt, err := report.MakeFromFile(path)
+	if err != nil {
+		return rpt, err
+	}
+	c.cache.Set(path, rpt)
+	return rpt, nil
+}
+
+// HasReports indicates whether the collector contains reports between
+// timestamp-app.window and timestamp.
+func (c *diskCollector) HasReports(ctx context.Context, timestamp time.Time) (bool, error) {
+	if c.isLive(timestamp) {
+		return c.Collector.HasReports(ctx, timestamp)
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	entries, pending := c.inRange(timestamp)
+	return len(entries) > 0 || pending, nil
+}
+
+// HasHistoricReports indicates whether the collector contains reports
+// older than now-app.window.
+func (c *diskCollector) HasHistoricReports() bool {
+	return true
+}
@@ -0,0 +1,124 @@
+package app_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func TestDiskCollector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scope-disk-collector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(1500000000, 0)
+	mtime.NowForce(now)
+	defer mtime.NowReset()
+
+	ctx := context.Background()
+	config := app.DiskCollectorConfig{Path: dir, Window: 10 * time.Second}
+	c, err := app.NewDiskCollector(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.HasHistoricReports() {
+		t.Error("Expected disk collector to have historic reports")
+	}
+
+	r1 := report.MakeReport()
+	r1.Endpoint.AddNode(report.MakeNode("foo"))
+	r2 := report.MakeReport()
+	r2.Endpoint.AddNode(report.MakeNode("bar"))
+	r3 := report.MakeReport()
+	r3.Endpoint.AddNode(report.MakeNode("baz"))
+
+	// r1 and r2 end up in separate quanta on disk; r3 triggers the
+	// write of r2.
+	c.Add(ctx, r1, nil)
+	mtime.NowForce(now.Add(time.Minute))
+	c.Add(ctx, r2, nil)
+	mtime.NowForce(now.Add(2 * time.Minute))
+	c.Add(ctx, r3, nil)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.msgpack.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files on disk, got %v", files)
+	}
+
+	for _, tc := range []struct {
+		at   time.Time
+		want report.Report
+	}{
+		{now.Add(time.Second), r1},
+		{now.Add(time.Minute + time.Second), r2},
+		{now.Add(2*time.Minute + time.Second), r3},
+	} {
+		mtime.NowForce(now.Add(2*time.Minute + time.Second))
+		have, err := c.Report(ctx, tc.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tc.want.Endpoint.Nodes, have.Endpoint.Nodes) {
+			t.Errorf("At %v: %s", tc.at, test.Diff(tc.want.Endpoint.Nodes, have.Endpoint.Nodes))
+		}
+	}
+
+	// Nothing was stored that long ago
+	if ok, err := c.HasReports(ctx, now.Add(-time.Hour)); err != nil || ok {
+		t.Errorf("Expected no reports an hour ago, got %v (%v)", ok, err)
+	}
+
+	// Closing the store writes the pending r3
+	if err := c.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, err = filepath.Glob(filepath.Join(dir, "*.msgpack.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files on disk after closing, got %v", files)
+	}
+
+	// Reopening the store finds the stored reports, and prunes the
+	// oldest beyond MaxAge.
+	config.MaxAge = time.Minute + time.Second
+	c, err = app.NewDiskCollector(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.HasReports(ctx, now.Add(time.Second)); err != nil || ok {
+		t.Errorf("Expected r1 to be pruned, got %v (%v)", ok, err)
+	}
+	have, err := c.Report(ctx, now.Add(time.Minute+time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r2.Endpoint.Nodes, have.Endpoint.Nodes) {
+		t.Error(test.Diff(r2.Endpoint.Nodes, have.Endpoint.Nodes))
+	}
+	mtime.NowForce(now.Add(2*time.Minute + 10*time.Second))
+	have, err = c.Report(ctx, now.Add(2*time.Minute+time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r3.Endpoint.Nodes, have.Endpoint.Nodes) {
+		t.Error(test.Diff(r3.Endpoint.Nodes, have.Endpoint.Nodes))
+	}
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/scope/report"
+)
+
+// Recorder is an Adder which writes every report it receives to a
+// directory, named after the time of receipt, such that the
+// directory can be replayed with NewFileCollector.
+type Recorder struct {
+	mtx  sync.Mutex
+	dir  string
+	last int64
+}
+
+// NewRecorder returns a Recorder writing to dir, which is created if
+// needed.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir}, nil
+}
+
+// Add writes the report to a file. buf, if given, must be the report
+// as gzip'd msgpack, and is written as-is. It implements Adder.
+func (r *Recorder) Add(_ context.Context, rpt report.Report, buf []byte) error {
+	if buf == nil {
+		b := &bytes.Buffer{}
+		if err := rpt.WriteBinary(b, gzip.DefaultCompression); err != nil {
+			return err
+		}
+		buf = b.Bytes()
+	}
+
+	r.mtx.Lock()
+	// Reports received within the same nanosecond must not
+	// overwrite each other.
+	ts := mtime.Now().UnixNano()
+	if ts <= r.last {
+		ts = r.last + 1
+	}
+	r.last = ts
+	r.mtx.Unlock()
+
+	path := filepath.Join(r.dir, fmt.Sprintf("%d%s", ts, diskReportExt))
+	return ioutil.WriteFile(path, buf, 0644)
+}
+
+// recordingCollector is a Collector which also hands every report it
+// is given to a recorder.
+type recordingCollector struct {
+	Collector
+	recorder Adder
+}
+
+// NewRecordingCollector returns a Collector which adds reports to c,
+// and records them, as received, with recorder.
+func NewRecordingCollector(c Collector, recorder Adder) Collector {
+	return &recordingCollector{Collector: c, recorder: recorder}
+}
+
+// Add implements Adder. Failing to record a report is logged, but
+// doesn't fail the publisher of the report.
+func (c *recordingCollector) Add(ctx context.Context, rpt report.Report, buf []byte) error {
+	if err := c.Collector.Add(ctx, rpt, buf); err != nil {
+		return err
+	}
+	if err := c.recorder.Add(ctx, rpt, buf); err != nil {
+		log.Errorf("Error recording report: %v", err)
+	}
+	return nil
+}
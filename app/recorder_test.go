@@ -0,0 +1,83 @@
+package app_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test"
+)
+
+func TestRecorderReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scope-recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(1500000000, 0)
+	mtime.NowForce(now)
+	defer mtime.NowReset()
+
+	ctx := context.Background()
+	r, err := app.NewRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := app.NewCollector(time.Minute)
+	rc := app.NewRecordingCollector(live, r)
+	for _, id := range []string{"foo", "bar", "baz"} {
+		rpt := report.MakeReport()
+		rpt.Endpoint.AddNode(report.MakeNode(id))
+		if err := rc.Add(ctx, rpt, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The reports are still collected as usual
+	if rpt, err := live.Report(ctx, now); err != nil {
+		t.Fatal(err)
+	} else if len(rpt.Endpoint.Nodes) != 3 {
+		t.Errorf("Expected 3 collected endpoints, got %v", rpt.Endpoint.Nodes)
+	}
+
+	// Reports recorded at the same time must not overwrite each other
+	files, err := filepath.Glob(filepath.Join(dir, "*.msgpack.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 recorded reports, got %v", files)
+	}
+	mtime.NowReset()
+
+	// Skip the first report, and don't loop
+	c, err := app.NewFileCollector(dir, time.Minute, app.ReplayConfig{Speed: 1000, Start: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Poll(t, 100*time.Millisecond, []string{"bar", "baz"}, func() interface{} {
+		rpt, err := c.Report(ctx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := []string{}
+		for id := range rpt.Endpoint.Nodes {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return ids
+	})
+
+	if _, err := app.NewFileCollector(dir, time.Minute, app.ReplayConfig{Start: time.Hour}); err == nil {
+		t.Error("Expected an error replaying an empty selection")
+	}
+}
@@ -105,6 +105,19 @@ func RegisterTopologyRoutes(router *mux.Router, r Reporter, capabilities map[str
 		HandleFunc("/api/topology/{topology}/ws",
 			requestContextDecorator(captureReporter(r, handleWebsocket))). // NB not gzip!
 		Name("api_topology_topology_ws")
+	// Not under /api/topology/{topology}, where it would shadow a node with ID "diff"
+	get.
+		HandleFunc("/api/topology-diff/{topology}",
+			gzipHandler(requestContextDecorator(captureReporter(r, handleDiff)))).
+		Name("api_topology_diff_topology")
+	get.
+		MatcherFunc(URLMatcher("/api/topology/pods/policy/{namespace}")).HandlerFunc(
+		gzipHandler(requestContextDecorator(captureReporter(r, handleNamespacePolicy)))).
+		Name("api_topology_pods_policy_namespace")
+	get.
+		MatcherFunc(URLMatcher("/api/topology/pods/{id}/policy")).HandlerFunc(
+		gzipHandler(requestContextDecorator(captureReporter(r, handlePodPolicy)))).
+		Name("api_topology_pods_id_policy")
 	get.
 		MatcherFunc(URLMatcher("/api/topology/{topology}/{id}")).HandlerFunc(
 		gzipHandler(requestContextDecorator(topologyRegistry.captureRenderer(r, handleNode)))).
@@ -113,6 +126,8 @@ func RegisterTopologyRoutes(router *mux.Router, r Reporter, capabilities map[str
 		gzipHandler(requestContextDecorator(makeRawReportHandler(r))))
 	get.HandleFunc("/api/probes",
 		gzipHandler(requestContextDecorator(makeProbeHandler(r))))
+	get.HandleFunc("/api/events",
+		gzipHandler(requestContextDecorator(captureReporter(r, handleEvents))))
 }
 
 // RegisterReportPostHandler registers the handler for report submission
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/scope/render"
+	"github.com/weaveworks/scope/report"
+)
+
+// Parent topologies exported as labels on node metrics.
+var topologyMetricsParents = []string{report.Host, report.Container, report.Pod}
+
+var (
+	nodeMetricDesc = prometheus.NewDesc(
+		"scope_node_metric",
+		"Latest value of a metric reported on a node of a rendered topology.",
+		append([]string{"topology", "node_id", "metric"}, topologyMetricsParents...),
+		nil,
+	)
+	topologyNodesDesc = prometheus.NewDesc(
+		"scope_topology_nodes",
+		"Number of nodes in a rendered topology.",
+		[]string{"topology"},
+		nil,
+	)
+	topologyEdgesDesc = prometheus.NewDesc(
+		"scope_topology_edges",
+		"Number of edges between the nodes of a rendered topology.",
+		[]string{"topology"},
+		nil,
+	)
+)
+
+// topologyMetrics is a prometheus.Collector which renders every
+// topology from the current report on each scrape.
+type topologyMetrics struct {
+	reporter Reporter
+	registry *Registry
+}
+
+// NewTopologyMetrics returns a prometheus.Collector exporting the nodes,
+// edges and node metrics of the topologies rendered from rep.
+func NewTopologyMetrics(rep Reporter) prometheus.Collector {
+	return topologyMetrics{reporter: rep, registry: topologyRegistry}
+}
+
+// Describe implements prometheus.Collector
+func (t topologyMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nodeMetricDesc
+	ch <- topologyNodesDesc
+	ch <- topologyEdgesDesc
+}
+
+// Collect implements prometheus.Collector
+func (t topologyMetrics) Collect(ch chan<- prometheus.Metric) {
+	rpt, err := t.reporter.Report(context.Background(), mtime.Now())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(topologyNodesDesc, err)
+		return
+	}
+	// Only gather the IDs while walking the registry: rendering takes the
+	// registry lock again, and would hold it for every render.
+	topologyIDs := []string{}
+	t.registry.walk(func(desc APITopologyDesc) {
+		topologyIDs = append(topologyIDs, desc.id)
+		for _, sub := range desc.SubTopologies {
+			topologyIDs = append(topologyIDs, sub.id)
+		}
+	})
+	for _, topologyID := range topologyIDs {
+		renderer, filter, err := t.registry.RendererForTopology(topologyID, url.Values{}, rpt)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(topologyNodesDesc, err)
+			continue
+		}
+		collectTopologyMetrics(ch, topologyID, render.Render(rpt, renderer, filter).Nodes)
+	}
+}
+
+func collectTopologyMetrics(ch chan<- prometheus.Metric, topologyID string, nodes report.Nodes) {
+	edges := 0
+	for _, n := range nodes {
+		edges += len(n.Adjacency)
+		if len(n.Metrics) == 0 {
+			continue
+		}
+		labels := []string{topologyID, n.ID, ""}
+		for _, parent := range topologyMetricsParents {
+			ids, _ := n.Parents.Lookup(parent)
+			labels = append(labels, strings.Join(ids, ","))
+		}
+		for key, metric := range n.Metrics {
+			sample, ok := metric.LastSample()
+			if !ok {
+				continue
+			}
+			labels[2] = key
+			ch <- prometheus.MustNewConstMetric(nodeMetricDesc, prometheus.GaugeValue, sample.Value, labels...)
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(topologyNodesDesc, prometheus.GaugeValue, float64(len(nodes)), topologyID)
+	ch <- prometheus.MustNewConstMetric(topologyEdgesDesc, prometheus.GaugeValue, float64(edges), topologyID)
+}
@@ -0,0 +1,86 @@
+package app_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/probe/process"
+	"github.com/weaveworks/scope/render/expected"
+	"github.com/weaveworks/scope/test/fixture"
+)
+
+func collectTopologyMetrics(t *testing.T) map[string][]*dto.Metric {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		app.NewTopologyMetrics(app.StaticCollector(fixture.Report)).Collect(ch)
+		close(ch)
+	}()
+	result := map[string][]*dto.Metric{}
+	for m := range ch {
+		var out dto.Metric
+		if err := m.Write(&out); err != nil {
+			t.Fatal(err)
+		}
+		result[m.Desc().String()] = append(result[m.Desc().String()], &out)
+	}
+	return result
+}
+
+func labelsOf(m *dto.Metric) map[string]string {
+	labels := map[string]string{}
+	for _, lp := range m.GetLabel() {
+		labels[lp.GetName()] = lp.GetValue()
+	}
+	return labels
+}
+
+func TestTopologyMetrics(t *testing.T) {
+	var (
+		metrics     = collectTopologyMetrics(t)
+		nodeMetrics []*dto.Metric
+		nodeCounts  []*dto.Metric
+	)
+	for desc, ms := range metrics {
+		switch {
+		case strings.Contains(desc, `"scope_node_metric"`):
+			nodeMetrics = ms
+		case strings.Contains(desc, `"scope_topology_nodes"`):
+			nodeCounts = ms
+		}
+	}
+
+	found := false
+	for _, m := range nodeCounts {
+		if labelsOf(m)["topology"] == "processes" {
+			found = true
+			if want, have := float64(len(expected.RenderedProcesses)), m.GetGauge().GetValue(); want != have {
+				t.Errorf("Expected %v processes, got %v", want, have)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a node count for the processes topology")
+	}
+
+	found = false
+	for _, m := range nodeMetrics {
+		labels := labelsOf(m)
+		if labels["topology"] != "processes" || labels["node_id"] != fixture.ClientProcess1NodeID || labels["metric"] != process.CPUUsage {
+			continue
+		}
+		found = true
+		if labels["host"] != fixture.ClientHostNodeID || labels["container"] != fixture.ClientContainerNodeID {
+			t.Errorf("Unexpected parent labels: %v", labels)
+		}
+		if want, have := 0.01, m.GetGauge().GetValue(); want != have {
+			t.Errorf("Expected %v, got %v", want, have)
+		}
+	}
+	if !found {
+		t.Error("Expected the CPU usage of client process 1 to be exported")
+	}
+}
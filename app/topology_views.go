@@ -0,0 +1,184 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/probe/kubernetes"
+	"github.com/weaveworks/scope/render"
+)
+
+// TopologyView is a user-defined topology: the nodes of an existing
+// topology, optionally grouped by a key, with extra filters.
+type TopologyView struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Parent, if set, is the topology under which the view is listed.
+	Parent string `json:"parent,omitempty"`
+	Rank   int    `json:"rank,omitempty"`
+	// Base is the topology the view renders the nodes of.
+	Base string `json:"base"`
+	// GroupBy is a Latest key to group the nodes by; GroupByLabel is a
+	// docker or kubernetes label to group the nodes by.
+	GroupBy      string                    `json:"group_by,omitempty"`
+	GroupByLabel string                    `json:"group_by_label,omitempty"`
+	Filters      []TopologyViewFilterGroup `json:"filters,omitempty"`
+}
+
+// TopologyViewFilterGroup is an APITopologyOptionGroup of a TopologyView.
+type TopologyViewFilterGroup struct {
+	ID      string               `json:"id"`
+	Default string               `json:"default"`
+	Options []TopologyViewFilter `json:"options"`
+}
+
+// TopologyViewFilter is an APITopologyOption of a TopologyView. It
+// matches nodes matching all of its predicates; with no predicates, it
+// matches every node.
+type TopologyViewFilter struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	// Predicates: a docker or kubernetes label as key=value, a
+	// namespace, and a Latest key as key=value.
+	MatchLabel     string `json:"match_label,omitempty"`
+	MatchNamespace string `json:"match_namespace,omitempty"`
+	MatchMetadata  string `json:"match_metadata,omitempty"`
+}
+
+type topologyViewsFile struct {
+	Views []TopologyView `json:"views"`
+}
+
+// LoadTopologyViews reads topology views from a YAML or JSON file, of
+// the form {"views": [...]}.
+func LoadTopologyViews(path string) ([]TopologyView, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file topologyViewsFile
+	if err := yaml.Unmarshal(buf, &file); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return file.Views, nil
+}
+
+// AddTopologyViews adds views to the default Registry (topologyRegistry)
+func AddTopologyViews(views ...TopologyView) error {
+	return topologyRegistry.AddTopologyViews(views...)
+}
+
+// AddTopologyViews adds views to this Registry. Views must be added
+// after the topologies they are based on, or listed under.
+func (r *Registry) AddTopologyViews(views ...TopologyView) error {
+	for _, view := range views {
+		desc, err := r.makeTopologyViewDesc(view)
+		if err != nil {
+			return fmt.Errorf("topology view %q: %v", view.ID, err)
+		}
+		r.Add(desc)
+	}
+	return nil
+}
+
+func (r *Registry) makeTopologyViewDesc(view TopologyView) (APITopologyDesc, error) {
+	if view.ID == "" || view.Name == "" {
+		return APITopologyDesc{}, fmt.Errorf("id and name are required")
+	}
+	if _, ok := r.get(view.ID); ok {
+		return APITopologyDesc{}, fmt.Errorf("topology already exists")
+	}
+	if view.Parent != "" {
+		if parent, ok := r.get(view.Parent); !ok || parent.parent != "" {
+			return APITopologyDesc{}, fmt.Errorf("parent %q is not a top-level topology", view.Parent)
+		}
+	}
+	base, ok := r.get(view.Base)
+	if !ok {
+		return APITopologyDesc{}, fmt.Errorf("unknown base topology %q", view.Base)
+	}
+
+	var groupBy []string
+	switch {
+	case view.GroupBy != "" && view.GroupByLabel != "":
+		return APITopologyDesc{}, fmt.Errorf("only one of group_by and group_by_label may be set")
+	case view.GroupBy != "":
+		groupBy = []string{view.GroupBy}
+	case view.GroupByLabel != "":
+		groupBy = []string{docker.LabelPrefix + view.GroupByLabel, kubernetes.LabelPrefix + view.GroupByLabel}
+	}
+
+	options := append([]APITopologyOptionGroup{}, base.Options...)
+	for _, group := range view.Filters {
+		optionGroup := APITopologyOptionGroup{ID: group.ID, Default: group.Default}
+		for _, option := range group.Options {
+			filter, err := makeTopologyViewFilter(option)
+			if err != nil {
+				return APITopologyDesc{}, fmt.Errorf("filter %q: %v", group.ID, err)
+			}
+			optionGroup.Options = append(optionGroup.Options, APITopologyOption{
+				Value: option.Value, Label: option.Label, filter: filter, filterPseudo: false,
+			})
+		}
+		options = append(options, optionGroup)
+	}
+
+	renderer := base.renderer
+	if len(groupBy) > 0 {
+		renderer = render.MakeMap(render.MapGroupBy(groupBy...), renderer)
+		// Filters are written against the nodes of the base topology,
+		// so apply them to the members of each group.
+		for i, group := range options {
+			groupOptions := make([]APITopologyOption, len(group.Options))
+			for j, option := range group.Options {
+				if option.filter != nil {
+					option.filter = render.AnyGroupMember(option.filter)
+				}
+				groupOptions[j] = option
+			}
+			options[i].Options = groupOptions
+		}
+	}
+
+	return APITopologyDesc{
+		id:          view.ID,
+		parent:      view.Parent,
+		renderer:    renderer,
+		Name:        view.Name,
+		Rank:        view.Rank,
+		Options:     options,
+		HideIfEmpty: base.HideIfEmpty,
+	}, nil
+}
+
+func makeTopologyViewFilter(option TopologyViewFilter) (render.FilterFunc, error) {
+	filters := []render.FilterFunc{}
+	if option.MatchLabel != "" {
+		kv := strings.SplitN(option.MatchLabel, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("match_label must be of the form key=value, got %q", option.MatchLabel)
+		}
+		filters = append(filters, render.AnyFilterFunc(
+			render.HasLatest(docker.LabelPrefix+kv[0], kv[1]),
+			render.HasLatest(kubernetes.LabelPrefix+kv[0], kv[1]),
+		))
+	}
+	if option.MatchNamespace != "" {
+		filters = append(filters, render.IsNamespace(option.MatchNamespace))
+	}
+	if option.MatchMetadata != "" {
+		kv := strings.SplitN(option.MatchMetadata, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("match_metadata must be of the form key=value, got %q", option.MatchMetadata)
+		}
+		filters = append(filters, render.HasLatest(kv[0], kv[1]))
+	}
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return render.ComposeFilterFuncs(filters...), nil
+}
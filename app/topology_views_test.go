@@ -0,0 +1,91 @@
+package app_test
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/render"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/fixture"
+)
+
+var topologyViewsYAML = `
+views:
+- id: containers-by-foo1
+  name: by foo1
+  parent: containers
+  base: containers
+  group_by_label: foo1
+- id: labelled-containers
+  name: Labelled containers
+  base: containers
+  filters:
+  - id: label
+    default: all
+    options:
+    - value: all
+      label: All
+    - value: one
+      label: Label one
+      match_label: ` + fixture.TestLabelKey1 + `=` + fixture.ApplicationLabelValue1 + `
+`
+
+func TestTopologyViews(t *testing.T) {
+	f, err := ioutil.TempFile("", "scope-topology-views")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(topologyViewsYAML); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	views, err := app.LoadTopologyViews(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := app.MakeRegistry()
+	if err := registry.AddTopologyViews(views...); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.AddTopologyViews(views[0]); err == nil {
+		t.Error("Expected an error adding a view twice")
+	}
+	if err := registry.AddTopologyViews(app.TopologyView{ID: "foo", Name: "Foo", Base: "bar"}); err == nil {
+		t.Error("Expected an error adding a view of an unknown topology")
+	}
+
+	// Containers grouped by label
+	renderer, filter, err := registry.RendererForTopology("containers-by-foo1", url.Values{}, fixture.Report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := render.Render(fixture.Report, renderer, filter).Nodes
+	group, ok := nodes["bar1"]
+	if !ok {
+		t.Fatalf("Expected a group for foo1=bar1, got %v", nodes)
+	}
+	if want := render.MakeGroupNodeTopology(report.Container, "docker_label_foo1"); group.Topology != want {
+		t.Errorf("Expected topology %s, got %s", want, group.Topology)
+	}
+	if _, ok := nodes[fixture.ClientContainerNodeID]; ok {
+		t.Error("Expected containers to be grouped")
+	}
+
+	// Containers filtered by label
+	renderer, filter, err = registry.RendererForTopology("labelled-containers", url.Values{"label": []string{"one"}}, fixture.Report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes = render.Render(fixture.Report, renderer, filter).Nodes
+	if _, ok := nodes[fixture.ClientContainerNodeID]; !ok {
+		t.Error("Expected the client container to match")
+	}
+	if _, ok := nodes[fixture.ServerContainerNodeID]; ok {
+		t.Error("Expected the server container to be filtered out")
+	}
+}
@@ -0,0 +1,40 @@
+// Render a topology from a report file, and write it as a graph document.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/render"
+	"github.com/weaveworks/scope/render/detailed"
+	"github.com/weaveworks/scope/render/export"
+	"github.com/weaveworks/scope/report"
+)
+
+func main() {
+	var (
+		topologyID = flag.String("topology", "containers", "Topology to render")
+		format     = flag.String("format", export.DOT, "Graph format: dot, graphml or jgf")
+	)
+	flag.Parse()
+
+	if len(flag.Args()) != 1 {
+		log.Fatal("usage: exportgraph [-topology processes] [-format dot|graphml|jgf] src.(json|msgpack)[.gz]")
+	}
+
+	rpt, err := report.MakeFromFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	renderer, filter, err := app.MakeRegistry().RendererForTopology(*topologyID, url.Values{}, rpt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	nodes := detailed.Summaries(detailed.RenderContext{Report: rpt}, render.Render(rpt, renderer, filter).Nodes)
+	if err := export.Write(os.Stdout, *format, *topologyID, nodes); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,393 @@
+package cri
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// The messages of the Kubernetes Container Runtime Interface (package
+// runtime.v1 of k8s.io/cri-api) used by the probe. Only the fields we
+// need are declared; the others are skipped when decoding. Field
+// numbers must match those of api.proto.
+
+// containerState is the state of a container.
+type containerState int32
+
+// The states of a container.
+const (
+	containerCreated containerState = 0
+	containerRunning containerState = 1
+	containerExited  containerState = 2
+	containerUnknown containerState = 3
+)
+
+// namespaceMode is the kind of a namespace of a pod sandbox.
+type namespaceMode int32
+
+// namespaceNode is the mode of the namespaces shared with the node.
+const namespaceNode namespaceMode = 2
+
+// versionRequest is the request of RuntimeService.Version.
+type versionRequest struct {
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+// versionResponse is the response of RuntimeService.Version.
+type versionResponse struct {
+	Version           string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	RuntimeName       string `protobuf:"bytes,2,opt,name=runtime_name,json=runtimeName,proto3" json:"runtime_name,omitempty"`
+	RuntimeVersion    string `protobuf:"bytes,3,opt,name=runtime_version,json=runtimeVersion,proto3" json:"runtime_version,omitempty"`
+	RuntimeAPIVersion string `protobuf:"bytes,4,opt,name=runtime_api_version,json=runtimeApiVersion,proto3" json:"runtime_api_version,omitempty"`
+}
+
+// containerMetadata holds the name of a container within its pod.
+type containerMetadata struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Attempt uint32 `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+}
+
+// imageSpec is the reference to an image.
+type imageSpec struct {
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+}
+
+// containerStateValue wraps a containerState.
+type containerStateValue struct {
+	State containerState `protobuf:"varint,1,opt,name=state,proto3,enum=runtime.v1.ContainerState" json:"state,omitempty"`
+}
+
+// containerFilter selects the containers to list.
+type containerFilter struct {
+	ID            string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State         *containerStateValue `protobuf:"bytes,2,opt,name=state" json:"state,omitempty"`
+	PodSandboxID  string               `protobuf:"bytes,3,opt,name=pod_sandbox_id,json=podSandboxId,proto3" json:"pod_sandbox_id,omitempty"`
+	LabelSelector map[string]string    `protobuf:"bytes,4,rep,name=label_selector,json=labelSelector" json:"label_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+// listContainersRequest is the request of RuntimeService.ListContainers.
+type listContainersRequest struct {
+	Filter *containerFilter `protobuf:"bytes,1,opt,name=filter" json:"filter,omitempty"`
+}
+
+// runtimeContainer is a container as listed by RuntimeService.ListContainers.
+type runtimeContainer struct {
+	ID           string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PodSandboxID string             `protobuf:"bytes,2,opt,name=pod_sandbox_id,json=podSandboxId,proto3" json:"pod_sandbox_id,omitempty"`
+	Metadata     *containerMetadata `protobuf:"bytes,3,opt,name=metadata" json:"metadata,omitempty"`
+	Image        *imageSpec         `protobuf:"bytes,4,opt,name=image" json:"image,omitempty"`
+	ImageRef     string             `protobuf:"bytes,5,opt,name=image_ref,json=imageRef,proto3" json:"image_ref,omitempty"`
+	State        containerState     `protobuf:"varint,6,opt,name=state,proto3,enum=runtime.v1.ContainerState" json:"state,omitempty"`
+	CreatedAt    int64              `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Labels       map[string]string  `protobuf:"bytes,8,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+// listContainersResponse is the response of
+// RuntimeService.ListContainers.
+type listContainersResponse struct {
+	Containers []*runtimeContainer `protobuf:"bytes,1,rep,name=containers" json:"containers,omitempty"`
+}
+
+// containerStatusRequest is the request of
+// RuntimeService.ContainerStatus.
+type containerStatusRequest struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	Verbose     bool   `protobuf:"varint,2,opt,name=verbose,proto3" json:"verbose,omitempty"`
+}
+
+// containerStatus is the status of a container.
+type containerStatus struct {
+	ID         string             `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Metadata   *containerMetadata `protobuf:"bytes,2,opt,name=metadata" json:"metadata,omitempty"`
+	State      containerState     `protobuf:"varint,3,opt,name=state,proto3,enum=runtime.v1.ContainerState" json:"state,omitempty"`
+	CreatedAt  int64              `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt  int64              `protobuf:"varint,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt int64              `protobuf:"varint,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	ExitCode   int32              `protobuf:"varint,7,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Image      *imageSpec         `protobuf:"bytes,8,opt,name=image" json:"image,omitempty"`
+	ImageRef   string             `protobuf:"bytes,9,opt,name=image_ref,json=imageRef,proto3" json:"image_ref,omitempty"`
+	Reason     string             `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message    string             `protobuf:"bytes,11,opt,name=message,proto3" json:"message,omitempty"`
+	Labels     map[string]string  `protobuf:"bytes,12,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ImageID    string             `protobuf:"bytes,17,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+}
+
+// containerStatusResponse is the response of
+// RuntimeService.ContainerStatus.
+type containerStatusResponse struct {
+	Status *containerStatus `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	// Runtime specific details, returned when verbose
+	Info map[string]string `protobuf:"bytes,2,rep,name=info" json:"info,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+// podSandboxStatusRequest is the request of
+// RuntimeService.PodSandboxStatus.
+type podSandboxStatusRequest struct {
+	PodSandboxID string `protobuf:"bytes,1,opt,name=pod_sandbox_id,json=podSandboxId,proto3" json:"pod_sandbox_id,omitempty"`
+}
+
+// podIP is an additional IP of a pod sandbox.
+type podIP struct {
+	IP string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+}
+
+// podSandboxNetworkStatus holds the IPs of a pod sandbox.
+type podSandboxNetworkStatus struct {
+	IP            string   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	AdditionalIPs []*podIP `protobuf:"bytes,2,rep,name=additional_ips,json=additionalIps" json:"additional_ips,omitempty"`
+}
+
+// namespaceOption holds the modes of the namespaces of a pod sandbox.
+type namespaceOption struct {
+	Network namespaceMode `protobuf:"varint,1,opt,name=network,proto3,enum=runtime.v1.NamespaceMode" json:"network,omitempty"`
+}
+
+// namespace holds the namespaces of a pod sandbox.
+type namespace struct {
+	Options *namespaceOption `protobuf:"bytes,2,opt,name=options" json:"options,omitempty"`
+}
+
+// linuxPodSandboxStatus is the Linux specific status of a pod sandbox.
+type linuxPodSandboxStatus struct {
+	Namespaces *namespace `protobuf:"bytes,1,opt,name=namespaces" json:"namespaces,omitempty"`
+}
+
+// podSandboxStatus is the status of a pod sandbox.
+type podSandboxStatus struct {
+	ID      string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Network *podSandboxNetworkStatus `protobuf:"bytes,5,opt,name=network" json:"network,omitempty"`
+	Linux   *linuxPodSandboxStatus   `protobuf:"bytes,6,opt,name=linux" json:"linux,omitempty"`
+}
+
+// podSandboxStatusResponse is the response of
+// RuntimeService.PodSandboxStatus.
+type podSandboxStatusResponse struct {
+	Status *podSandboxStatus `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+}
+
+// uint64Value wraps a uint64, telling unset values apart.
+type uint64Value struct {
+	Value uint64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+// cpuUsage is the CPU used by a container.
+type cpuUsage struct {
+	Timestamp            int64        `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	UsageCoreNanoSeconds *uint64Value `protobuf:"bytes,2,opt,name=usage_core_nano_seconds,json=usageCoreNanoSeconds" json:"usage_core_nano_seconds,omitempty"`
+}
+
+// memoryUsage is the memory used by a container.
+type memoryUsage struct {
+	Timestamp       int64        `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	WorkingSetBytes *uint64Value `protobuf:"bytes,2,opt,name=working_set_bytes,json=workingSetBytes" json:"working_set_bytes,omitempty"`
+	AvailableBytes  *uint64Value `protobuf:"bytes,3,opt,name=available_bytes,json=availableBytes" json:"available_bytes,omitempty"`
+}
+
+// containerStats are the resources used by a container.
+type containerStats struct {
+	CPU    *cpuUsage    `protobuf:"bytes,2,opt,name=cpu" json:"cpu,omitempty"`
+	Memory *memoryUsage `protobuf:"bytes,3,opt,name=memory" json:"memory,omitempty"`
+}
+
+// containerStatsRequest is the request of RuntimeService.ContainerStats.
+type containerStatsRequest struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+}
+
+// containerStatsResponse is the response of
+// RuntimeService.ContainerStats.
+type containerStatsResponse struct {
+	Stats *containerStats `protobuf:"bytes,1,opt,name=stats" json:"stats,omitempty"`
+}
+
+// startContainerRequest is the request of RuntimeService.StartContainer.
+type startContainerRequest struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+}
+
+// startContainerResponse is the response of
+// RuntimeService.StartContainer.
+type startContainerResponse struct{}
+
+// stopContainerRequest is the request of RuntimeService.StopContainer.
+type stopContainerRequest struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	// Seconds to wait before killing the container
+	Timeout int64 `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+// stopContainerResponse is the response of RuntimeService.StopContainer.
+type stopContainerResponse struct{}
+
+// removeContainerRequest is the request of
+// RuntimeService.RemoveContainer.
+type removeContainerRequest struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+}
+
+// removeContainerResponse is the response of
+// RuntimeService.RemoveContainer.
+type removeContainerResponse struct{}
+
+// execRequest is the request of RuntimeService.Exec.
+type execRequest struct {
+	ContainerID string   `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	Cmd         []string `protobuf:"bytes,2,rep,name=cmd" json:"cmd,omitempty"`
+	Tty         bool     `protobuf:"varint,3,opt,name=tty,proto3" json:"tty,omitempty"`
+	Stdin       bool     `protobuf:"varint,4,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	Stdout      bool     `protobuf:"varint,5,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr      bool     `protobuf:"varint,6,opt,name=stderr,proto3" json:"stderr,omitempty"`
+}
+
+// execResponse is the response of RuntimeService.Exec.
+type execResponse struct {
+	// URL of the streaming server to connect to
+	URL string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+// attachRequest is the request of RuntimeService.Attach.
+type attachRequest struct {
+	ContainerID string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	Stdin       bool   `protobuf:"varint,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	Tty         bool   `protobuf:"varint,3,opt,name=tty,proto3" json:"tty,omitempty"`
+	Stdout      bool   `protobuf:"varint,4,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr      bool   `protobuf:"varint,5,opt,name=stderr,proto3" json:"stderr,omitempty"`
+}
+
+// attachResponse is the response of RuntimeService.Attach.
+type attachResponse struct {
+	// URL of the streaming server to connect to
+	URL string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+// listImagesRequest is the request of ImageService.ListImages.
+type listImagesRequest struct{}
+
+// runtimeImage is an image as listed by ImageService.ListImages.
+type runtimeImage struct {
+	ID          string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RepoTags    []string `protobuf:"bytes,2,rep,name=repo_tags,json=repoTags" json:"repo_tags,omitempty"`
+	RepoDigests []string `protobuf:"bytes,3,rep,name=repo_digests,json=repoDigests" json:"repo_digests,omitempty"`
+	Size        uint64   `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+// listImagesResponse is the response of ImageService.ListImages.
+type listImagesResponse struct {
+	Images []*runtimeImage `protobuf:"bytes,1,rep,name=images" json:"images,omitempty"`
+}
+
+func (m *versionRequest) Reset()                   { *m = versionRequest{} }
+func (m *versionRequest) String() string           { return proto.CompactTextString(m) }
+func (*versionRequest) ProtoMessage()              {}
+func (m *versionResponse) Reset()                  { *m = versionResponse{} }
+func (m *versionResponse) String() string          { return proto.CompactTextString(m) }
+func (*versionResponse) ProtoMessage()             {}
+func (m *containerMetadata) Reset()                { *m = containerMetadata{} }
+func (m *containerMetadata) String() string        { return proto.CompactTextString(m) }
+func (*containerMetadata) ProtoMessage()           {}
+func (m *imageSpec) Reset()                        { *m = imageSpec{} }
+func (m *imageSpec) String() string                { return proto.CompactTextString(m) }
+func (*imageSpec) ProtoMessage()                   {}
+func (m *containerStateValue) Reset()              { *m = containerStateValue{} }
+func (m *containerStateValue) String() string      { return proto.CompactTextString(m) }
+func (*containerStateValue) ProtoMessage()         {}
+func (m *containerFilter) Reset()                  { *m = containerFilter{} }
+func (m *containerFilter) String() string          { return proto.CompactTextString(m) }
+func (*containerFilter) ProtoMessage()             {}
+func (m *listContainersRequest) Reset()            { *m = listContainersRequest{} }
+func (m *listContainersRequest) String() string    { return proto.CompactTextString(m) }
+func (*listContainersRequest) ProtoMessage()       {}
+func (m *runtimeContainer) Reset()                 { *m = runtimeContainer{} }
+func (m *runtimeContainer) String() string         { return proto.CompactTextString(m) }
+func (*runtimeContainer) ProtoMessage()            {}
+func (m *listContainersResponse) Reset()           { *m = listContainersResponse{} }
+func (m *listContainersResponse) String() string   { return proto.CompactTextString(m) }
+func (*listContainersResponse) ProtoMessage()      {}
+func (m *containerStatusRequest) Reset()           { *m = containerStatusRequest{} }
+func (m *containerStatusRequest) String() string   { return proto.CompactTextString(m) }
+func (*containerStatusRequest) ProtoMessage()      {}
+func (m *containerStatus) Reset()                  { *m = containerStatus{} }
+func (m *containerStatus) String() string          { return proto.CompactTextString(m) }
+func (*containerStatus) ProtoMessage()             {}
+func (m *containerStatusResponse) Reset()          { *m = containerStatusResponse{} }
+func (m *containerStatusResponse) String() string  { return proto.CompactTextString(m) }
+func (*containerStatusResponse) ProtoMessage()     {}
+func (m *podSandboxStatusRequest) Reset()          { *m = podSandboxStatusRequest{} }
+func (m *podSandboxStatusRequest) String() string  { return proto.CompactTextString(m) }
+func (*podSandboxStatusRequest) ProtoMessage()     {}
+func (m *podIP) Reset()                            { *m = podIP{} }
+func (m *podIP) String() string                    { return proto.CompactTextString(m) }
+func (*podIP) ProtoMessage()                       {}
+func (m *podSandboxNetworkStatus) Reset()          { *m = podSandboxNetworkStatus{} }
+func (m *podSandboxNetworkStatus) String() string  { return proto.CompactTextString(m) }
+func (*podSandboxNetworkStatus) ProtoMessage()     {}
+func (m *namespaceOption) Reset()                  { *m = namespaceOption{} }
+func (m *namespaceOption) String() string          { return proto.CompactTextString(m) }
+func (*namespaceOption) ProtoMessage()             {}
+func (m *namespace) Reset()                        { *m = namespace{} }
+func (m *namespace) String() string                { return proto.CompactTextString(m) }
+func (*namespace) ProtoMessage()                   {}
+func (m *linuxPodSandboxStatus) Reset()            { *m = linuxPodSandboxStatus{} }
+func (m *linuxPodSandboxStatus) String() string    { return proto.CompactTextString(m) }
+func (*linuxPodSandboxStatus) ProtoMessage()       {}
+func (m *podSandboxStatus) Reset()                 { *m = podSandboxStatus{} }
+func (m *podSandboxStatus) String() string         { return proto.CompactTextString(m) }
+func (*podSandboxStatus) ProtoMessage()            {}
+func (m *podSandboxStatusResponse) Reset()         { *m = podSandboxStatusResponse{} }
+func (m *podSandboxStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*podSandboxStatusResponse) ProtoMessage()    {}
+func (m *uint64Value) Reset()                      { *m = uint64Value{} }
+func (m *uint64Value) String() string              { return proto.CompactTextString(m) }
+func (*uint64Value) ProtoMessage()                 {}
+func (m *cpuUsage) Reset()                         { *m = cpuUsage{} }
+func (m *cpuUsage) String() string                 { return proto.CompactTextString(m) }
+func (*cpuUsage) ProtoMessage()                    {}
+func (m *memoryUsage) Reset()                      { *m = memoryUsage{} }
+func (m *memoryUsage) String() string              { return proto.CompactTextString(m) }
+func (*memoryUsage) ProtoMessage()                 {}
+func (m *containerStats) Reset()                   { *m = containerStats{} }
+func (m *containerStats) String() string           { return proto.CompactTextString(m) }
+func (*containerStats) ProtoMessage()              {}
+func (m *containerStatsRequest) Reset()            { *m = containerStatsRequest{} }
+func (m *containerStatsRequest) String() string    { return proto.CompactTextString(m) }
+func (*containerStatsRequest) ProtoMessage()       {}
+func (m *containerStatsResponse) Reset()           { *m = containerStatsResponse{} }
+func (m *containerStatsResponse) String() string   { return proto.CompactTextString(m) }
+func (*containerStatsResponse) ProtoMessage()      {}
+func (m *startContainerRequest) Reset()            { *m = startContainerRequest{} }
+func (m *startContainerRequest) String() string    { return proto.CompactTextString(m) }
+func (*startContainerRequest) ProtoMessage()       {}
+func (m *startContainerResponse) Reset()           { *m = startContainerResponse{} }
+func (m *startContainerResponse) String() string   { return proto.CompactTextString(m) }
+func (*startContainerResponse) ProtoMessage()      {}
+func (m *stopContainerRequest) Reset()             { *m = stopContainerRequest{} }
+func (m *stopContainerRequest) String() string     { return proto.CompactTextString(m) }
+func (*stopContainerRequest) ProtoMessage()        {}
+func (m *stopContainerResponse) Reset()            { *m = stopContainerResponse{} }
+func (m *stopContainerResponse) String() string    { return proto.CompactTextString(m) }
+func (*stopContainerResponse) ProtoMessage()       {}
+func (m *removeContainerRequest) Reset()           { *m = removeContainerRequest{} }
+func (m *removeContainerRequest) String() string   { return proto.CompactTextString(m) }
+func (*removeContainerRequest) ProtoMessage()      {}
+func (m *removeContainerResponse) Reset()          { *m = removeContainerResponse{} }
+func (m *removeContainerResponse) String() string  { return proto.CompactTextString(m) }
+func (*removeContainerResponse) ProtoMessage()     {}
+func (m *execRequest) Reset()                      { *m = execRequest{} }
+func (m *execRequest) String() string              { return proto.CompactTextString(m) }
+func (*execRequest) ProtoMessage()                 {}
+func (m *execResponse) Reset()                     { *m = execResponse{} }
+func (m *execResponse) String() string             { return proto.CompactTextString(m) }
+func (*execResponse) ProtoMessage()                {}
+func (m *attachRequest) Reset()                    { *m = attachRequest{} }
+func (m *attachRequest) String() string            { return proto.CompactTextString(m) }
+func (*attachRequest) ProtoMessage()               {}
+func (m *attachResponse) Reset()                   { *m = attachResponse{} }
+func (m *attachResponse) String() string           { return proto.CompactTextString(m) }
+func (*attachResponse) ProtoMessage()              {}
+func (m *listImagesRequest) Reset()                { *m = listImagesRequest{} }
+func (m *listImagesRequest) String() string        { return proto.CompactTextString(m) }
+func (*listImagesRequest) ProtoMessage()           {}
+func (m *runtimeImage) Reset()                     { *m = runtimeImage{} }
+func (m *runtimeImage) String() string             { return proto.CompactTextString(m) }
+func (*runtimeImage) ProtoMessage()                {}
+func (m *listImagesResponse) Reset()               { *m = listImagesResponse{} }
+func (m *listImagesResponse) String() string       { return proto.CompactTextString(m) }
+func (*listImagesResponse) ProtoMessage()          {}
@@ -0,0 +1,444 @@
+package cri
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	docker_client "github.com/fsouza/go-dockerclient"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/weaveworks/scope/probe/docker"
+)
+
+const (
+	runtimeService = "runtime.v1.RuntimeService"
+	imageService   = "runtime.v1.ImageService"
+
+	unixPrefix     = "unix://"
+	requestTimeout = 10 * time.Second
+	// Like the kubelet, which relists containers every second
+	pollInterval = time.Second
+	// Like Docker, which streams stats every second
+	statsInterval = time.Second
+)
+
+// The docker.Client calls without a CRI equivalent.
+var (
+	errRestartNotSupported = fmt.Errorf("restarting containers is not supported by CRI runtimes")
+	errPauseNotSupported   = fmt.Errorf("pausing containers is not supported by CRI runtimes")
+)
+
+// client is a docker.Client talking to a container runtime through the
+// Kubernetes Container Runtime Interface, e.g. containerd or CRI-O.
+// Containers are described as Docker would, so the docker Registry,
+// Reporter and Tagger work unchanged on top of it.
+type client struct {
+	conn         *grpc.ClientConn
+	pollInterval time.Duration
+
+	sync.Mutex
+	listeners  map[chan<- *docker_client.APIEvents]chan struct{}
+	execs      map[string]*execSession
+	lastExecID int
+}
+
+// NewClient connects to the CRI runtime listening on endpoint, e.g.
+// unix:///run/containerd/containerd.sock.
+func NewClient(endpoint string) (docker.Client, error) {
+	if !strings.HasPrefix(endpoint, unixPrefix) {
+		return nil, fmt.Errorf("unsupported CRI endpoint %q: only unix sockets are", endpoint)
+	}
+	conn, err := grpc.Dial(strings.TrimPrefix(endpoint, unixPrefix),
+		grpc.WithInsecure(),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c := newClient(conn, pollInterval)
+	version := &versionResponse{}
+	if err := c.call(runtimeService, "Version", &versionRequest{}, version); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	log.Infof("CRI: connected to %s %s (API %s)", version.RuntimeName, version.RuntimeVersion, version.RuntimeAPIVersion)
+	return c, nil
+}
+
+func newClient(conn *grpc.ClientConn, pollInterval time.Duration) *client {
+	return &client{
+		conn:         conn,
+		pollInterval: pollInterval,
+		listeners:    map[chan<- *docker_client.APIEvents]chan struct{}{},
+		execs:        map[string]*execSession{},
+	}
+}
+
+func (c *client) call(service, method string, req, resp proto.Message) error {
+	return c.callWithTimeout(service, method, req, resp, requestTimeout)
+}
+
+func (c *client) callWithTimeout(service, method string, req, resp proto.Message, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return grpc.Invoke(ctx, "/"+service+"/"+method, req, resp, c.conn)
+}
+
+func (c *client) listContainers(filter *containerFilter) ([]*runtimeContainer, error) {
+	resp := &listContainersResponse{}
+	if err := c.call(runtimeService, "ListContainers", &listContainersRequest{Filter: filter}, resp); err != nil {
+		return nil, err
+	}
+	return resp.Containers, nil
+}
+
+func (c *client) ListContainers(docker_client.ListContainersOptions) ([]docker_client.APIContainers, error) {
+	containers, err := c.listContainers(nil)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]docker_client.APIContainers, 0, len(containers))
+	for _, container := range containers {
+		apiContainer := docker_client.APIContainers{
+			ID:      container.ID,
+			Created: container.CreatedAt / int64(time.Second),
+			Labels:  container.Labels,
+		}
+		if container.Image != nil {
+			apiContainer.Image = container.Image.Image
+		}
+		if container.Metadata != nil {
+			apiContainer.Names = []string{"/" + container.Metadata.Name}
+		}
+		result = append(result, apiContainer)
+	}
+	return result, nil
+}
+
+// containerInfo are the details of a container returned by the
+// runtime in verbose mode. Both containerd and CRI-O report them.
+type containerInfo struct {
+	SandboxID   string `json:"sandboxID"`
+	PID         int    `json:"pid"`
+	RuntimeSpec struct {
+		Hostname string `json:"hostname"`
+		Process  struct {
+			Args []string `json:"args"`
+			Env  []string `json:"env"`
+		} `json:"process"`
+	} `json:"runtimeSpec"`
+}
+
+func (c *client) InspectContainer(id string) (*docker_client.Container, error) {
+	resp := &containerStatusResponse{}
+	if err := c.call(runtimeService, "ContainerStatus", &containerStatusRequest{ContainerID: id, Verbose: true}, resp); err != nil {
+		if grpc.Code(err) == codes.NotFound {
+			return nil, &docker_client.NoSuchContainer{ID: id, Err: err}
+		}
+		return nil, err
+	}
+	status := resp.Status
+	if status == nil {
+		return nil, &docker_client.NoSuchContainer{ID: id}
+	}
+	var info containerInfo
+	if raw, ok := resp.Info["info"]; ok {
+		if err := json.Unmarshal([]byte(raw), &info); err != nil {
+			log.Warnf("CRI: invalid info of container %s: %v", id, err)
+		}
+	}
+
+	container := &docker_client.Container{
+		ID:      status.ID,
+		Created: time.Unix(0, status.CreatedAt),
+		Image:   status.ImageID,
+		Config: &docker_client.Config{
+			Hostname: info.RuntimeSpec.Hostname,
+			Env:      info.RuntimeSpec.Process.Env,
+			Labels:   status.Labels,
+		},
+		State: docker_client.State{
+			Running:  status.State == containerRunning,
+			ExitCode: int(status.ExitCode),
+			Error:    status.Message,
+		},
+		NetworkSettings: &docker_client.NetworkSettings{},
+		HostConfig:      &docker_client.HostConfig{},
+	}
+	if container.Image == "" {
+		// Runtimes older than the image_id field
+		container.Image = status.ImageRef
+	}
+	if status.Image != nil {
+		container.Config.Image = status.Image.Image
+	}
+	if status.Metadata != nil {
+		container.Name = status.Metadata.Name
+		container.RestartCount = int(status.Metadata.Attempt)
+	}
+	if status.StartedAt > 0 {
+		container.State.StartedAt = time.Unix(0, status.StartedAt)
+	}
+	if status.FinishedAt > 0 {
+		container.State.FinishedAt = time.Unix(0, status.FinishedAt)
+	}
+	if container.State.Running {
+		container.State.Pid = info.PID
+	}
+	if args := info.RuntimeSpec.Process.Args; len(args) > 0 {
+		container.Path, container.Args = args[0], args[1:]
+	}
+	c.addSandboxNetwork(container, info.SandboxID)
+	return container, nil
+}
+
+// addSandboxNetwork sets the network of container to that of its pod
+// sandbox, which its containers share.
+func (c *client) addSandboxNetwork(container *docker_client.Container, sandboxID string) {
+	if sandboxID == "" {
+		containers, err := c.listContainers(&containerFilter{ID: container.ID})
+		if err != nil || len(containers) != 1 {
+			return
+		}
+		sandboxID = containers[0].PodSandboxID
+	}
+	resp := &podSandboxStatusResponse{}
+	if err := c.call(runtimeService, "PodSandboxStatus", &podSandboxStatusRequest{PodSandboxID: sandboxID}, resp); err != nil {
+		log.Warnf("CRI: failed to get the pod sandbox of container %s: %v", container.ID, err)
+		return
+	}
+	status := resp.Status
+	if status == nil {
+		return
+	}
+	if linux := status.Linux; linux != nil && linux.Namespaces != nil && linux.Namespaces.Options != nil &&
+		linux.Namespaces.Options.Network == namespaceNode {
+		container.HostConfig.NetworkMode = "host"
+		return
+	}
+	if network := status.Network; network != nil {
+		container.NetworkSettings.IPAddress = network.IP
+		for _, ip := range network.AdditionalIPs {
+			container.NetworkSettings.SecondaryIPAddresses = append(container.NetworkSettings.SecondaryIPAddresses, ip.IP)
+		}
+	}
+}
+
+func (c *client) ListImages(docker_client.ListImagesOptions) ([]docker_client.APIImages, error) {
+	resp := &listImagesResponse{}
+	if err := c.call(imageService, "ListImages", &listImagesRequest{}, resp); err != nil {
+		return nil, err
+	}
+	result := make([]docker_client.APIImages, 0, len(resp.Images))
+	for _, image := range resp.Images {
+		result = append(result, docker_client.APIImages{
+			ID:          image.ID,
+			RepoTags:    image.RepoTags,
+			RepoDigests: image.RepoDigests,
+			Size:        int64(image.Size),
+			VirtualSize: int64(image.Size),
+		})
+	}
+	return result, nil
+}
+
+// ListNetworks returns no networks, as CRI runtimes leave networking
+// to CNI plugins.
+func (c *client) ListNetworks() ([]docker_client.Network, error) {
+	return nil, nil
+}
+
+// AddEventListener sends events to events as Docker would, from the
+// changes seen when polling the containers of the runtime. events is
+// closed if the runtime can't be reached anymore.
+func (c *client) AddEventListener(events chan<- *docker_client.APIEvents) error {
+	states, err := c.containerStates()
+	if err != nil {
+		return err
+	}
+	quit := make(chan struct{})
+	c.Lock()
+	c.listeners[events] = quit
+	c.Unlock()
+	go c.pollEvents(events, quit, states)
+	return nil
+}
+
+func (c *client) RemoveEventListener(events chan *docker_client.APIEvents) error {
+	c.Lock()
+	defer c.Unlock()
+	if quit, ok := c.listeners[events]; ok {
+		close(quit)
+		delete(c.listeners, events)
+	}
+	return nil
+}
+
+func (c *client) containerStates() (map[string]containerState, error) {
+	containers, err := c.listContainers(nil)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]containerState, len(containers))
+	for _, container := range containers {
+		states[container.ID] = container.State
+	}
+	return states, nil
+}
+
+func (c *client) pollEvents(events chan<- *docker_client.APIEvents, quit <-chan struct{}, states map[string]containerState) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+		current, err := c.containerStates()
+		if err != nil {
+			log.Errorf("CRI: failed to list containers: %v", err)
+			close(events)
+			return
+		}
+		for _, event := range containerEvents(states, current, time.Now()) {
+			select {
+			case events <- event:
+			case <-quit:
+				return
+			}
+		}
+		states = current
+	}
+}
+
+// containerEvents returns the Docker events matching the changes from
+// the previous to the current states of containers, by container ID.
+func containerEvents(previous, current map[string]containerState, now time.Time) []*docker_client.APIEvents {
+	var (
+		result []*docker_client.APIEvents
+		ids    = []string{}
+	)
+	for id := range current {
+		ids = append(ids, id)
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	event := func(id, status string) {
+		result = append(result, &docker_client.APIEvents{
+			Status:   status,
+			ID:       id,
+			Time:     now.Unix(),
+			TimeNano: now.UnixNano(),
+		})
+	}
+	for _, id := range ids {
+		before, existed := previous[id]
+		after, exists := current[id]
+		switch {
+		case !exists:
+			event(id, docker.DestroyEvent)
+		case !existed:
+			event(id, docker.CreateEvent)
+			if after == containerRunning {
+				event(id, docker.StartEvent)
+			}
+		case before != after && after == containerRunning:
+			event(id, docker.StartEvent)
+		case before != after && after == containerExited:
+			event(id, docker.DieEvent)
+		}
+	}
+	return result
+}
+
+func (c *client) StopContainer(id string, timeout uint) error {
+	req := &stopContainerRequest{ContainerID: id, Timeout: int64(timeout)}
+	return c.callWithTimeout(runtimeService, "StopContainer", req, &stopContainerResponse{}, requestTimeout+time.Duration(timeout)*time.Second)
+}
+
+func (c *client) StartContainer(id string, _ *docker_client.HostConfig) error {
+	return c.call(runtimeService, "StartContainer", &startContainerRequest{ContainerID: id}, &startContainerResponse{})
+}
+
+func (c *client) RestartContainer(string, uint) error {
+	return errRestartNotSupported
+}
+
+func (c *client) PauseContainer(string) error {
+	return errPauseNotSupported
+}
+
+func (c *client) UnpauseContainer(string) error {
+	return errPauseNotSupported
+}
+
+func (c *client) RemoveContainer(opts docker_client.RemoveContainerOptions) error {
+	return c.call(runtimeService, "RemoveContainer", &removeContainerRequest{ContainerID: opts.ID}, &removeContainerResponse{})
+}
+
+// Stats sends the stats of a container to opts.Stats every second, as
+// Docker would, until opts.Done is closed.
+func (c *client) Stats(opts docker_client.StatsOptions) error {
+	defer close(opts.Stats)
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+	for {
+		resp := &containerStatsResponse{}
+		if err := c.call(runtimeService, "ContainerStats", &containerStatsRequest{ContainerID: opts.ID}, resp); err != nil {
+			return err
+		}
+		if stats, ok := dockerStats(resp.Stats); ok {
+			select {
+			case opts.Stats <- stats:
+			case <-opts.Done:
+				return nil
+			}
+		}
+		if !opts.Stream {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-opts.Done:
+			return nil
+		}
+	}
+}
+
+// dockerStats converts the stats of a container to Docker's. The CPU
+// usage is relative to the time of all the CPUs of the host, as Docker's
+// system CPU usage.
+func dockerStats(stats *containerStats) (*docker_client.Stats, bool) {
+	if stats == nil || stats.CPU == nil || stats.CPU.UsageCoreNanoSeconds == nil {
+		return nil, false
+	}
+	result := &docker_client.Stats{Read: time.Unix(0, stats.CPU.Timestamp)}
+	result.CPUStats.CPUUsage.TotalUsage = stats.CPU.UsageCoreNanoSeconds.Value
+	result.CPUStats.SystemCPUUsage = uint64(stats.CPU.Timestamp) * uint64(runtime.NumCPU())
+	if memory := stats.Memory; memory != nil && memory.WorkingSetBytes != nil {
+		result.MemoryStats.Usage = memory.WorkingSetBytes.Value
+		// Only set for containers with a memory limit
+		if memory.AvailableBytes != nil && memory.AvailableBytes.Value > 0 {
+			result.MemoryStats.Limit = memory.WorkingSetBytes.Value + memory.AvailableBytes.Value
+		}
+	}
+	return result, true
+}
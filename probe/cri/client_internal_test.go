@@ -0,0 +1,396 @@
+package cri
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	docker_client "github.com/fsouza/go-dockerclient"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+
+	commonTest "github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/common/xfer"
+	"github.com/weaveworks/scope/probe/controls"
+	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test"
+)
+
+const (
+	sandboxID = "sandbox1"
+	sandboxIP = "10.32.0.7"
+)
+
+// fakeRuntime is a CRI runtime serving a set of containers, all in the
+// same pod sandbox.
+type fakeRuntime struct {
+	sync.Mutex
+	containers map[string]*containerStatus
+	pids       map[string]int
+}
+
+func newFakeRuntime() *fakeRuntime {
+	return &fakeRuntime{
+		containers: map[string]*containerStatus{},
+		pids:       map[string]int{},
+	}
+}
+
+func (f *fakeRuntime) addContainer(id, name string, pid int) {
+	f.Lock()
+	defer f.Unlock()
+	f.containers[id] = &containerStatus{
+		ID:        id,
+		Metadata:  &containerMetadata{Name: name, Attempt: 1},
+		State:     containerRunning,
+		CreatedAt: time.Unix(1500000000, 0).UnixNano(),
+		StartedAt: time.Unix(1500000001, 0).UnixNano(),
+		Image:     &imageSpec{Image: "docker.io/library/nginx:1.13"},
+		ImageRef:  "docker.io/library/nginx@sha256:e4f0474a75c510f40b37b6b7dc2516241ffa8bde5a442bde3d372c9519c84d90",
+		ImageID:   "sha256:b8efb18f159bd948486f18bd8940b56fd2298b438229f5bd2bcf4cedcf037448",
+		Labels: map[string]string{
+			"io.kubernetes.pod.uid":  "ee9d1e8c-9a9c-11e7-8b8c-42010a840002",
+			"io.kubernetes.pod.name": "nginx",
+		},
+	}
+	f.pids[id] = pid
+}
+
+func (f *fakeRuntime) version(proto.Message) (proto.Message, error) {
+	return &versionResponse{Version: "0.1.0", RuntimeName: "fake", RuntimeVersion: "1.0", RuntimeAPIVersion: "v1"}, nil
+}
+
+func (f *fakeRuntime) listContainers(req proto.Message) (proto.Message, error) {
+	f.Lock()
+	defer f.Unlock()
+	filter := req.(*listContainersRequest).Filter
+	resp := &listContainersResponse{}
+	for id, status := range f.containers {
+		if filter != nil && filter.ID != "" && filter.ID != id {
+			continue
+		}
+		resp.Containers = append(resp.Containers, &runtimeContainer{
+			ID:           id,
+			PodSandboxID: sandboxID,
+			Metadata:     status.Metadata,
+			State:        status.State,
+			CreatedAt:    status.CreatedAt,
+			Labels:       status.Labels,
+		})
+	}
+	return resp, nil
+}
+
+func (f *fakeRuntime) containerStatus(req proto.Message) (proto.Message, error) {
+	f.Lock()
+	defer f.Unlock()
+	id := req.(*containerStatusRequest).ContainerID
+	status, ok := f.containers[id]
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "container %q not found", id)
+	}
+	return &containerStatusResponse{
+		Status: status,
+		Info: map[string]string{
+			"info": fmt.Sprintf(`{"pid": %d, "runtimeSpec": {"hostname": "nginx", "process": {"args": ["nginx", "-g", "daemon off;"], "env": ["PATH=/usr/bin"]}}}`, f.pids[id]),
+		},
+	}, nil
+}
+
+func (f *fakeRuntime) podSandboxStatus(req proto.Message) (proto.Message, error) {
+	if id := req.(*podSandboxStatusRequest).PodSandboxID; id != sandboxID {
+		return nil, grpc.Errorf(codes.NotFound, "pod sandbox %q not found", id)
+	}
+	return &podSandboxStatusResponse{
+		Status: &podSandboxStatus{ID: sandboxID, Network: &podSandboxNetworkStatus{IP: sandboxIP}},
+	}, nil
+}
+
+func (f *fakeRuntime) stopContainer(req proto.Message) (proto.Message, error) {
+	f.Lock()
+	defer f.Unlock()
+	id := req.(*stopContainerRequest).ContainerID
+	status, ok := f.containers[id]
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "container %q not found", id)
+	}
+	status.State = containerExited
+	status.FinishedAt = time.Unix(1500000002, 0).UnixNano()
+	return &stopContainerResponse{}, nil
+}
+
+func (f *fakeRuntime) containerStats(req proto.Message) (proto.Message, error) {
+	return &containerStatsResponse{
+		Stats: &containerStats{
+			CPU: &cpuUsage{Timestamp: 1000, UsageCoreNanoSeconds: &uint64Value{Value: 500}},
+			Memory: &memoryUsage{
+				Timestamp:       1000,
+				WorkingSetBytes: &uint64Value{Value: 1024},
+				AvailableBytes:  &uint64Value{Value: 3072},
+			},
+		},
+	}, nil
+}
+
+func (f *fakeRuntime) listImages(proto.Message) (proto.Message, error) {
+	return &listImagesResponse{
+		Images: []*runtimeImage{{
+			ID:       "sha256:b8efb18f159bd948486f18bd8940b56fd2298b438229f5bd2bcf4cedcf037448",
+			RepoTags: []string{"docker.io/library/nginx:1.13"},
+			Size:     1000,
+		}},
+	}, nil
+}
+
+// serve serves f as a CRI runtime on a Unix socket in dir, returning
+// its endpoint.
+func (f *fakeRuntime) serve(t *testing.T, dir string) (string, *grpc.Server) {
+	method := func(name string, req proto.Message, handle func(proto.Message) (proto.Message, error)) grpc.MethodDesc {
+		return grpc.MethodDesc{
+			MethodName: name,
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return handle(req)
+			},
+		}
+	}
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: runtimeService,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			method("Version", &versionRequest{}, f.version),
+			method("ListContainers", &listContainersRequest{}, f.listContainers),
+			method("ContainerStatus", &containerStatusRequest{}, f.containerStatus),
+			method("PodSandboxStatus", &podSandboxStatusRequest{}, f.podSandboxStatus),
+			method("StopContainer", &stopContainerRequest{}, f.stopContainer),
+			method("ContainerStats", &containerStatsRequest{}, f.containerStats),
+		},
+	}, f)
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: imageService,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			method("ListImages", &listImagesRequest{}, f.listImages),
+		},
+	}, f)
+
+	path := filepath.Join(dir, "cri.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+	return unixPrefix + path, server
+}
+
+func newTestClient(t *testing.T, f *fakeRuntime) (*client, func()) {
+	dir, err := ioutil.TempDir("", "cri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint, server := f.serve(t, dir)
+	c, err := NewClient(endpoint)
+	if err != nil {
+		server.Stop()
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return c.(*client), func() {
+		c.(*client).conn.Close()
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestInspectContainer(t *testing.T) {
+	f := newFakeRuntime()
+	f.addContainer("ping", "nginx", 1234)
+	c, cleanup := newTestClient(t, f)
+	defer cleanup()
+
+	have, err := c.InspectContainer("ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &docker_client.Container{
+		ID:      "ping",
+		Name:    "nginx",
+		Created: time.Unix(1500000000, 0),
+		Path:    "nginx",
+		Args:    []string{"-g", "daemon off;"},
+		Image:   "sha256:b8efb18f159bd948486f18bd8940b56fd2298b438229f5bd2bcf4cedcf037448",
+		Config: &docker_client.Config{
+			Hostname: "nginx",
+			Env:      []string{"PATH=/usr/bin"},
+			Image:    "docker.io/library/nginx:1.13",
+			Labels: map[string]string{
+				"io.kubernetes.pod.uid":  "ee9d1e8c-9a9c-11e7-8b8c-42010a840002",
+				"io.kubernetes.pod.name": "nginx",
+			},
+		},
+		State: docker_client.State{
+			Running:   true,
+			Pid:       1234,
+			StartedAt: time.Unix(1500000001, 0),
+		},
+		RestartCount:    1,
+		NetworkSettings: &docker_client.NetworkSettings{IPAddress: sandboxIP},
+		HostConfig:      &docker_client.HostConfig{},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Error(commonTest.Diff(want, have))
+	}
+
+	if _, err := c.InspectContainer("pong"); err == nil {
+		t.Error("Expected an error inspecting a missing container")
+	} else if _, ok := err.(*docker_client.NoSuchContainer); !ok {
+		t.Errorf("Expected a NoSuchContainer error, got %v", err)
+	}
+}
+
+func TestContainerEvents(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	have := containerEvents(
+		map[string]containerState{"created": containerCreated, "started": containerCreated, "died": containerRunning, "gone": containerRunning, "same": containerRunning},
+		map[string]containerState{"created": containerCreated, "started": containerRunning, "died": containerExited, "new": containerRunning, "same": containerRunning},
+		now,
+	)
+	event := func(id, status string) *docker_client.APIEvents {
+		return &docker_client.APIEvents{ID: id, Status: status, Time: now.Unix(), TimeNano: now.UnixNano()}
+	}
+	want := []*docker_client.APIEvents{
+		event("died", docker.DieEvent),
+		event("gone", docker.DestroyEvent),
+		event("new", docker.CreateEvent),
+		event("new", docker.StartEvent),
+		event("started", docker.StartEvent),
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Error(commonTest.Diff(want, have))
+	}
+}
+
+func TestStats(t *testing.T) {
+	f := newFakeRuntime()
+	f.addContainer("ping", "nginx", 1234)
+	c, cleanup := newTestClient(t, f)
+	defer cleanup()
+
+	stats := make(chan *docker_client.Stats, 1)
+	if err := c.Stats(docker_client.StatsOptions{ID: "ping", Stats: stats, Done: make(chan bool)}); err != nil {
+		t.Fatal(err)
+	}
+	have, ok := <-stats
+	if !ok {
+		t.Fatal("Expected stats")
+	}
+	if have.CPUStats.CPUUsage.TotalUsage != 500 || have.MemoryStats.Usage != 1024 || have.MemoryStats.Limit != 4096 {
+		t.Errorf("Unexpected stats: %+v", have)
+	}
+}
+
+// The docker registry finds the containers of the runtime, follows
+// their lifecycle and controls them.
+func TestRegistry(t *testing.T) {
+	f := newFakeRuntime()
+	f.addContainer("ping", "nginx", 1234)
+	c, cleanup := newTestClient(t, f)
+	defer cleanup()
+	c.pollInterval = 10 * time.Millisecond
+
+	hr := controls.NewDefaultHandlerRegistry()
+	registry, err := docker.NewRegistry(docker.RegistryOptions{
+		Interval:        10 * time.Millisecond,
+		HostID:          "host1",
+		HandlerRegistry: hr,
+		Client:          c,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Stop()
+
+	// Read the state of containers under the lock of the registry, as
+	// the reporter does
+	containerState := func(id string) interface{} {
+		state := ""
+		registry.WalkContainers(func(c docker.Container) {
+			if c.ID() == id {
+				state = c.StateString()
+			}
+		})
+		return state
+	}
+	test.Poll(t, 100*time.Millisecond, docker.StateRunning, func() interface{} { return containerState("ping") })
+	registry.LockedPIDLookup(func(lookup func(int) docker.Container) {
+		if container := lookup(1234); container == nil || container.ID() != "ping" {
+			t.Errorf("Expected pid 1234 to be in container ping")
+		}
+	})
+
+	f.addContainer("pong", "nginx", 1235)
+	test.Poll(t, 100*time.Millisecond, docker.StateRunning, func() interface{} { return containerState("pong") })
+
+	if resp := hr.HandleControlRequest(xfer.Request{
+		NodeID:  report.MakeContainerNodeID("pong"),
+		Control: docker.StopContainer,
+	}); resp.Error != "" {
+		t.Fatal(resp.Error)
+	}
+	test.Poll(t, 100*time.Millisecond, docker.StateExited, func() interface{} { return containerState("pong") })
+}
+
+type mockStreamConn struct {
+	httpstream.Connection
+	closed bool
+}
+
+func (c *mockStreamConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type mockUpgrader struct {
+	conns []*mockStreamConn
+}
+
+func (u *mockUpgrader) NewConnection(*http.Response) (httpstream.Connection, error) {
+	conn := &mockStreamConn{}
+	u.conns = append(u.conns, conn)
+	return conn, nil
+}
+
+func TestStreamWaiterClose(t *testing.T) {
+	upgrader := &mockUpgrader{}
+	closed := false
+	w := &streamWaiter{
+		done:     make(chan struct{}),
+		upgrader: &closingUpgrader{Upgrader: upgrader},
+		onClose:  func() { closed = true },
+	}
+	if _, err := w.upgrader.NewConnection(nil); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if !closed || !upgrader.conns[0].closed {
+		t.Errorf("Expected closing the stream to close its connection")
+	}
+	// A stream closed before its connection is upgraded doesn't start
+	if _, err := w.upgrader.NewConnection(nil); err == nil || !upgrader.conns[1].closed {
+		t.Errorf("Expected no connection once closed")
+	}
+}
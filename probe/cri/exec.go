@@ -0,0 +1,240 @@
+package cri
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	docker_client "github.com/fsouza/go-dockerclient"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// execSession is a command created by CreateExec, and the sizes of its
+// terminal once started.
+type execSession struct {
+	containerID string
+	cmd         []string
+	tty         bool
+	sizes       sizeQueue
+}
+
+// sizeQueue is a remotecommand.TerminalSizeQueue holding the latest size
+// of a terminal.
+type sizeQueue chan remotecommand.TerminalSize
+
+func (q sizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+// push replaces the size waiting to be read, if any, with size.
+func (q sizeQueue) push(size remotecommand.TerminalSize) {
+	select {
+	case <-q:
+	default:
+	}
+	select {
+	case q <- size:
+	default:
+	}
+}
+
+// CreateExec prepares a command to run in a container. It only runs
+// once started, as CRI runtimes create and start execs in one go.
+func (c *client) CreateExec(opts docker_client.CreateExecOptions) (*docker_client.Exec, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.lastExecID++
+	id := strconv.Itoa(c.lastExecID)
+	c.execs[id] = &execSession{
+		containerID: opts.Container,
+		cmd:         opts.Cmd,
+		tty:         opts.Tty,
+	}
+	return &docker_client.Exec{ID: id}, nil
+}
+
+func (c *client) StartExecNonBlocking(id string, opts docker_client.StartExecOptions) (docker_client.CloseWaiter, error) {
+	c.Lock()
+	exec, ok := c.execs[id]
+	c.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no such exec: %s", id)
+	}
+	req := &execRequest{
+		ContainerID: exec.containerID,
+		Cmd:         exec.cmd,
+		Tty:         exec.tty,
+		Stdin:       opts.InputStream != nil,
+		Stdout:      true,
+		// Terminals merge the error and output streams
+		Stderr: !exec.tty,
+	}
+	resp := &execResponse{}
+	if err := c.call(runtimeService, "Exec", req, resp); err != nil {
+		c.removeExec(id)
+		return nil, err
+	}
+
+	sizes := make(sizeQueue, 1)
+	c.Lock()
+	exec.sizes = sizes
+	c.Unlock()
+	streams := remotecommand.StreamOptions{
+		Stdin:             opts.InputStream,
+		Stdout:            opts.OutputStream,
+		Tty:               exec.tty,
+		TerminalSizeQueue: sizes,
+	}
+	if req.Stderr {
+		streams.Stderr = opts.ErrorStream
+	}
+	return stream(resp.URL, streams, func() { c.removeExec(id) })
+}
+
+// removeExec forgets about an exec, and stops resizing its terminal.
+func (c *client) removeExec(id string) {
+	c.Lock()
+	defer c.Unlock()
+	if exec, ok := c.execs[id]; ok {
+		if exec.sizes != nil {
+			close(exec.sizes)
+		}
+		delete(c.execs, id)
+	}
+}
+
+func (c *client) ResizeExecTTY(id string, height, width int) error {
+	c.Lock()
+	defer c.Unlock()
+	exec, ok := c.execs[id]
+	if !ok || exec.sizes == nil {
+		return fmt.Errorf("no such exec: %s", id)
+	}
+	exec.sizes.push(remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)})
+	return nil
+}
+
+func (c *client) AttachToContainerNonBlocking(opts docker_client.AttachToContainerOptions) (docker_client.CloseWaiter, error) {
+	req := &attachRequest{
+		ContainerID: opts.Container,
+		Stdin:       opts.Stdin,
+		Tty:         opts.RawTerminal,
+		Stdout:      opts.Stdout,
+		Stderr:      opts.Stderr && !opts.RawTerminal,
+	}
+	resp := &attachResponse{}
+	if err := c.call(runtimeService, "Attach", req, resp); err != nil {
+		return nil, err
+	}
+	streams := remotecommand.StreamOptions{Tty: opts.RawTerminal}
+	if req.Stdin {
+		streams.Stdin = opts.InputStream
+	}
+	if req.Stdout {
+		streams.Stdout = opts.OutputStream
+	}
+	if req.Stderr {
+		streams.Stderr = opts.ErrorStream
+	}
+	return stream(resp.URL, streams, func() {})
+}
+
+// streamWaiter is the docker_client.CloseWaiter of a stream to the
+// streaming server of a runtime.
+type streamWaiter struct {
+	done     chan struct{}
+	err      error
+	upgrader *closingUpgrader
+	onClose  func()
+}
+
+func (w *streamWaiter) Wait() error {
+	<-w.done
+	return w.err
+}
+
+// Close ends the stream, by closing its connection to the streaming
+// server, which ends the session in the runtime, and releases its
+// resources.
+func (w *streamWaiter) Close() error {
+	w.upgrader.close()
+	w.onClose()
+	return nil
+}
+
+// closingUpgrader is a spdy.Upgrader keeping the connection it upgrades
+// to, for it to be closed from outside of the executor using it.
+type closingUpgrader struct {
+	spdy.Upgrader
+	sync.Mutex
+	conn   httpstream.Connection
+	closed bool
+}
+
+func (u *closingUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+	u.Lock()
+	defer u.Unlock()
+	if u.closed {
+		conn.Close()
+		return nil, errors.New("stream closed")
+	}
+	u.conn = conn
+	return conn, nil
+}
+
+func (u *closingUpgrader) close() {
+	u.Lock()
+	defer u.Unlock()
+	u.closed = true
+	if u.conn != nil {
+		u.conn.Close()
+	}
+}
+
+// stream connects streams to the exec or attach session served at
+// rawURL, as kubectl does through the API server.
+func stream(rawURL string, streams remotecommand.StreamOptions, onClose func()) (docker_client.CloseWaiter, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		onClose()
+		return nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(&rest.Config{})
+	if err != nil {
+		onClose()
+		return nil, err
+	}
+	w := &streamWaiter{
+		done:     make(chan struct{}),
+		upgrader: &closingUpgrader{Upgrader: upgrader},
+		onClose:  onClose,
+	}
+	executor, err := remotecommand.NewSPDYExecutorForTransports(transport, w.upgrader, "POST", u)
+	if err != nil {
+		onClose()
+		return nil, err
+	}
+	go func() {
+		defer close(w.done)
+		w.err = executor.Stream(streams)
+		if w.err == io.EOF {
+			w.err = nil
+		}
+	}()
+	return w, nil
+}
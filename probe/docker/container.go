@@ -31,6 +31,10 @@ const (
 	ContainerUptime        = report.DockerContainerUptime
 	ContainerRestartCount  = report.DockerContainerRestartCount
 	ContainerNetworkMode   = report.DockerContainerNetworkMode
+	ContainerEventsPrefix  = report.DockerContainerEventsPrefix
+	ContainerEventTime     = report.DockerContainerEventTime
+	ContainerEventType     = report.DockerContainerEventType
+	ContainerEventDetail   = report.DockerContainerEventDetail
 
 	NetworkRxDropped = "network_rx_dropped"
 	NetworkRxBytes   = "network_rx_bytes"
@@ -68,6 +72,17 @@ var (
 	StateDeleted    = "deleted"
 )
 
+// At most maxContainerEvents lifecycle events are kept per container.
+const maxContainerEvents = 20
+
+// ContainerEvent is a lifecycle event of a container, e.g. its death,
+// with details such as its exit code.
+type ContainerEvent struct {
+	Time   time.Time
+	Type   string
+	Detail string
+}
+
 // StatsGatherer gathers container stats
 type StatsGatherer interface {
 	Stats(docker.StatsOptions) error
@@ -76,6 +91,7 @@ type StatsGatherer interface {
 // Container represents a Docker container
 type Container interface {
 	UpdateState(*docker.Container)
+	AddEvent(ContainerEvent)
 
 	ID() string
 	Image() string
@@ -99,6 +115,7 @@ type container struct {
 	latestStats            docker.Stats
 	pendingStats           [60]docker.Stats
 	numPending             int
+	events                 []ContainerEvent
 	hostID                 string
 	baseNode               report.Node
 	noCommandLineArguments bool
@@ -123,6 +140,17 @@ func (c *container) UpdateState(container *docker.Container) {
 	c.container = container
 }
 
+// AddEvent records a lifecycle event of the container, forgetting the
+// oldest one if there are too many.
+func (c *container) AddEvent(event ContainerEvent) {
+	c.Lock()
+	defer c.Unlock()
+	if len(c.events) >= maxContainerEvents {
+		c.events = append(c.events[:0], c.events[len(c.events)-maxContainerEvents+1:]...)
+	}
+	c.events = append(c.events, event)
+}
+
 func (c *container) ID() string {
 	return c.container.ID
 }
@@ -230,14 +258,22 @@ func (c *container) ports(localAddrs []net.IP) report.StringSet {
 			continue
 		}
 		for _, b := range bindings {
-			if b.HostIP != "0.0.0.0" {
-				ports = append(ports, fmt.Sprintf("%s:%s->%s", b.HostIP, b.HostPort, port))
+			// Bindings to all addresses of an IP version are
+			// reported for each local address of that version.
+			var ipv4 bool
+			switch b.HostIP {
+			case "0.0.0.0":
+				ipv4 = true
+			case "::":
+				ipv4 = false
+			default:
+				ports = append(ports, fmt.Sprintf("%s->%s", net.JoinHostPort(b.HostIP, b.HostPort), port))
 				continue
 			}
 
 			for _, ip := range localAddrs {
-				if ip.To4() != nil {
-					ports = append(ports, fmt.Sprintf("%s:%s->%s", ip, b.HostPort, port))
+				if (ip.To4() != nil) == ipv4 {
+					ports = append(ports, fmt.Sprintf("%s->%s", net.JoinHostPort(ip.String(), b.HostPort), port))
 				}
 			}
 		}
@@ -263,18 +299,17 @@ func addScopeToIPs(hostID string, ips []string) []string {
 	return ipsWithScopes
 }
 
-func isIPv4(addr string) bool {
-	ip := net.ParseIP(addr)
-	return ip != nil && ip.To4() != nil
-}
-
 func (c *container) NetworkInfo(localAddrs []net.IP) report.Sets {
 	c.RLock()
 	defer c.RUnlock()
 
-	ips := c.container.NetworkSettings.SecondaryIPAddresses
-	if c.container.NetworkSettings.IPAddress != "" {
-		ips = append(ips, c.container.NetworkSettings.IPAddress)
+	ips := []string{}
+	ips = append(ips, c.container.NetworkSettings.SecondaryIPAddresses...)
+	ips = append(ips, c.container.NetworkSettings.SecondaryIPv6Addresses...)
+	for _, ip := range []string{c.container.NetworkSettings.IPAddress, c.container.NetworkSettings.GlobalIPv6Address} {
+		if ip != "" {
+			ips = append(ips, ip)
+		}
 	}
 
 	// For now, for the proof-of-concept, we just add networks as a set of
@@ -287,20 +322,15 @@ func (c *container) NetworkInfo(localAddrs []net.IP) report.Sets {
 			continue
 		}
 		networks = append(networks, name)
-		if settings.IPAddress != "" {
-			ips = append(ips, settings.IPAddress)
+		for _, ip := range []string{settings.IPAddress, settings.GlobalIPv6Address} {
+			if ip != "" {
+				ips = append(ips, ip)
+			}
 		}
 	}
 
-	// Filter out IPv6 addresses; nothing works with IPv6 yet
-	ipv4s := []string{}
-	for _, ip := range ips {
-		if isIPv4(ip) {
-			ipv4s = append(ipv4s, ip)
-		}
-	}
 	// Treat all Docker IPs as local scoped.
-	ipsWithScopes := addScopeToIPs(c.hostID, ipv4s)
+	ipsWithScopes := addScopeToIPs(c.hostID, ips)
 
 	s := report.MakeSets()
 	if len(networks) > 0 {
@@ -309,8 +339,8 @@ func (c *container) NetworkInfo(localAddrs []net.IP) report.Sets {
 	if len(c.container.NetworkSettings.Ports) > 0 {
 		s = s.Add(ContainerPorts, c.ports(localAddrs))
 	}
-	if len(ipv4s) > 0 {
-		s = s.Add(ContainerIPs, report.MakeStringSet(ipv4s...))
+	if len(ips) > 0 {
+		s = s.Add(ContainerIPs, report.MakeStringSet(ips...))
 	}
 	if len(ipsWithScopes) > 0 {
 		s = s.Add(ContainerIPsWithScopes, report.MakeStringSet(ipsWithScopes...))
@@ -446,9 +476,30 @@ func (c *container) GetNode() report.Node {
 	result := c.baseNode.WithLatests(latest)
 	result = result.WithLatestControls(controls)
 	result = result.WithMetrics(c.metrics())
+	if len(c.events) > 0 {
+		result = result.AddPrefixMulticolumnTable(ContainerEventsPrefix, c.eventRows())
+	}
 	return result
 }
 
+// eventRows are the rows of the table of lifecycle events, sorted by
+// time as their IDs are. The type in the IDs tells apart events of the
+// same time, e.g. the creation and start of a container.
+func (c *container) eventRows() []report.Row {
+	rows := make([]report.Row, 0, len(c.events))
+	for _, e := range c.events {
+		rows = append(rows, report.Row{
+			ID: fmt.Sprintf("%020d-%s", e.Time.UnixNano(), e.Type),
+			Entries: map[string]string{
+				ContainerEventTime:   e.Time.UTC().Format(time.RFC3339Nano),
+				ContainerEventType:   e.Type,
+				ContainerEventDetail: e.Detail,
+			},
+		})
+	}
+	return rows
+}
+
 // ExtractContainerIPs returns the list of container IPs given a Node from the Container topology.
 func ExtractContainerIPs(nmd report.Node) []string {
 	v, _ := nmd.Sets.Lookup(ContainerIPs)
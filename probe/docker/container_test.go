@@ -131,6 +131,41 @@ func TestContainer(t *testing.T) {
 	}
 }
 
+func TestContainerEvents(t *testing.T) {
+	c := docker.NewContainer(container1, "scope", false, false)
+	for i := 0; i < 25; i++ {
+		c.AddEvent(docker.ContainerEvent{Time: startTime.Add(time.Duration(i) * time.Second), Type: docker.StartEvent})
+	}
+	c.AddEvent(docker.ContainerEvent{Time: startTime.Add(time.Minute), Type: docker.DieEvent, Detail: "exit code 137"})
+
+	rows := c.GetNode().ExtractMulticolumnTable(docker.ContainerTableTemplates[docker.ContainerEventsPrefix])
+	if len(rows) != 20 {
+		t.Fatalf("Expected the 20 latest events, got %d", len(rows))
+	}
+	if have := rows[0].Entries[docker.ContainerEventTime]; have != "2009-11-10T23:00:06Z" {
+		t.Errorf("Expected the oldest events to be forgotten, got one at %s", have)
+	}
+	want := map[string]string{
+		docker.ContainerEventTime:   "2009-11-10T23:01:00Z",
+		docker.ContainerEventType:   docker.DieEvent,
+		docker.ContainerEventDetail: "exit code 137",
+	}
+	if have := rows[len(rows)-1].Entries; !reflect.DeepEqual(want, have) {
+		t.Errorf("%v != %v", have, want)
+	}
+}
+
+func TestContainerEventsAtTheSameTime(t *testing.T) {
+	c := docker.NewContainer(container1, "scope", false, false)
+	c.AddEvent(docker.ContainerEvent{Time: startTime, Type: docker.CreateEvent})
+	c.AddEvent(docker.ContainerEvent{Time: startTime, Type: docker.StartEvent})
+
+	rows := c.GetNode().ExtractMulticolumnTable(docker.ContainerTableTemplates[docker.ContainerEventsPrefix])
+	if len(rows) != 2 {
+		t.Fatalf("Expected both events, got %v", rows)
+	}
+}
+
 func TestContainerHidingArgs(t *testing.T) {
 	const hostID = "scope"
 	c := docker.NewContainer(container1, hostID, true, false)
@@ -166,3 +201,34 @@ func TestContainerHidingBoth(t *testing.T) {
 		}
 	})
 }
+
+func TestContainerIPv6(t *testing.T) {
+	const hostID = "scope"
+	c := docker.NewContainer(&client.Container{
+		ID:     "ping",
+		Config: &client.Config{},
+		NetworkSettings: &client.NetworkSettings{
+			IPAddress:         "1.2.3.4",
+			GlobalIPv6Address: "fd00::4",
+			Ports: map[client.Port][]client.PortBinding{
+				client.Port("80/tcp"): {
+					{HostIP: "0.0.0.0", HostPort: "8080"},
+					{HostIP: "::", HostPort: "8080"},
+				},
+			},
+			Networks: map[string]client.ContainerNetwork{
+				"network1": {GlobalIPv6Address: "fd00:1::8"},
+			},
+		},
+	}, hostID, false, false)
+
+	want := report.MakeSets().
+		Add("docker_container_ports", report.MakeStringSet("10.0.0.1:8080->80/tcp", "[fd00::1]:8080->80/tcp")).
+		Add("docker_container_networks", report.MakeStringSet("network1")).
+		Add("docker_container_ips", report.MakeStringSet("1.2.3.4", "fd00::4", "fd00:1::8")).
+		Add("docker_container_ips_with_scopes", report.MakeStringSet(";1.2.3.4", ";fd00::4", ";fd00:1::8"))
+	have := c.NetworkInfo([]net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("fd00::1")})
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("%v != %v", have, want)
+	}
+}
@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"strings"
 	"sync"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/armon/go-radix"
 	docker_client "github.com/fsouza/go-dockerclient"
 
+	"github.com/weaveworks/common/mtime"
 	"github.com/weaveworks/scope/probe/controls"
 	"github.com/weaveworks/scope/report"
 )
@@ -21,6 +23,9 @@ const (
 	DieEvent               = "die"
 	PauseEvent             = "pause"
 	UnpauseEvent           = "unpause"
+	OOMEvent               = "oom"
+	RestartEvent           = "restart"
+	HealthStatusEvent      = "health_status"
 	NetworkConnectEvent    = "network:connect"
 	NetworkDisconnectEvent = "network:disconnect"
 )
@@ -106,13 +111,19 @@ type RegistryOptions struct {
 	DockerEndpoint         string
 	NoCommandLineArguments bool
 	NoEnvironmentVariables bool
+	// Client to use instead of connecting to DockerEndpoint, e.g. one
+	// of a CRI runtime
+	Client Client
 }
 
 // NewRegistry returns a usable Registry. Don't forget to Stop it.
 func NewRegistry(options RegistryOptions) (Registry, error) {
-	client, err := NewDockerClientStub(options.DockerEndpoint)
-	if err != nil {
-		return nil, err
+	client := options.Client
+	if client == nil {
+		var err error
+		if client, err = NewDockerClientStub(options.DockerEndpoint); err != nil {
+			return nil, err
+		}
 	}
 
 	r := &registry{
@@ -262,7 +273,7 @@ func (r *registry) updateContainers() error {
 	}
 
 	for _, apiContainer := range apiContainers {
-		r.updateContainerState(apiContainer.ID, nil)
+		r.updateContainerState(apiContainer.ID, nil, nil)
 	}
 
 	return nil
@@ -299,12 +310,41 @@ func (r *registry) updateNetworks() error {
 
 func (r *registry) handleEvent(event *docker_client.APIEvents) {
 	// TODO: Send shortcut reports on networks being created/destroyed?
-	switch event.Status {
-	case CreateEvent, RenameEvent, StartEvent, DieEvent, DestroyEvent, PauseEvent, UnpauseEvent, NetworkConnectEvent, NetworkDisconnectEvent:
-		r.updateContainerState(event.ID, stateAfterEvent(event.Status))
+	status := event.Status
+	// Health checks report their result in the status of their event,
+	// e.g. "health_status: unhealthy"
+	if strings.HasPrefix(status, HealthStatusEvent+":") {
+		status = HealthStatusEvent
+	}
+	switch status {
+	case CreateEvent, RenameEvent, StartEvent, DieEvent, DestroyEvent, PauseEvent, UnpauseEvent, NetworkConnectEvent, NetworkDisconnectEvent, OOMEvent, RestartEvent, HealthStatusEvent:
+		r.updateContainerState(event.ID, stateAfterEvent(status), lifecycleEvent(status, event))
 	}
 }
 
+// lifecycleEvent returns the event to keep in the history of a
+// container, if any.
+func lifecycleEvent(status string, event *docker_client.APIEvents) *ContainerEvent {
+	result := &ContainerEvent{Type: status, Time: mtime.Now()}
+	if event.TimeNano != 0 {
+		result.Time = time.Unix(0, event.TimeNano)
+	} else if event.Time != 0 {
+		result.Time = time.Unix(event.Time, 0)
+	}
+	switch status {
+	case DieEvent:
+		if exitCode, ok := event.Actor.Attributes["exitCode"]; ok {
+			result.Detail = "exit code " + exitCode
+		}
+	case HealthStatusEvent:
+		result.Detail = strings.TrimSpace(strings.TrimPrefix(event.Status, HealthStatusEvent+":"))
+	case CreateEvent, StartEvent, OOMEvent, RestartEvent:
+	default:
+		return nil
+	}
+	return result
+}
+
 func stateAfterEvent(event string) *string {
 	switch event {
 	case DestroyEvent:
@@ -314,7 +354,9 @@ func stateAfterEvent(event string) *string {
 	}
 }
 
-func (r *registry) updateContainerState(containerID string, intendedState *string) {
+// updateContainerState inspects a container, recording event in its
+// history if any.
+func (r *registry) updateContainerState(containerID string, intendedState *string, event *ContainerEvent) {
 	r.Lock()
 	defer r.Unlock()
 
@@ -364,6 +406,9 @@ func (r *registry) updateContainerState(containerID string, intendedState *strin
 		delete(r.containersByPID, c.PID())
 		c.UpdateState(dockerContainer)
 	}
+	if event != nil {
+		c.AddEvent(*event)
+	}
 
 	// Update PID index
 	if c.PID() > 1 {
@@ -36,6 +36,8 @@ type mockContainer struct {
 
 func (c *mockContainer) UpdateState(_ *client.Container) {}
 
+func (c *mockContainer) AddEvent(docker.ContainerEvent) {}
+
 func (c *mockContainer) ID() string {
 	return c.c.ID
 }
@@ -515,3 +517,56 @@ func TestRegistryDelete(t *testing.T) {
 		}
 	})
 }
+
+func TestRegistryContainerEvents(t *testing.T) {
+	mdc := newMockClient()
+	setupStubs(mdc, func() {
+		docker.NewContainerStub = docker.NewContainer
+		registry := testRegistry()
+		defer registry.Stop()
+		runtime.Gosched()
+
+		check := func(want []map[string]string) {
+			test.Poll(t, 100*time.Millisecond, want, func() interface{} {
+				have := []map[string]string{}
+				registry.WalkContainers(func(c docker.Container) {
+					for _, row := range c.GetNode().ExtractMulticolumnTable(docker.ContainerTableTemplates[docker.ContainerEventsPrefix]) {
+						have = append(have, row.Entries)
+					}
+				})
+				return have
+			})
+		}
+		check([]map[string]string{})
+
+		mdc.send(&client.APIEvents{
+			Status:   docker.DieEvent,
+			ID:       "ping",
+			Actor:    client.APIActor{ID: "ping", Attributes: map[string]string{"exitCode": "137"}},
+			TimeNano: startTime.UnixNano(),
+		})
+		mdc.send(&client.APIEvents{Status: docker.OOMEvent, ID: "ping", Time: startTime.Unix() + 1})
+		mdc.send(&client.APIEvents{Status: "health_status: unhealthy", ID: "ping", Time: startTime.Unix() + 2})
+		// Not a lifecycle event
+		mdc.send(&client.APIEvents{Status: docker.PauseEvent, ID: "ping", Time: startTime.Unix() + 3})
+		runtime.Gosched()
+
+		check([]map[string]string{
+			{
+				docker.ContainerEventTime:   "2009-11-10T23:00:00Z",
+				docker.ContainerEventType:   docker.DieEvent,
+				docker.ContainerEventDetail: "exit code 137",
+			},
+			{
+				docker.ContainerEventTime:   "2009-11-10T23:00:01Z",
+				docker.ContainerEventType:   docker.OOMEvent,
+				docker.ContainerEventDetail: "",
+			},
+			{
+				docker.ContainerEventTime:   "2009-11-10T23:00:02Z",
+				docker.ContainerEventType:   docker.HealthStatusEvent,
+				docker.ContainerEventDetail: "unhealthy",
+			},
+		})
+	})
+}
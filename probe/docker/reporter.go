@@ -78,6 +78,17 @@ var (
 			Type:   report.PropertyListType,
 			Prefix: EnvPrefix,
 		},
+		ContainerEventsPrefix: {
+			ID:     ContainerEventsPrefix,
+			Label:  "Events",
+			Type:   report.MulticolumnTableType,
+			Prefix: ContainerEventsPrefix,
+			Columns: []report.Column{
+				{ID: ContainerEventTime, Label: "Time", DataType: report.DateTime},
+				{ID: ContainerEventType, Label: "Event"},
+				{ID: ContainerEventDetail, Label: "Details"},
+			},
+		},
 	}
 
 	ContainerImageTableTemplates = report.TableTemplates{
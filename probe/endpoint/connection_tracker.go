@@ -5,6 +5,7 @@ import (
 	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/weaveworks/common/mtime"
 	"github.com/weaveworks/scope/probe/endpoint/procspy"
 	"github.com/weaveworks/scope/probe/process"
 	"github.com/weaveworks/scope/report"
@@ -18,6 +19,7 @@ type connectionTrackerConfig struct {
 	UseConntrack bool
 	WalkProc     bool
 	UseEbpfConn  bool
+	EbpfListen   bool
 	ProcRoot     string
 	BufferSize   int
 	ProcessCache *process.CachingWalker
@@ -33,8 +35,16 @@ type connectionTracker struct {
 
 	// time of the previous ebpf failure, or zero if it didn't fail
 	ebpfLastFailureTime time.Time
+
+	// listening sockets found by the last scan under eBPF, and its time
+	ebpfListening        listeningSockets
+	ebpfListeningScanned time.Time
 }
 
+// ebpfListeningInterval is how often /proc is walked for listening
+// sockets when tracking connections with eBPF, as those change seldom.
+const ebpfListeningInterval = 10 * time.Second
+
 func newConnectionTracker(conf connectionTrackerConfig) connectionTracker {
 	ct := connectionTracker{
 		conf:            conf,
@@ -44,6 +54,11 @@ func newConnectionTracker(conf connectionTrackerConfig) connectionTracker {
 		et, err := newEbpfTracker()
 		if err == nil {
 			ct.ebpfTracker = et
+			// eBPF only tracks connections; listening sockets are
+			// still found by scanning /proc, unless disabled.
+			if conf.EbpfListen && conf.WalkProc && conf.SpyProcs && conf.Scanner == nil {
+				ct.conf.Scanner = procspy.NewConnectionScanner(conf.ProcessCache, conf.SpyProcs)
+			}
 			go ct.getInitialState()
 			return ct
 		}
@@ -72,6 +87,20 @@ func flowToTuple(f flow) (ft fourTuple) {
 	return ft
 }
 
+// flowToEdgeMetadata returns the edge metadata of a flow, as seen from
+// the originating end. Counters are only present when conntrack
+// accounting is enabled.
+func flowToEdgeMetadata(f flow, now time.Time) report.EdgeMetadata {
+	return report.EdgeMetadata{
+		Connections:        1,
+		EgressPacketCount:  f.Original.Packets,
+		IngressPacketCount: f.Reply.Packets,
+		EgressByteCount:    f.Original.Bytes,
+		IngressByteCount:   f.Reply.Bytes,
+		LastSeen:           now,
+	}
+}
+
 func (t *connectionTracker) useProcfs() {
 	t.ebpfTracker = nil
 	if t.conf.WalkProc && t.conf.Scanner == nil {
@@ -89,6 +118,7 @@ func (t *connectionTracker) ReportConnections(rpt *report.Report) {
 	if t.ebpfTracker != nil {
 		if !t.ebpfTracker.isDead() {
 			t.performEbpfTrack(rpt, hostNodeID)
+			t.performEbpfListening(rpt)
 			return
 		}
 
@@ -109,6 +139,7 @@ func (t *connectionTracker) ReportConnections(rpt *report.Report) {
 			if err == nil {
 				go t.getInitialState()
 				t.performEbpfTrack(rpt, hostNodeID)
+				t.performEbpfListening(rpt)
 				return
 			}
 			log.Warnf("could not restart ebpf tracker, falling back to proc scanning: %v", err)
@@ -118,10 +149,12 @@ func (t *connectionTracker) ReportConnections(rpt *report.Report) {
 
 	// consult the flowWalker for short-lived (conntracked) connections
 	seenTuples := map[string]fourTuple{}
+	now := mtime.Now()
 	t.flowWalker.walkFlows(func(f flow, alive bool) {
 		tuple := flowToTuple(f)
-		seenTuples[tuple.key()] = tuple
-		t.addConnection(rpt, false, tuple, "", nil, nil)
+		seenTuples[tuple.key(f.Original.Layer4.Proto)] = tuple
+		extra := transportInfo(f.Original.Layer4.Proto)
+		t.addConnection(rpt, false, tuple, "", extra, extra, flowToEdgeMetadata(f, now))
 	})
 
 	if t.conf.WalkProc && t.conf.Scanner != nil {
@@ -135,41 +168,103 @@ func (t *connectionTracker) existingFlows() map[string]fourTuple {
 		// log.Warnf("Not using conntrack: disabled")
 	} else if err := IsConntrackSupported(t.conf.ProcRoot); err != nil {
 		log.Warnf("Not using conntrack: not supported by the kernel: %s", err)
-	} else if existingFlows, err := existingConnections([]string{"--any-nat"}); err != nil {
-		log.Errorf("conntrack existingConnections error: %v", err)
 	} else {
-		for _, f := range existingFlows {
-			tuple := flowToTuple(f)
-			seenTuples[tuple.key()] = tuple
+		for _, proto := range conntrackProtocols {
+			existingFlows, err := existingConnections(proto, []string{"--any-nat"})
+			if err != nil {
+				log.Errorf("conntrack existingConnections error: %v", err)
+				break
+			}
+			for _, f := range existingFlows {
+				tuple := flowToTuple(f)
+				seenTuples[tuple.key(proto)] = tuple
+			}
 		}
 	}
 	return seenTuples
 }
 
 func (t *connectionTracker) performWalkProc(rpt *report.Report, hostNodeID string, seenTuples map[string]fourTuple) error {
-	conns, err := t.conf.Scanner.Connections()
+	conns, err := t.conf.Scanner.Sockets()
 	if err != nil {
 		return err
 	}
+	md := report.EdgeMetadata{Connections: 1, LastSeen: mtime.Now()}
+	listening := listeningSockets{}
 	for conn := conns.Next(); conn != nil; conn = conns.Next() {
+		if conn.Listening {
+			listening.add(conn)
+			continue
+		}
 		tuple, namespaceID, incoming := connectionTuple(conn, seenTuples)
-		var toNodeInfo, fromNodeInfo map[string]string
+		var (
+			toNodeInfo   = transportInfo(conn.Transport)
+			fromNodeInfo = transportInfo(conn.Transport)
+		)
 		if conn.Proc.PID > 0 {
-			fromNodeInfo = map[string]string{
-				process.PID:       strconv.FormatUint(uint64(conn.Proc.PID), 10),
-				report.HostNodeID: hostNodeID,
+			if fromNodeInfo == nil {
+				fromNodeInfo = map[string]string{}
 			}
+			fromNodeInfo[process.PID] = strconv.FormatUint(uint64(conn.Proc.PID), 10)
+			fromNodeInfo[report.HostNodeID] = hostNodeID
 		}
-		t.addConnection(rpt, incoming, tuple, namespaceID, fromNodeInfo, toNodeInfo)
+		t.addConnection(rpt, incoming, tuple, namespaceID, fromNodeInfo, toNodeInfo, md)
 	}
+	listening.report(rpt, t.conf.HostID)
 	return nil
 }
 
+// performEbpfListening scans /proc for listening sockets only, as
+// eBPF tracks the connections, if a scanner was set up for it. Scans
+// are ebpfListeningInterval apart; the last one is reported in between.
+func (t *connectionTracker) performEbpfListening(rpt *report.Report) {
+	if t.conf.Scanner == nil {
+		return
+	}
+	if now := mtime.Now(); now.Sub(t.ebpfListeningScanned) >= ebpfListeningInterval {
+		sockets, err := t.conf.Scanner.Sockets()
+		if err != nil {
+			log.Errorf("Error scanning listening sockets: %v", err)
+			return
+		}
+		listening := listeningSockets{}
+		for s := sockets.Next(); s != nil; s = sockets.Next() {
+			if s.Listening {
+				listening.add(s)
+			}
+		}
+		t.ebpfListening, t.ebpfListeningScanned = listening, now
+	}
+	t.ebpfListening.report(rpt, t.conf.HostID)
+}
+
+// listeningSockets holds the rows of the listening sockets table of
+// each process, by PID.
+type listeningSockets map[uint][]report.Row
+
+func (l listeningSockets) add(s *procspy.Connection) {
+	// Sockets not bound to a port, or not attributed to a process,
+	// are of no interest.
+	if s.LocalPort == 0 || s.Proc.PID == 0 {
+		return
+	}
+	l[s.Proc.PID] = append(l[s.Proc.PID], process.ListeningSocketRow(s.Transport, s.LocalAddress.String(), s.LocalPort))
+}
+
+// report adds the sockets processes listen on to their nodes in the
+// Process topology, as a table.
+func (l listeningSockets) report(rpt *report.Report, hostID string) {
+	for pid, rows := range l {
+		nodeID := report.MakeProcessNodeID(hostID, strconv.FormatUint(uint64(pid), 10))
+		rpt.Process.AddNode(report.MakeNode(nodeID).AddPrefixMulticolumnTable(process.ListeningSocketsTablePrefix, rows))
+	}
+}
+
 // getInitialState runs conntrack and proc parsing synchronously only
 // once to initialize ebpfTracker
 func (t *connectionTracker) getInitialState() {
 	var processCache *process.CachingWalker
-	walker := process.NewWalker(t.conf.ProcRoot, true)
+	walker := process.NewWalker(t.conf.ProcRoot, true, false)
 	processCache = process.NewCachingWalker(walker)
 	processCache.Tick()
 
@@ -195,6 +290,7 @@ func (t *connectionTracker) getInitialState() {
 }
 
 func (t *connectionTracker) performEbpfTrack(rpt *report.Report, hostNodeID string) error {
+	now := mtime.Now()
 	t.ebpfTracker.walkConnections(func(e ebpfConnection) {
 		var toNodeInfo, fromNodeInfo map[string]string
 		if e.pid > 0 {
@@ -203,29 +299,44 @@ func (t *connectionTracker) performEbpfTrack(rpt *report.Report, hostNodeID stri
 				report.HostNodeID: hostNodeID,
 			}
 		}
-		t.addConnection(rpt, e.incoming, e.tuple, e.networkNamespace, fromNodeInfo, toNodeInfo)
+		md := report.EdgeMetadata{Connections: 1, FirstSeen: e.firstSeen, LastSeen: now}
+		t.addConnection(rpt, e.incoming, e.tuple, e.networkNamespace, fromNodeInfo, toNodeInfo, md)
 	})
 	return nil
 }
 
-func (t *connectionTracker) addConnection(rpt *report.Report, incoming bool, ft fourTuple, namespaceID string, extraFromNode, extraToNode map[string]string) {
+// addConnection adds an edge between the two ends of ft to the report.
+// md describes the edge as seen from ft.fromAddr.
+func (t *connectionTracker) addConnection(rpt *report.Report, incoming bool, ft fourTuple, namespaceID string, extraFromNode, extraToNode map[string]string, md report.EdgeMetadata) {
 	if incoming {
 		ft = reverse(ft)
 		extraFromNode, extraToNode = extraToNode, extraFromNode
+		md = md.Reversed()
 	}
 	var (
 		fromNode = t.makeEndpointNode(namespaceID, ft.fromAddr, ft.fromPort, extraFromNode)
 		toNode   = t.makeEndpointNode(namespaceID, ft.toAddr, ft.toPort, extraToNode)
 	)
-	rpt.Endpoint.AddNode(fromNode.WithAdjacent(toNode.ID))
+	rpt.Endpoint.AddNode(fromNode.WithEdge(toNode.ID, md))
 	rpt.Endpoint.AddNode(toNode)
 	t.addDNS(rpt, ft.fromAddr)
 	t.addDNS(rpt, ft.toAddr)
 }
 
+// transportInfo returns the metadata marking the endpoints of a
+// connection over transport; TCP, the default, is not marked.
+func transportInfo(transport string) map[string]string {
+	if transport == "" || transport == tcpProto {
+		return nil
+	}
+	return map[string]string{Transport: transport}
+}
+
+// makeEndpointNode returns the node of an endpoint, which is specific
+// to the transport marked in extra, if any.
 func (t *connectionTracker) makeEndpointNode(namespaceID string, addr string, port uint16, extra map[string]string) report.Node {
 	portStr := strconv.Itoa(int(port))
-	node := report.MakeNodeWith(report.MakeEndpointNodeID(t.conf.HostID, namespaceID, addr, portStr), nil)
+	node := report.MakeNodeWith(report.MakeTransportEndpointNodeID(t.conf.HostID, namespaceID, addr, portStr, extra[Transport]), nil)
 	if extra != nil {
 		node = node.WithLatests(extra)
 	}
@@ -273,6 +384,10 @@ func connectionTuple(conn *procspy.Connection, seenTuples map[string]fourTuple)
 	// (or have already figured it out), so we normalize and use the
 	// canonical direction. Otherwise, we can use a port-heuristic to guess
 	// the direction.
-	canonical, ok := seenTuples[tuple.key()]
+	transport := conn.Transport
+	if transport == "" {
+		transport = tcpProto
+	}
+	canonical, ok := seenTuples[tuple.key(transport)]
 	return tuple, namespaceID, (ok && canonical != tuple) || (!ok && tuple.fromPort < tuple.toPort)
 }
@@ -24,11 +24,21 @@ const (
 
 	timeWait    = "TIME_WAIT"
 	tcpProto    = "tcp"
+	udpProto    = "udp"
 	newType     = "[NEW]"
 	updateType  = "[UPDATE]"
 	destroyType = "[DESTROY]"
 )
 
+// Protocols of the flows tracked. conntrack only streams events for a
+// single protocol at a time, so each has its own conntrack process.
+var conntrackProtocols = []string{tcpProto, udpProto}
+
+// Layer 3 families of the flows tracked. conntrack only lists flows of
+// a single family at a time, IPv4 by default, but streams events for
+// all of them.
+var conntrackFamilies = []string{"ipv4", "ipv6"}
+
 var (
 	destroyTypeB = []byte(destroyType)
 	assured      = []byte("[ASSURED] ")
@@ -47,10 +57,12 @@ type layer4 struct {
 }
 
 type meta struct {
-	Layer3 layer3
-	Layer4 layer4
-	ID     int64
-	State  string
+	Layer3  layer3
+	Layer4  layer4
+	ID      int64
+	State   string
+	Packets uint64
+	Bytes   uint64
 }
 
 type flow struct {
@@ -78,9 +90,9 @@ func (n nilFlowWalker) walkFlows(f func(flow, bool)) {}
 // implement flowWalker.
 type conntrackWalker struct {
 	sync.Mutex
-	cmd           exec.Cmd
-	activeFlows   map[int64]flow // active flows in state != TIME_WAIT
-	bufferedFlows []flow         // flows coming out of activeFlows spend 1 walk cycle here
+	cmds          map[string]exec.Cmd // by protocol
+	activeFlows   map[int64]flow      // active flows in state != TIME_WAIT
+	bufferedFlows []flow              // flows coming out of activeFlows spend 1 walk cycle here
 	bufferSize    int
 	args          []string
 	quit          chan struct{}
@@ -95,12 +107,15 @@ func newConntrackFlowWalker(useConntrack bool, procRoot string, bufferSize int,
 		return nilFlowWalker{}
 	}
 	result := &conntrackWalker{
+		cmds:        map[string]exec.Cmd{},
 		activeFlows: map[int64]flow{},
 		bufferSize:  bufferSize,
 		args:        args,
 		quit:        make(chan struct{}),
 	}
-	go result.loop()
+	for _, proto := range conntrackProtocols {
+		go result.loop(proto)
+	}
 	return result
 }
 
@@ -118,15 +133,15 @@ var IsConntrackSupported = func(procRoot string) error {
 	return nil
 }
 
-func (c *conntrackWalker) loop() {
+func (c *conntrackWalker) loop(proto string) {
 	// conntrack can sometimes fail with ENOBUFS, when there is a particularly
 	// high connection rate.  In these cases just retry in a loop, so we can
 	// survive the spike.  For sustained loads this degrades nicely, as we
 	// read the table before starting to handle events - basically degrading to
 	// polling.
 	for {
-		c.run()
-		c.clearFlows()
+		c.run(proto)
+		c.clearFlows(proto)
 
 		select {
 		case <-time.After(time.Second):
@@ -136,15 +151,17 @@ func (c *conntrackWalker) loop() {
 	}
 }
 
-func (c *conntrackWalker) clearFlows() {
+func (c *conntrackWalker) clearFlows(proto string) {
 	c.Lock()
 	defer c.Unlock()
 
-	for _, f := range c.activeFlows {
+	for id, f := range c.activeFlows {
+		if f.Original.Layer4.Proto != proto {
+			continue
+		}
 		c.bufferedFlows = append(c.bufferedFlows, f)
+		delete(c.activeFlows, id)
 	}
-
-	c.activeFlows = map[int64]flow{}
 }
 
 func logPipe(prefix string, reader io.Reader) {
@@ -157,10 +174,10 @@ func logPipe(prefix string, reader io.Reader) {
 	}
 }
 
-func (c *conntrackWalker) run() {
+func (c *conntrackWalker) run(proto string) {
 	// Fork another conntrack, just to capture existing connections
 	// for which we don't get events
-	existingFlows, err := existingConnections(c.args)
+	existingFlows, err := existingConnections(proto, c.args)
 	if err != nil {
 		log.Errorf("conntrack existingConnections error: %v", err)
 		return
@@ -171,7 +188,7 @@ func (c *conntrackWalker) run() {
 
 	args := append([]string{
 		"--buffer-size", strconv.Itoa(c.bufferSize), "-E",
-		"-o", "id", "-p", "tcp"}, c.args...,
+		"-o", "id", "-p", proto}, c.args...,
 	)
 	cmd := exec.Command("conntrack", args...)
 	stdout, err := cmd.StdoutPipe()
@@ -207,7 +224,7 @@ func (c *conntrackWalker) run() {
 	case <-c.quit:
 		return
 	}
-	c.cmd = cmd
+	c.cmds[proto] = cmd
 	c.Unlock()
 
 	scanner := bufio.NewScanner(bufio.NewReader(stdout))
@@ -255,8 +272,9 @@ func removeInplace(s, sep []byte) []byte {
 // decodeFlowKeyValues parses the key-values from a conntrack line and updates the flow
 // It only considers the following key-values:
 // src=127.0.0.1 dst=127.0.0.1 sport=58958 dport=6784 src=127.0.0.1 dst=127.0.0.1 sport=6784 dport=58958 id=1595499776
+// and, when conntrack accounting is enabled, the packets= and bytes= following each tuple.
 // Keys can be present twice, so the order is important.
-// Conntrack could add other key-values such as secctx=, packets=, bytes=. Those are ignored.
+// Conntrack could add other key-values such as secctx=. Those are ignored.
 func decodeFlowKeyValues(line []byte, f *flow) error {
 	var err error
 	for _, field := range strings.FieldsFunc(string(line), func(c rune) bool { return unicode.IsSpace(c) }) {
@@ -296,6 +314,20 @@ func decodeFlowKeyValues(line []byte, f *flow) error {
 				f.Reply.Layer4.DstPort, err = strconv.Atoi(value)
 			}
 
+		case key == "packets":
+			if f.Reply.Layer3.SrcIP == "" {
+				f.Original.Packets, err = strconv.ParseUint(value, 10, 64)
+			} else {
+				f.Reply.Packets, err = strconv.ParseUint(value, 10, 64)
+			}
+
+		case key == "bytes":
+			if f.Reply.Layer3.SrcIP == "" {
+				f.Original.Bytes, err = strconv.ParseUint(value, 10, 64)
+			} else {
+				f.Reply.Bytes, err = strconv.ParseUint(value, 10, 64)
+			}
+
 		case key == "id":
 			f.Independent.ID, err = strconv.ParseInt(value, 10, 64)
 		}
@@ -312,7 +344,7 @@ func decodeStreamedFlow(scanner *bufio.Scanner) (flow, error) {
 		f      flow
 	)
 
-	// Examples:
+	// Examples (note how UDP flows have no state field):
 	// " [UPDATE] udp      17 29 src=192.168.2.100 dst=192.168.2.1 sport=57767 dport=53 src=192.168.2.1 dst=192.168.2.100 sport=53 dport=57767"
 	// "    [NEW] tcp      6 120 SYN_SENT src=127.0.0.1 dst=127.0.0.1 sport=58958 dport=6784 [UNREPLIED] src=127.0.0.1 dst=127.0.0.1 sport=6784 dport=58958 id=1595499776"
 	// " [UPDATE] tcp      6 120 TIME_WAIT src=10.0.2.15 dst=10.0.2.15 sport=51154 dport=4040 src=10.0.2.15 dst=10.0.2.15 sport=4040 dport=51154 [ASSURED] id=3663628160"
@@ -344,6 +376,7 @@ func decodeStreamedFlow(scanner *bufio.Scanner) (flow, error) {
 	if err != nil {
 		return flow{}, fmt.Errorf("Error parsing streamed flow %q: %v ", line, err)
 	}
+	f.Independent.State = stateOrNothing(f.Independent.State)
 
 	err = decodeFlowKeyValues(line, &f)
 	if err != nil {
@@ -354,8 +387,29 @@ func decodeStreamedFlow(scanner *bufio.Scanner) (flow, error) {
 	return f, nil
 }
 
-func existingConnections(conntrackWalkerArgs []string) ([]flow, error) {
-	args := append([]string{"-L", "-o", "id", "-p", "tcp"}, conntrackWalkerArgs...)
+// stateOrNothing returns field, unless it is not a state but the first
+// key-value of a flow of a stateless protocol, like UDP.
+func stateOrNothing(field string) string {
+	if strings.Contains(field, "=") {
+		return ""
+	}
+	return field
+}
+
+func existingConnections(proto string, conntrackWalkerArgs []string) ([]flow, error) {
+	var result []flow
+	for _, family := range conntrackFamilies {
+		flows, err := existingFamilyConnections(family, proto, conntrackWalkerArgs)
+		result = append(result, flows...)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func existingFamilyConnections(family, proto string, conntrackWalkerArgs []string) ([]flow, error) {
+	args := append([]string{"-L", "-o", "id", "-f", family, "-p", proto}, conntrackWalkerArgs...)
 	cmd := exec.Command("conntrack", args...)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -402,6 +456,7 @@ func decodeDumpedFlow(scanner *bufio.Scanner) (flow, error) {
 	// "tcp      6 431998 ESTABLISHED src=10.0.2.2 dst=10.0.2.15 sport=49911 dport=22 src=10.0.2.15 dst=10.0.2.2 sport=22 dport=49911 [ASSURED] mark=0 use=1 id=2993966208"
 	// "tcp      6 108 ESTABLISHED src=172.17.0.5 dst=172.17.0.2 sport=47010 dport=80 src=172.17.0.2 dst=172.17.0.5 sport=80 dport=47010 [ASSURED] mark=0 secctx=system_u:object_r:unlabeled_t:s0 use=1 id=4001098880"
 	// "tcp      6 431970 ESTABLISHED src=192.168.35.116 dst=216.58.213.227 sport=49862 dport=443 packets=11 bytes=1337 src=216.58.213.227 dst=192.168.35.116 sport=443 dport=49862 packets=8 bytes=716 [ASSURED] mark=0 secctx=system_u:object_r:unlabeled_t:s0 use=1 id=943643840"
+	// "udp      17 28 src=172.17.0.3 dst=10.96.0.10 sport=41278 dport=53 src=10.96.0.10 dst=172.17.0.3 sport=53 dport=41278 mark=0 use=1 id=2147883392"
 
 	// remove tags since they are optional and make parsing harder
 	line, err := getUntaggedLine(scanner)
@@ -413,6 +468,7 @@ func decodeDumpedFlow(scanner *bufio.Scanner) (flow, error) {
 	if err != nil {
 		return flow{}, fmt.Errorf("Error parsing dumped flow %q: %v ", line, err)
 	}
+	f.Independent.State = stateOrNothing(f.Independent.State)
 
 	err = decodeFlowKeyValues(line, &f)
 	if err != nil {
@@ -427,8 +483,8 @@ func (c *conntrackWalker) stop() {
 	c.Lock()
 	defer c.Unlock()
 	close(c.quit)
-	if c.cmd != nil {
-		c.cmd.Kill()
+	for _, cmd := range c.cmds {
+		cmd.Kill()
 	}
 }
 
@@ -436,13 +492,15 @@ func (c *conntrackWalker) handleFlow(f flow, forceAdd bool) {
 	c.Lock()
 	defer c.Unlock()
 
-	// For not, I'm only interested in tcp connections - there is too much udp
-	// traffic going on (every container talking to weave dns, for example) to
-	// render nicely. TODO: revisit this.
-	if f.Original.Layer4.Proto != tcpProto {
-		return
+	switch f.Original.Layer4.Proto {
+	case tcpProto:
+		c.handleTCPFlow(f, forceAdd)
+	case udpProto:
+		c.handleUDPFlow(f, forceAdd)
 	}
+}
 
+func (c *conntrackWalker) handleTCPFlow(f flow, forceAdd bool) {
 	// Ignore flows for which we never saw an update; they are likely
 	// incomplete or wrong.  See #1462.
 	switch {
@@ -461,6 +519,22 @@ func (c *conntrackWalker) handleFlow(f flow, forceAdd bool) {
 	}
 }
 
+func (c *conntrackWalker) handleUDPFlow(f flow, forceAdd bool) {
+	// Unlike TCP, a UDP flow only sees an update once it is replied to,
+	// which one-way flows never are, so new flows are taken too. They
+	// are active until conntrack destroys them, once they have been
+	// idle for nf_conntrack_udp_timeout{,_stream}.
+	switch {
+	case forceAdd || f.Type == newType || f.Type == updateType:
+		c.activeFlows[f.Independent.ID] = f
+	case f.Type == destroyType:
+		if active, ok := c.activeFlows[f.Independent.ID]; ok {
+			delete(c.activeFlows, f.Independent.ID)
+			c.bufferedFlows = append(c.bufferedFlows, active)
+		}
+	}
+}
+
 // walkFlows calls f with all active flows and flows that have come and gone
 // since the last call to walkFlows
 func (c *conntrackWalker) walkFlows(f func(flow, bool)) {
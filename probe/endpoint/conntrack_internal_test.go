@@ -3,10 +3,12 @@ package endpoint
 import (
 	"bufio"
 	"io"
+	"net"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/weaveworks/scope/probe/endpoint/procspy"
 	"github.com/weaveworks/scope/test"
 )
 
@@ -229,6 +231,8 @@ var wantDumpedFlows = []flow{
 				DstPort: 443,
 				Proto:   "tcp",
 			},
+			Packets: 11,
+			Bytes:   1337,
 		},
 		Reply: meta{
 			Layer3: layer3{
@@ -240,6 +244,8 @@ var wantDumpedFlows = []flow{
 				DstPort: 49862,
 				Proto:   "tcp",
 			},
+			Packets: 8,
+			Bytes:   716,
 		},
 		Independent: meta{
 			ID:    943643840,
@@ -251,3 +257,98 @@ var wantDumpedFlows = []flow{
 func TestDumpedFlowDecoding(t *testing.T) {
 	testFlowDecoding(t, dumpedFlowsSource, wantDumpedFlows, decodeDumpedFlow)
 }
+
+// Obtained through conntrack -L -f ipv6 -p tcp -o id
+const dumpedIPv6FlowsSource = `tcp      6 431999 ESTABLISHED src=fd00::5 dst=fd00::2 sport=47010 dport=80 src=fd00::2 dst=fd00::5 sport=80 dport=47010 [ASSURED] mark=0 use=1 id=4001098880`
+
+func TestDumpedIPv6FlowDecoding(t *testing.T) {
+	testFlowDecoding(t, dumpedIPv6FlowsSource, []flow{
+		{
+			Original: meta{
+				Layer3: layer3{SrcIP: "fd00::5", DstIP: "fd00::2"},
+				Layer4: layer4{SrcPort: 47010, DstPort: 80, Proto: "tcp"},
+			},
+			Reply: meta{
+				Layer3: layer3{SrcIP: "fd00::2", DstIP: "fd00::5"},
+				Layer4: layer4{SrcPort: 80, DstPort: 47010, Proto: "tcp"},
+			},
+			Independent: meta{ID: 4001098880, State: "ESTABLISHED"},
+		},
+	}, decodeDumpedFlow)
+}
+
+// Obtained through conntrack -E -o id
+const streamedUDPFlowsSource = `    [NEW] udp      17 30 src=172.17.0.3 dst=10.96.0.10 sport=41278 dport=53 [UNREPLIED] src=10.96.0.10 dst=172.17.0.3 sport=53 dport=41278 id=2147883392
+ [DESTROY] udp      17 src=172.17.0.3 dst=10.96.0.10 sport=41278 dport=53 src=10.96.0.10 dst=172.17.0.3 sport=53 dport=41278 id=2147883392`
+
+func udpFlow(flowType string) flow {
+	return flow{
+		Type: flowType,
+		Original: meta{
+			Layer3: layer3{SrcIP: "172.17.0.3", DstIP: "10.96.0.10"},
+			Layer4: layer4{SrcPort: 41278, DstPort: 53, Proto: "udp"},
+		},
+		Reply: meta{
+			Layer3: layer3{SrcIP: "10.96.0.10", DstIP: "172.17.0.3"},
+			Layer4: layer4{SrcPort: 53, DstPort: 41278, Proto: "udp"},
+		},
+		Independent: meta{ID: 2147883392},
+	}
+}
+
+func TestStreamedUDPFlowDecoding(t *testing.T) {
+	testFlowDecoding(t, streamedUDPFlowsSource, []flow{udpFlow(newType), udpFlow(destroyType)}, decodeStreamedFlow)
+}
+
+func TestUDPFlows(t *testing.T) {
+	walked := func(c *conntrackWalker) (active, buffered int) {
+		c.walkFlows(func(_ flow, alive bool) {
+			if alive {
+				active++
+			} else {
+				buffered++
+			}
+		})
+		return active, buffered
+	}
+
+	// One-way flows are tracked until destroyed
+	c := &conntrackWalker{activeFlows: map[int64]flow{}}
+	c.handleFlow(udpFlow(newType), false)
+	if active, buffered := walked(c); active != 1 || buffered != 0 {
+		t.Errorf("Expected 1 active flow, got %d active and %d buffered", active, buffered)
+	}
+	c.handleFlow(udpFlow(destroyType), false)
+	if active, buffered := walked(c); active != 0 || buffered != 1 {
+		t.Errorf("Expected 1 buffered flow, got %d active and %d buffered", active, buffered)
+	}
+
+	// Restarting the UDP event stream only clears UDP flows
+	c.handleFlow(udpFlow(newType), false)
+	c.handleFlow(flow{Type: updateType, Original: meta{Layer4: layer4{Proto: "tcp"}}, Independent: meta{ID: 1, State: "ESTABLISHED"}}, false)
+	c.clearFlows(udpProto)
+	if active, buffered := walked(c); active != 1 || buffered != 1 {
+		t.Errorf("Expected 1 active and 1 buffered flow, got %d active and %d buffered", active, buffered)
+	}
+}
+
+func TestConnectionTupleTransport(t *testing.T) {
+	// conntrack saw a udp flow from port 53 to port 1234 ...
+	udp := fourTuple{"10.0.0.1", "10.0.0.2", 53, 1234}
+	seenTuples := map[string]fourTuple{udp.key(udpProto): udp}
+	// ... which tells nothing of a tcp connection between the same ports
+	conn := &procspy.Connection{
+		Transport:     tcpProto,
+		LocalAddress:  net.ParseIP("10.0.0.2"),
+		LocalPort:     1234,
+		RemoteAddress: net.ParseIP("10.0.0.1"),
+		RemotePort:    53,
+	}
+	if _, _, incoming := connectionTuple(conn, seenTuples); incoming {
+		t.Errorf("Expected the tcp connection to be outgoing, by its ports")
+	}
+	conn.Transport = udpProto
+	if _, _, incoming := connectionTuple(conn, seenTuples); !incoming {
+		t.Errorf("Expected the udp connection to be incoming, as seen by conntrack")
+	}
+}
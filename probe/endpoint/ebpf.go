@@ -10,9 +10,11 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/weaveworks/common/fs"
+	"github.com/weaveworks/common/mtime"
 	"github.com/weaveworks/scope/probe/endpoint/procspy"
 	"github.com/weaveworks/scope/probe/host"
 	"github.com/weaveworks/scope/probe/process"
@@ -25,6 +27,7 @@ type ebpfConnection struct {
 	networkNamespace string
 	incoming         bool
 	pid              int
+	firstSeen        time.Time
 }
 
 // EbpfTracker contains the sets of open and closed TCP connections.
@@ -36,6 +39,7 @@ type EbpfTracker struct {
 	stopping        bool
 	dead            bool
 	lastTimestampV4 uint64
+	lastTimestampV6 uint64
 
 	// debugBPF specifies if EbpfTracker must be started in debug mode. This
 	// allows to easily debug issues like:
@@ -140,6 +144,20 @@ func newEbpfTracker() (*EbpfTracker, error) {
 
 // TCPEventV4 handles IPv4 TCP events from the eBPF tracer
 func (t *EbpfTracker) TCPEventV4(e tracer.TcpV4) {
+	tuple := fourTuple{e.SAddr.String(), e.DAddr.String(), e.SPort, e.DPort}
+	t.handleEvent(&t.lastTimestampV4, e.Timestamp, e.Type, int(e.Pid), int(e.Fd), tuple, e.NetNS)
+}
+
+// TCPEventV6 handles IPv6 TCP events from the eBPF tracer
+func (t *EbpfTracker) TCPEventV6(e tracer.TcpV6) {
+	tuple := fourTuple{e.SAddr.String(), e.DAddr.String(), e.SPort, e.DPort}
+	t.handleEvent(&t.lastTimestampV6, e.Timestamp, e.Type, int(e.Pid), int(e.Fd), tuple, e.NetNS)
+}
+
+// handleEvent handles the TCP events of either IP version. IPv4 and
+// IPv6 events come in separate streams, each ordered by timestamp, so
+// each version keeps its own lastTimestamp.
+func (t *EbpfTracker) handleEvent(lastTimestamp *uint64, timestamp uint64, ev tracer.EventType, pid, fd int, tuple fourTuple, netNS uint32) {
 	if t.debugBPF {
 		debugBPFFile := "/var/run/scope/debug-bpf"
 		b, err := ioutil.ReadFile("/var/run/scope/debug-bpf")
@@ -151,42 +169,35 @@ func (t *EbpfTracker) TCPEventV4(e tracer.TcpV4) {
 		}
 	}
 
-	if t.lastTimestampV4 > e.Timestamp {
+	if *lastTimestamp > timestamp {
 		// A kernel bug can cause the timestamps to be wrong (e.g. on Ubuntu with Linux 4.4.0-47.68)
 		// Upgrading the kernel will fix the problem. For further info see:
 		// https://github.com/iovisor/bcc/issues/790#issuecomment-263704235
 		// https://github.com/weaveworks/scope/issues/2334
-		log.Errorf("tcp tracer received event with timestamp %v even though the last timestamp was %v. Stopping the eBPF tracker.", e.Timestamp, t.lastTimestampV4)
+		log.Errorf("tcp tracer received event with timestamp %v even though the last timestamp was %v. Stopping the eBPF tracker.", timestamp, *lastTimestamp)
 		t.stop()
 		return
 	}
 
-	t.lastTimestampV4 = e.Timestamp
+	*lastTimestamp = timestamp
 
-	if e.Type == tracer.EventFdInstall {
-		t.handleFdInstall(e.Type, int(e.Pid), int(e.Fd))
+	if ev == tracer.EventFdInstall {
+		t.handleFdInstall(ev, pid, fd)
 	} else {
-		tuple := fourTuple{e.SAddr.String(), e.DAddr.String(), e.SPort, e.DPort}
-		t.handleConnection(e.Type, tuple, int(e.Pid), strconv.Itoa(int(e.NetNS)))
+		t.handleConnection(ev, tuple, pid, strconv.Itoa(int(netNS)))
 	}
 }
 
-// TCPEventV6 handles IPv6 TCP events from the eBPF tracer. This is
-// currently a no-op.
-func (t *EbpfTracker) TCPEventV6(e tracer.TcpV6) {
-	// TODO: IPv6 not supported in Scope
-}
-
 // LostV4 handles IPv4 TCP event misses from the eBPF tracer.
 func (t *EbpfTracker) LostV4(count uint64) {
 	log.Errorf("tcp tracer lost %d events. Stopping the eBPF tracker", count)
 	t.stop()
 }
 
-// LostV6 handles IPv4 TCP event misses from the eBPF tracer. This is
-// currently a no-op.
+// LostV6 handles IPv6 TCP event misses from the eBPF tracer.
 func (t *EbpfTracker) LostV6(count uint64) {
-	// TODO: IPv6 not supported in Scope
+	log.Errorf("tcp tracer lost %d IPv6 events. Stopping the eBPF tracker", count)
+	t.stop()
 }
 
 func tupleFromPidFd(pid int, fd int) (tuple fourTuple, netns string, ok bool) {
@@ -256,6 +267,7 @@ func (t *EbpfTracker) handleFdInstall(ev tracer.EventType, pid int, fd int) {
 		tuple:            tuple,
 		pid:              pid,
 		networkNamespace: netns,
+		firstSeen:        mtime.Now(),
 	}
 }
 
@@ -273,6 +285,7 @@ func (t *EbpfTracker) handleConnection(ev tracer.EventType, tuple fourTuple, pid
 			tuple:            tuple,
 			pid:              pid,
 			networkNamespace: networkNamespace,
+			firstSeen:        mtime.Now(),
 		}
 	case tracer.EventAccept:
 		t.openConnections[tuple] = ebpfConnection{
@@ -280,6 +293,7 @@ func (t *EbpfTracker) handleConnection(ev tracer.EventType, tuple fourTuple, pid
 			tuple:            tuple,
 			pid:              pid,
 			networkNamespace: networkNamespace,
+			firstSeen:        mtime.Now(),
 		}
 	case tracer.EventClose:
 		if !t.ready {
@@ -314,6 +328,10 @@ func (t *EbpfTracker) walkConnections(f func(ebpfConnection)) {
 func (t *EbpfTracker) feedInitialConnections(conns procspy.ConnIter, seenTuples map[string]fourTuple, processesWaitingInAccept []int, hostNodeID string) {
 	t.Lock()
 	for conn := conns.Next(); conn != nil; conn = conns.Next() {
+		// We only get events for TCP connections
+		if conn.Transport != "" && conn.Transport != tcpProto {
+			continue
+		}
 		tuple, namespaceID, incoming := connectionTuple(conn, seenTuples)
 		if _, ok := t.closedDuringInit[tuple]; !ok {
 			if _, ok := t.openConnections[tuple]; !ok {
@@ -322,6 +340,7 @@ func (t *EbpfTracker) feedInitialConnections(conns procspy.ConnIter, seenTuples
 					tuple:            tuple,
 					pid:              int(conn.Proc.PID),
 					networkNamespace: namespaceID,
+					firstSeen:        mtime.Now(),
 				}
 			}
 		}
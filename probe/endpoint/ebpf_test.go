@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/weaveworks/common/mtime"
 	"github.com/weaveworks/tcptracer-bpf/pkg/tracer"
 
 	"github.com/weaveworks/scope/probe/host"
@@ -22,6 +23,10 @@ func newMockEbpfTracker() *EbpfTracker {
 }
 
 func TestHandleConnection(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	mtime.NowForce(now)
+	defer mtime.NowReset()
+
 	var (
 		ServerPid  uint32 = 42
 		ClientPid  uint32 = 43
@@ -53,6 +58,7 @@ func TestHandleConnection(t *testing.T) {
 			networkNamespace: strconv.Itoa(int(NetNS)),
 			incoming:         false,
 			pid:              int(ClientPid),
+			firstSeen:        now,
 		}
 
 		IPv4ConnectCloseEvent = tracer.TcpV4{
@@ -89,6 +95,7 @@ func TestHandleConnection(t *testing.T) {
 			networkNamespace: strconv.Itoa(int(NetNS)),
 			incoming:         true,
 			pid:              int(ServerPid),
+			firstSeen:        now,
 		}
 
 		IPv4AcceptCloseEvent = tracer.TcpV4{
@@ -236,6 +243,42 @@ func TestInvalidTimeStampDead(t *testing.T) {
 	}
 }
 
+func TestTCPEventV6(t *testing.T) {
+	var (
+		ServerIP = net.ParseIP("fd00::1")
+		ClientIP = net.ParseIP("fd00::2")
+		event    = tracer.TcpV6{
+			Timestamp: 10,
+			Type:      tracer.EventConnect,
+			Pid:       43,
+			Comm:      "cmd",
+			SAddr:     ClientIP,
+			DAddr:     ServerIP,
+			SPort:     6789,
+			DPort:     12345,
+			NetNS:     123456789,
+		}
+		want = ebpfConnection{
+			tuple:            fourTuple{"fd00::2", "fd00::1", 6789, 12345},
+			networkNamespace: "123456789",
+			incoming:         false,
+			pid:              43,
+		}
+	)
+	mockEbpfTracker := newMockEbpfTracker()
+	// IPv4 and IPv6 timestamps are ordered independently
+	mockEbpfTracker.lastTimestampV4 = 20
+	mockEbpfTracker.TCPEventV6(event)
+	if mockEbpfTracker.isDead() {
+		t.Errorf("expected ebpfTracker to be alive after an IPv6 event")
+	}
+	have := mockEbpfTracker.openConnections[want.tuple]
+	have.firstSeen = time.Time{}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Expected %v, got %v", want, have)
+	}
+}
+
 func TestIsKernelSupported(t *testing.T) {
 	var release, version string
 	oldGetKernelReleaseAndVersion := host.GetKernelReleaseAndVersion
@@ -18,15 +18,16 @@ func (t fourTuple) String() string {
 	return fmt.Sprintf("%s:%d-%s:%d", t.fromAddr, t.fromPort, t.toAddr, t.toPort)
 }
 
-// key is a sortable direction-independent key for tuples, used to look up a
-// fourTuple when you are unsure of its direction.
-func (t fourTuple) key() string {
+// key is a sortable direction-independent key for tuples of the given
+// transport protocol, used to look up a fourTuple when you are unsure of
+// its direction.
+func (t fourTuple) key(transport string) string {
 	key := []string{
 		fmt.Sprintf("%s:%d", t.fromAddr, t.fromPort),
 		fmt.Sprintf("%s:%d", t.toAddr, t.toPort),
 	}
 	sort.Strings(key)
-	return strings.Join(key, " ")
+	return transport + " " + strings.Join(key, " ")
 }
 
 // reverse flips the direction of the tuple
@@ -0,0 +1,343 @@
+package endpoint
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/scope/report"
+)
+
+// Node metrics keys of the HTTP sniffer.
+const (
+	HTTPRequestRate = "http_requests_per_second"
+	HTTPErrorRate   = "http_errors_per_second"
+	HTTPLatencyP50  = "http_latency_p50_ms"
+	HTTPLatencyP95  = "http_latency_p95_ms"
+	HTTPLatencyP99  = "http_latency_p99_ms"
+)
+
+const (
+	// Requests of a connection still waiting for a response, beyond
+	// which the oldest are dropped
+	maxPendingHTTPRequests = 100
+	// Latencies sampled per endpoint and report, to compute percentiles
+	maxHTTPLatencySamples = 1000
+	// Connections without traffic for longer are forgotten
+	httpFlowTimeout = time.Minute
+)
+
+// HTTPMetricTemplates are the templates of the HTTP metrics of server
+// endpoints.
+var HTTPMetricTemplates = report.MetricTemplates{
+	HTTPRequestRate: {ID: HTTPRequestRate, Label: "HTTP requests/s", Format: report.DefaultFormat, Priority: 10},
+	HTTPErrorRate:   {ID: HTTPErrorRate, Label: "HTTP errors/s", Format: report.DefaultFormat, Priority: 11},
+	HTTPLatencyP50:  {ID: HTTPLatencyP50, Label: "HTTP latency p50 (ms)", Format: report.DefaultFormat, Priority: 12},
+	HTTPLatencyP95:  {ID: HTTPLatencyP95, Label: "HTTP latency p95 (ms)", Format: report.DefaultFormat, Priority: 13},
+	HTTPLatencyP99:  {ID: HTTPLatencyP99, Label: "HTTP latency p99 (ms)", Format: report.DefaultFormat, Priority: 14},
+}
+
+// HTTPLatencyMetrics are the HTTP metrics which, unlike rates, don't
+// add up across endpoints; the highest is the one to show.
+var HTTPLatencyMetrics = map[string]struct{}{
+	HTTPLatencyP50: {},
+	HTTPLatencyP95: {},
+	HTTPLatencyP99: {},
+}
+
+var (
+	httpMethods = [][]byte{
+		[]byte("GET "), []byte("POST "), []byte("PUT "), []byte("DELETE "), []byte("HEAD "),
+		[]byte("OPTIONS "), []byte("PATCH "), []byte("CONNECT "), []byte("TRACE "),
+	}
+	httpVersionPrefix = []byte("HTTP/1.")
+)
+
+// HTTPSniffer is a Reporter of the rate, errors and latency of HTTP/1.x
+// requests, as metrics of the endpoints serving them. It follows the
+// requests and responses seen on the wire on a set of server ports.
+type HTTPSniffer struct {
+	hostID      string
+	namespaceID string // of the network namespace the packets are captured in
+	tracker     *httpTracker
+	stop        chan struct{}
+	closeSource func() // unblocks the reader of the packet source
+}
+
+// Name of this reporter, for metrics gathering
+func (*HTTPSniffer) Name() string { return "HTTP" }
+
+// Report implements Reporter.
+func (s *HTTPSniffer) Report() (report.Report, error) {
+	rpt := report.MakeReport()
+	rpt.Endpoint = s.tracker.report(s.hostID, s.namespaceID)
+	return rpt, nil
+}
+
+// Stop makes the sniffer stop inspecting HTTP communications
+func (s *HTTPSniffer) Stop() {
+	close(s.stop)
+	if s.closeSource != nil {
+		s.closeSource()
+	}
+}
+
+// packetSource is a source of captured packets, live or from a file.
+type packetSource interface {
+	ReadPacketData() ([]byte, gopacket.CaptureInfo, error)
+	LinkType() layers.LinkType
+}
+
+// httpFlow is a TCP connection to a server port.
+type httpFlow struct {
+	requests                        []time.Time // times of the requests awaiting a response
+	lastRequestSeq, lastResponseSeq uint32
+	lastSeen                        time.Time
+}
+
+// httpServer is the address and port of a server endpoint.
+type httpServer struct {
+	addr string
+	port uint16
+}
+
+// httpStats are the requests made to a server endpoint since the last
+// report.
+type httpStats struct {
+	requests, errors int
+	latencies        []float64 // in milliseconds
+}
+
+// httpTracker matches the requests and responses of the connections to
+// a set of server ports, accumulating statistics per server endpoint.
+type httpTracker struct {
+	ports map[uint16]struct{}
+
+	sync.Mutex
+	flows      map[fourTuple]*httpFlow // keyed with the client as from
+	stats      map[httpServer]*httpStats
+	lastReport time.Time
+}
+
+func newHTTPTracker(ports []uint16) *httpTracker {
+	t := &httpTracker{
+		ports:      map[uint16]struct{}{},
+		flows:      map[fourTuple]*httpFlow{},
+		stats:      map[httpServer]*httpStats{},
+		lastReport: mtime.Now(),
+	}
+	for _, port := range ports {
+		t.ports[port] = struct{}{}
+	}
+	return t
+}
+
+// readPackets decodes the packets of source and tracks the HTTP
+// messages they carry, until the source is exhausted or stop is
+// closed.
+func (t *httpTracker) readPackets(source packetSource, stop <-chan struct{}) error {
+	var (
+		decodedLayers []gopacket.LayerType
+		tcp           layers.TCP
+		ip4           layers.IPv4
+		ip6           layers.IPv6
+		eth           layers.Ethernet
+		dot1q         layers.Dot1Q
+		sll           layers.LinuxSLL
+	)
+	firstLayer := layers.LayerTypeEthernet
+	if source.LinkType() == layers.LinkTypeLinuxSLL {
+		// Captures on the "any" interface (see https://wiki.wireshark.org/SLL)
+		firstLayer = layers.LayerTypeLinuxSLL
+	}
+	packetParser := gopacket.NewDecodingLayerParser(firstLayer, &sll, &dot1q, &eth, &ip4, &ip6, &tcp)
+	// The TCP payload is what we are after; there is no layer for it
+	packetParser.IgnoreUnsupported = true
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		packet, ci, err := source.ReadPacketData()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := packetParser.DecodeLayers(packet, &decodedLayers); err != nil {
+			continue
+		}
+
+		var src, dst string
+		for _, layerType := range decodedLayers {
+			switch layerType {
+			case layers.LayerTypeIPv4:
+				src, dst = ip4.SrcIP.String(), ip4.DstIP.String()
+			case layers.LayerTypeIPv6:
+				src, dst = ip6.SrcIP.String(), ip6.DstIP.String()
+			case layers.LayerTypeTCP:
+				if src != "" {
+					t.handleSegment(fourTuple{src, dst, uint16(tcp.SrcPort), uint16(tcp.DstPort)}, tcp.Seq, tcp.Payload, ci.Timestamp)
+				}
+			}
+		}
+	}
+}
+
+// handleSegment looks for the start of an HTTP request or response in
+// the payload of a TCP segment. Messages spanning several segments are
+// told apart by their first one.
+func (t *httpTracker) handleSegment(tuple fourTuple, seq uint32, payload []byte, timestamp time.Time) {
+	if len(payload) == 0 {
+		return
+	}
+	_, toServer := t.ports[tuple.toPort]
+	_, fromServer := t.ports[tuple.fromPort]
+	switch {
+	case toServer && isHTTPRequest(payload):
+		t.Lock()
+		defer t.Unlock()
+		flow := t.flow(tuple, timestamp)
+		// The same packet is seen on every interface it goes through
+		if flow.lastRequestSeq == seq {
+			return
+		}
+		flow.lastRequestSeq = seq
+		if len(flow.requests) >= maxPendingHTTPRequests {
+			flow.requests = flow.requests[1:]
+		}
+		flow.requests = append(flow.requests, timestamp)
+	case fromServer:
+		status, ok := httpResponseStatus(payload)
+		if !ok {
+			return
+		}
+		t.Lock()
+		defer t.Unlock()
+		flow, ok := t.flows[reverse(tuple)]
+		if !ok || len(flow.requests) == 0 || flow.lastResponseSeq == seq {
+			return
+		}
+		flow.lastResponseSeq = seq
+		flow.lastSeen = timestamp
+		// HTTP/1.x answers the requests of a connection in order
+		request := flow.requests[0]
+		flow.requests = flow.requests[1:]
+
+		server := httpServer{addr: tuple.fromAddr, port: tuple.fromPort}
+		stats, ok := t.stats[server]
+		if !ok {
+			stats = &httpStats{}
+			t.stats[server] = stats
+		}
+		if status >= 400 {
+			stats.errors++
+		}
+		stats.addRequest(float64(timestamp.Sub(request)) / float64(time.Millisecond))
+	}
+}
+
+// addRequest counts a request answered with the given latency, keeping
+// a uniform sample of the latencies of all the requests counted, so
+// that the latest requests are represented too.
+func (s *httpStats) addRequest(latency float64) {
+	s.requests++
+	if len(s.latencies) < maxHTTPLatencySamples {
+		s.latencies = append(s.latencies, latency)
+	} else if i := rand.Intn(s.requests); i < maxHTTPLatencySamples {
+		s.latencies[i] = latency
+	}
+}
+
+// flow returns the flow of tuple, creating it if need be. The tracker
+// must be locked.
+func (t *httpTracker) flow(tuple fourTuple, timestamp time.Time) *httpFlow {
+	flow, ok := t.flows[tuple]
+	if !ok {
+		flow = &httpFlow{}
+		t.flows[tuple] = flow
+	}
+	flow.lastSeen = timestamp
+	return flow
+}
+
+// report returns the server endpoints which answered requests since
+// the last report, with their HTTP metrics, and starts over. Endpoints
+// are scoped like those of the connections seen in namespaceID.
+func (t *httpTracker) report(hostID, namespaceID string) report.Topology {
+	t.Lock()
+	defer t.Unlock()
+
+	now := mtime.Now()
+	interval := now.Sub(t.lastReport).Seconds()
+	t.lastReport = now
+	for tuple, flow := range t.flows {
+		if now.Sub(flow.lastSeen) > httpFlowTimeout {
+			delete(t.flows, tuple)
+		}
+	}
+	topology := report.MakeTopology().WithMetricTemplates(HTTPMetricTemplates)
+	if interval <= 0 {
+		return topology
+	}
+	for server, stats := range t.stats {
+		nodeID := report.MakeEndpointNodeID(hostID, namespaceID, server.addr, strconv.Itoa(int(server.port)))
+		metrics := report.Metrics{
+			HTTPRequestRate: report.MakeSingletonMetric(now, float64(stats.requests)/interval),
+			HTTPErrorRate:   report.MakeSingletonMetric(now, float64(stats.errors)/interval),
+		}
+		sort.Float64s(stats.latencies)
+		for id, p := range map[string]float64{HTTPLatencyP50: 50, HTTPLatencyP95: 95, HTTPLatencyP99: 99} {
+			metrics[id] = report.MakeSingletonMetric(now, percentile(stats.latencies, p))
+		}
+		topology.AddNode(report.MakeNode(nodeID).WithMetrics(metrics))
+	}
+	t.stats = map[httpServer]*httpStats{}
+	log.Debugf("HTTPSniffer: reported %d endpoints", len(topology.Nodes))
+	return topology
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted, which
+// must not be empty.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func isHTTPRequest(payload []byte) bool {
+	for _, method := range httpMethods {
+		if bytes.HasPrefix(payload, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpResponseStatus returns the status code of a response starting
+// with a status line, e.g. "HTTP/1.1 200 OK".
+func httpResponseStatus(payload []byte) (int, bool) {
+	if !bytes.HasPrefix(payload, httpVersionPrefix) || len(payload) < len("HTTP/1.x 200") {
+		return 0, false
+	}
+	code := payload[len("HTTP/1.x "):len("HTTP/1.x 200")]
+	status, err := strconv.Atoi(string(code))
+	if err != nil {
+		return 0, false
+	}
+	return status, true
+}
@@ -0,0 +1,109 @@
+package endpoint
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/pcapgo"
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/scope/report"
+)
+
+func TestHTTPSnifferFromPcap(t *testing.T) {
+	start := time.Now()
+	mtime.NowForce(start)
+	defer mtime.NowReset()
+
+	f, err := os.Open("testdata/http.pcap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reader, err := pcapgo.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := newHTTPTracker([]uint16{80})
+	if err := tracker.readPackets(reader, make(chan struct{})); err != nil {
+		t.Fatal(err)
+	}
+
+	now := start.Add(2 * time.Second)
+	mtime.NowForce(now)
+	topology := tracker.report("host", "4026531993")
+
+	// The capture holds 3 requests to port 80, 2 of them failed, answered
+	// in 10, 30 and 40ms; a request to port 8080 and a duplicate packet
+	// are ignored.
+	serverID := report.MakeEndpointNodeID("host", "4026531993", "10.0.0.2", "80")
+	if want, have := 1, len(topology.Nodes); want != have {
+		t.Fatalf("want %d nodes, have %d: %v", want, have, topology.Nodes)
+	}
+	node, ok := topology.Nodes[serverID]
+	if !ok {
+		t.Fatalf("Expected node %q", serverID)
+	}
+	for id, want := range map[string]float64{
+		HTTPRequestRate: 1.5,
+		HTTPErrorRate:   1,
+		HTTPLatencyP50:  30,
+		HTTPLatencyP95:  40,
+		HTTPLatencyP99:  40,
+	} {
+		metric, ok := node.Metrics.Lookup(id)
+		if !ok {
+			t.Errorf("Expected metric %q", id)
+			continue
+		}
+		if have, _ := metric.LastSample(); !reflect.DeepEqual(have.Value, want) {
+			t.Errorf("%s: want %v, have %v", id, want, have.Value)
+		}
+	}
+
+	// Stats start over after each report
+	if have := tracker.report("host", "4026531993"); len(have.Nodes) != 0 {
+		t.Errorf("Expected no nodes, have %v", have.Nodes)
+	}
+}
+
+func TestHTTPStatsSampleLatencies(t *testing.T) {
+	stats := httpStats{}
+	for i := 0; i < 10*maxHTTPLatencySamples; i++ {
+		stats.addRequest(float64(i))
+	}
+	if want, have := 10*maxHTTPLatencySamples, stats.requests; want != have {
+		t.Errorf("want %d requests, have %d", want, have)
+	}
+	if want, have := maxHTTPLatencySamples, len(stats.latencies); want != have {
+		t.Fatalf("want %d latencies, have %d", want, have)
+	}
+	// The later requests must be sampled too, not only the first ones
+	later := 0
+	for _, latency := range stats.latencies {
+		if latency >= maxHTTPLatencySamples {
+			later++
+		}
+	}
+	if later < maxHTTPLatencySamples/2 {
+		t.Errorf("want most latencies from later requests, have %d of %d", later, len(stats.latencies))
+	}
+}
+
+func TestHTTPResponseStatus(t *testing.T) {
+	for payload, want := range map[string]int{
+		"HTTP/1.1 200 OK\r\n":           200,
+		"HTTP/1.0 503 Unavailable\r\n":  503,
+		"HTTP/1.1 2":                    0,
+		"HTTP/2.0 200 OK\r\n":           0,
+		"GET / HTTP/1.1\r\n":            0,
+		"HTTP/1.1 abc Not a status\r\n": 0,
+	} {
+		have, _ := httpResponseStatus([]byte(payload))
+		if want != have {
+			t.Errorf("%q: want %d, have %d", payload, want, have)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package endpoint
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/pcap"
+	"github.com/weaveworks/scope/probe/endpoint/procspy"
+)
+
+// httpPcapTimeout is the read timeout of the capture handle.
+const httpPcapTimeout = time.Second
+
+// NewHTTPSniffer creates a new sniffer of the HTTP requests made to the
+// given server ports, on all interfaces.
+func NewHTTPSniffer(hostID string, ports []uint16) (*HTTPSniffer, error) {
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("no ports to sniff")
+	}
+	pcapHandle, err := newHTTPPcapHandle(ports)
+	if err != nil {
+		return nil, err
+	}
+	// Captures only see the network namespace of the probe
+	var namespaceID string
+	if netns, err := procspy.ReadNetnsFromPID(os.Getpid()); err != nil {
+		log.Warnf("HTTPSniffer: cannot find the network namespace, loopback endpoints won't be scoped by it: %v", err)
+	} else {
+		namespaceID = strconv.FormatUint(netns, 10)
+	}
+	s := &HTTPSniffer{
+		hostID:      hostID,
+		namespaceID: namespaceID,
+		tracker:     newHTTPTracker(ports),
+		stop:        make(chan struct{}),
+		closeSource: pcapHandle.Close,
+	}
+	go func() {
+		if err := s.tracker.readPackets(livePacketSource{pcapHandle}, s.stop); err != nil {
+			log.Errorf("HTTPSniffer: error reading packet data: %s", err)
+		}
+	}()
+	return s, nil
+}
+
+func newHTTPPcapHandle(ports []uint16) (*pcap.Handle, error) {
+	inactive, err := pcap.NewInactiveHandle("any")
+	if err != nil {
+		return nil, err
+	}
+	defer inactive.CleanUp()
+	// Packets are delivered immediately; the timeout only bounds how
+	// long closing the handle waits for the reader to notice.
+	if err = inactive.SetTimeout(httpPcapTimeout); err != nil {
+		return nil, err
+	}
+	if err = inactive.SetImmediateMode(true); err != nil {
+		return nil, err
+	}
+	if err = inactive.SetBufferSize(bufSize); err != nil {
+		return nil, err
+	}
+	pcapHandle, err := inactive.Activate()
+	if err != nil {
+		return nil, err
+	}
+	filters := make([]string, 0, len(ports))
+	for _, port := range ports {
+		filters = append(filters, fmt.Sprintf("port %d", port))
+	}
+	if err := pcapHandle.SetBPFFilter("tcp and (" + strings.Join(filters, " or ") + ")"); err != nil {
+		pcapHandle.Close()
+		return nil, err
+	}
+	return pcapHandle, nil
+}
+
+// livePacketSource reads packets from a live capture, skipping
+// timeouts, until the handle is closed.
+type livePacketSource struct {
+	*pcap.Handle
+}
+
+func (s livePacketSource) ReadPacketData() ([]byte, gopacket.CaptureInfo, error) {
+	for {
+		data, ci, err := s.Handle.ReadPacketData()
+		// TimeoutExpired only means there was no traffic
+		if err != pcap.NextErrorTimeoutExpired {
+			return data, ci, err
+		}
+	}
+}
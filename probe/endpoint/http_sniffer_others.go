@@ -0,0 +1,13 @@
+// +build darwin arm
+
+package endpoint
+
+import (
+	"fmt"
+)
+
+// NewHTTPSniffer creates a new sniffer of the HTTP requests made to the
+// given server ports - not implemented, like the DNSSnooper.
+func NewHTTPSniffer(hostID string, ports []uint16) (*HTTPSniffer, error) {
+	return nil, fmt.Errorf("HTTP sniffing is not supported on this platform")
+}
@@ -54,8 +54,9 @@ func (n natMapper) applyNAT(rpt report.Report, scope string) {
 
 		realEndpointPort := strconv.Itoa(mapping.originalPort)
 		copyEndpointPort := strconv.Itoa(mapping.rewrittenPort)
-		realEndpointID := report.MakeEndpointNodeID(scope, "", mapping.originalIP, realEndpointPort)
-		copyEndpointID := report.MakeEndpointNodeID(scope, "", mapping.rewrittenIP, copyEndpointPort)
+		transport := f.Original.Layer4.Proto
+		realEndpointID := report.MakeTransportEndpointNodeID(scope, "", mapping.originalIP, realEndpointPort, transport)
+		copyEndpointID := report.MakeTransportEndpointNodeID(scope, "", mapping.rewrittenIP, copyEndpointPort, transport)
 
 		node, ok := rpt.Endpoint.Nodes[realEndpointID]
 		if !ok {
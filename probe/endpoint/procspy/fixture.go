@@ -23,5 +23,16 @@ func (s FixedScanner) Connections() (ConnIter, error) {
 	return &iter, nil
 }
 
+// Sockets implements ConnectionsScanner.Sockets, marking the
+// connections without a remote port as listening.
+func (s FixedScanner) Sockets() (ConnIter, error) {
+	iter := fixedConnIter{}
+	for _, c := range s {
+		c.Listening = c.RemotePort == 0
+		iter = append(iter, c)
+	}
+	return &iter, nil
+}
+
 // Stop implements ConnectionsScanner.Stop (dummy since there is no background work)
 func (s FixedScanner) Stop() {}
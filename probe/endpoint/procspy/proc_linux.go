@@ -98,6 +98,17 @@ func getNetNamespacePathSuffix() string {
 
 // ReadTCPFiles reads the proc files tcp and tcp6 for a pid
 func ReadTCPFiles(pid int, buf *bytes.Buffer) (int64, error) {
+	return readNetFiles(pid, "tcp", buf)
+}
+
+// readUDPFiles reads the proc files udp and udp6 for a pid
+func readUDPFiles(pid int, buf *bytes.Buffer) (int64, error) {
+	return readNetFiles(pid, "udp", buf)
+}
+
+// readNetFiles reads the proc files of a protocol, over IPv4 and IPv6,
+// for a pid
+func readNetFiles(pid int, protocol string, buf *bytes.Buffer) (int64, error) {
 	var (
 		errRead  error
 		errRead6 error
@@ -108,9 +119,9 @@ func ReadTCPFiles(pid int, buf *bytes.Buffer) (int64, error) {
 	// even for tcp4 connections, we need to read the "tcp6" file because of IPv4-Mapped IPv6 Addresses
 
 	dirName := strconv.Itoa(pid)
-	read, errRead = readFile(filepath.Join(procRoot, dirName, "/net/tcp"), buf)
+	read, errRead = readFile(filepath.Join(procRoot, dirName, "/net/", protocol), buf)
 	if ipv6IsSupported {
-		read6, errRead6 = readFile(filepath.Join(procRoot, dirName, "/net/tcp6"), buf)
+		read6, errRead6 = readFile(filepath.Join(procRoot, dirName, "/net/", protocol+"6"), buf)
 	}
 
 	if errRead != nil {
@@ -120,8 +131,8 @@ func ReadTCPFiles(pid int, buf *bytes.Buffer) (int64, error) {
 }
 
 // Read the connections for a group of processes living in the same namespace,
-// which are found (identically) in /proc/PID/net/tcp{,6} for any of the
-// processes.
+// which are found (identically) in /proc/PID/net/{tcp,udp}{,6} for any of
+// the processes.
 func readProcessConnections(buf *bytes.Buffer, namespaceProcs []*process.Process) (bool, error) {
 	var (
 		read int64
@@ -133,6 +144,10 @@ func readProcessConnections(buf *bytes.Buffer, namespaceProcs []*process.Process
 			// try next process
 			continue
 		}
+		// udp sockets are a bonus: don't give up on tcp ones without them
+		if readUDP, err := readUDPFiles(p.PID, buf); err == nil {
+			read += readUDP
+		}
 		// Return after succeeding on any process
 		// (proc/PID/net/tcp and proc/PID/net/tcp6 are identical for all the processes in the same namespace)
 		return read > 0, nil
@@ -5,15 +5,22 @@ import (
 	"net"
 )
 
-// Used to check whether we are parsing a header line
-var slHeader = []byte("sl")
+var (
+	// Used to check whether we are parsing a header line
+	slHeader = []byte("sl")
+	// Only the header of /proc/net/udp{,6} files has a drops column
+	udpHeaderSuffix = []byte("drops")
+)
 
-// ProcNet is an iterator to parse /proc/net/tcp{,6} files.
+// ProcNet is an iterator to parse /proc/net/tcp{,6} and
+// /proc/net/udp{,6} files, which may be concatenated.
 type ProcNet struct {
 	b                       []byte
 	c                       Connection
 	bytesLocal, bytesRemote [16]byte
 	seen                    map[uint64]struct{}
+	udp                     bool // whether we are parsing a udp file
+	listening               bool // whether to also return listening sockets
 }
 
 // NewProcNet gives a new ProcNet parser.
@@ -25,6 +32,15 @@ func NewProcNet(b []byte) *ProcNet {
 	}
 }
 
+// NewSocketsProcNet gives a new ProcNet parser returning listening tcp
+// sockets and bound, unconnected udp sockets, marked as Listening, as
+// well as connections.
+func NewSocketsProcNet(b []byte) *ProcNet {
+	p := NewProcNet(b)
+	p.listening = true
+	return p
+}
+
 // Next returns the next connection. All buffers are re-used, so if you want
 // to keep the IPs you have to copy them.
 func (p *ProcNet) Next() *Connection {
@@ -40,16 +56,25 @@ again:
 
 	sl, b = nextField(b) // 'sl' column
 	if bytes.Equal(sl, slHeader) {
-		// Skip header
+		// Skip header, noting which protocol the following lines are for
 		p.b = nextLine(b)
+		header := b[:len(b)-len(p.b)]
+		p.udp = bytes.HasSuffix(bytes.TrimRight(header, " \n"), udpHeaderSuffix)
 		goto again
 	}
 	local, b = nextField(b)
 	remote, b = nextField(b)
 	state, b = nextField(b)
-	switch parseHex(state) {
-	// Only process established or half-closed connections
-	case tcpEstablished, tcpFinWait1, tcpFinWait2, tcpCloseWait:
+	s := parseHex(state)
+	// Listening tcp sockets, and unconnected udp sockets (which are in
+	// the close state), are only processed when asked for
+	listening := (p.udp && s == tcpClose) || (!p.udp && s == tcpListen)
+	switch {
+	case listening && p.listening:
+	// Only process established or half-closed connections, and
+	// connected udp sockets (which are in the established state)
+	case p.udp && s == tcpEstablished:
+	case !p.udp && (s == tcpEstablished || s == tcpFinWait1 || s == tcpFinWait2 || s == tcpCloseWait):
 	default:
 		p.b = nextLine(b)
 		goto again
@@ -64,7 +89,17 @@ again:
 	p.c.LocalAddress, p.c.LocalPort = scanAddressNA(local, &p.bytesLocal)
 	p.c.RemoteAddress, p.c.RemotePort = scanAddressNA(remote, &p.bytesRemote)
 	p.c.Inode = parseDec(inode)
+	p.c.Listening = listening
+	p.c.Transport = "tcp"
+	if p.udp {
+		p.c.Transport = "udp"
+	}
 	p.b = nextLine(b)
+	// Only udp sockets bound to a port, without a remote address, are
+	// waiting for datagrams rather than sending them
+	if listening && p.udp && (p.c.LocalPort == 0 || p.c.RemotePort != 0 || !p.c.RemoteAddress.IsUnspecified()) {
+		goto again
+	}
 	if _, alreadySeen := p.seen[p.c.Inode]; alreadySeen {
 		goto again
 	}
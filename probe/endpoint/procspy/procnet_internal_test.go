@@ -20,6 +20,7 @@ func TestProcNet(t *testing.T) {
 			LocalPort:     0xa6c0,
 			RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
 			RemotePort:    0x0,
+			Transport:     "tcp",
 			Inode:         5107,
 		},
 		{
@@ -27,6 +28,7 @@ func TestProcNet(t *testing.T) {
 			LocalPort:     0x006f,
 			RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
 			RemotePort:    0x0,
+			Transport:     "tcp",
 			Inode:         5084,
 		},
 		{
@@ -34,6 +36,7 @@ func TestProcNet(t *testing.T) {
 			LocalPort:     0x0019,
 			RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
 			RemotePort:    0x0,
+			Transport:     "tcp",
 			Inode:         10550,
 		},
 		{
@@ -41,6 +44,7 @@ func TestProcNet(t *testing.T) {
 			LocalPort:     0xe4d7,
 			RemoteAddress: net.IP([]byte{0xc0, 0x1e, 0xfc, 0x57}),
 			RemotePort:    0x01bb,
+			Transport:     "tcp",
 			Inode:         639474,
 		},
 	}
@@ -73,7 +77,8 @@ func TestTransport6(t *testing.T) {
 			RemoteAddress: net.IP(make([]byte, 16)),
 			RemotePort:    0x0,
 			// uid:           0,
-			Inode: 23661201,
+			Transport: "tcp",
+			Inode:     23661201,
 		},
 		{
 			// state: 1,
@@ -92,7 +97,8 @@ func TestTransport6(t *testing.T) {
 			}),
 			RemotePort: 0x01bb,
 			// uid:        1000,
-			Inode: 36856710,
+			Transport: "tcp",
+			Inode:     36856710,
 		},
 	}
 
@@ -121,6 +127,7 @@ broken line
 			LocalPort:     0xa6c0,
 			RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
 			RemotePort:    0x0,
+			Transport:     "tcp",
 		},
 	}
 
@@ -137,6 +144,111 @@ broken line
 
 }
 
+func TestProcNetUDP(t *testing.T) {
+	// /proc/net/tcp followed by /proc/net/udp
+	testString := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0019 00000000:0000 01 00000000:00000000 00:00000000 00000000     0        0 10550 1 ffff8800a729b780 100 0 0 10 0
+  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  104: 00000000:0044 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 15012 2 ffff88003d9e9800 0
+  105: 00000000:0000 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 15013 2 ffff88003d9e9800 0
+  106: 0F02000A:D6E5 0A00600A:0035 07 00000000:00000000 00:00000000 00000000     0        0 15014 2 ffff88003d9e9800 0
+  250: 0F02000A:D6E4 0A00600A:0035 01 00000000:00000000 00:00000000 00000000     0        0 15203 2 ffff88003d9e9c00 0
+`
+	p := NewProcNet([]byte(testString))
+	expected := []Connection{
+		{
+			LocalAddress:  net.IP([]byte{0x7f, 0x0, 0x0, 0x01}),
+			LocalPort:     0x0019,
+			RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
+			RemotePort:    0x0,
+			Transport:     "tcp",
+			Inode:         10550,
+		},
+		// The unconnected udp socket is skipped
+		{
+			LocalAddress:  net.IP([]byte{0x0a, 0x00, 0x02, 0x0f}),
+			LocalPort:     0xd6e4,
+			RemoteAddress: net.IP([]byte{0x0a, 0x60, 0x00, 0x0a}),
+			RemotePort:    0x35,
+			Transport:     "udp",
+			Inode:         15203,
+		},
+	}
+	for i := 0; i < 2; i++ {
+		have := p.Next()
+		want := expected[i]
+		if !reflect.DeepEqual(*have, want) {
+			t.Errorf("Got\n%+v\nExpected\n%+v\n", *have, want)
+		}
+	}
+	if got := p.Next(); got != nil {
+		t.Errorf("p.Next() wasn't empty")
+	}
+}
+
+func TestSocketsProcNet(t *testing.T) {
+	// /proc/net/tcp followed by /proc/net/udp
+	testString := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0019 0100007F:A6C0 01 00000000:00000000 00:00000000 00000000     0        0 10550 1 ffff8800a729b780 100 0 0 10 0
+   1: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 10551 1 ffff8800a729b780 100 0 0 10 0
+   2: 00000000:0051 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 10552 1 ffff8800a729b780 100 0 0 10 0
+  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  104: 00000000:0044 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 15012 2 ffff88003d9e9800 0
+  105: 00000000:0000 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 15013 2 ffff88003d9e9800 0
+  106: 0F02000A:D6E5 0A00600A:0035 07 00000000:00000000 00:00000000 00000000     0        0 15014 2 ffff88003d9e9800 0
+  250: 0F02000A:D6E4 0A00600A:0035 01 00000000:00000000 00:00000000 00000000     0        0 15203 2 ffff88003d9e9c00 0
+`
+	p := NewSocketsProcNet([]byte(testString))
+	expected := []Connection{
+		{
+			LocalAddress:  net.IP([]byte{127, 0, 0, 1}),
+			LocalPort:     0x0019,
+			RemoteAddress: net.IP([]byte{127, 0, 0, 1}),
+			RemotePort:    0xa6c0,
+			Transport:     "tcp",
+			Inode:         10550,
+		},
+		{
+			LocalAddress:  net.IP([]byte{0, 0, 0, 0}),
+			LocalPort:     0x0050,
+			RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
+			RemotePort:    0x0,
+			Transport:     "tcp",
+			Inode:         10551,
+			Listening:     true,
+		},
+		// The closed tcp socket is skipped
+		{
+			LocalAddress:  net.IP([]byte{0, 0, 0, 0}),
+			LocalPort:     0x0044,
+			RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
+			RemotePort:    0x0,
+			Transport:     "udp",
+			Inode:         15012,
+			Listening:     true,
+		},
+		// The unbound and the disconnected udp sockets are skipped
+		{
+			LocalAddress:  net.IP([]byte{10, 0, 2, 15}),
+			LocalPort:     0xd6e4,
+			RemoteAddress: net.IP([]byte{10, 96, 0, 10}),
+			RemotePort:    0x0035,
+			Transport:     "udp",
+			Inode:         15203,
+		},
+	}
+	for i := 0; i < len(expected); i++ {
+		have := p.Next()
+		want := expected[i]
+		if !reflect.DeepEqual(*have, want) {
+			t.Errorf("Got\n%+v\nExpected\n%+v\n", *have, want)
+		}
+	}
+	if got := p.Next(); got != nil {
+		t.Errorf("p.Next() wasn't empty")
+	}
+}
+
 func TestProcNetFiltersDuplicates(t *testing.T) {
 	testString := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout Inode                                                     
    0: 00000000:A6C0 00000000:0000 01 00000000:00000000 00:00000000 00000000   105        0 5107 1 ffff8800a6aaf040 100 0 0 10 0                      
@@ -148,6 +260,7 @@ func TestProcNetFiltersDuplicates(t *testing.T) {
 		LocalPort:     0xa6c0,
 		RemoteAddress: net.IP([]byte{0, 0, 0, 0}),
 		RemotePort:    0x0,
+		Transport:     "tcp",
 		Inode:         5107,
 	}
 	have := p.Next()
@@ -1,6 +1,7 @@
-// Package procspy lists TCP connections, and optionally tries to find the
-// owning processes. Works on Linux (via /proc) and Darwin (via `lsof -i` and
-// `netstat`). You'll need root to use Processes().
+// Package procspy lists TCP connections (and, on Linux, connected UDP
+// sockets), and optionally tries to find the owning processes. Works on
+// Linux (via /proc) and Darwin (via `lsof -i` and `netstat`). You'll need
+// root to use Processes().
 package procspy
 
 import (
@@ -12,10 +13,14 @@ const (
 	tcpEstablished = 1
 	tcpFinWait1    = 4
 	tcpFinWait2    = 5
+	tcpClose       = 7
 	tcpCloseWait   = 8
+	tcpListen      = 10
 )
 
-// Connection is a (TCP) connection. The Proc struct might not be filled in.
+// Connection is a TCP connection, or a connected UDP socket, as told by
+// Transport. Listening sockets are connections without a remote end,
+// and are marked as such. The Proc struct might not be filled in.
 type Connection struct {
 	Transport     string
 	LocalAddress  net.IP
@@ -23,6 +28,7 @@ type Connection struct {
 	RemoteAddress net.IP
 	RemotePort    uint16
 	Inode         uint64
+	Listening     bool
 	Proc          Proc
 }
 
@@ -42,6 +48,9 @@ type ConnIter interface {
 type ConnectionScanner interface {
 	// Connections returns all established (TCP) connections.
 	Connections() (ConnIter, error)
+	// Sockets returns all established connections, along with the
+	// listening sockets, in a single scan.
+	Sockets() (ConnIter, error)
 	// Stops the scanning
 	Stop()
 }
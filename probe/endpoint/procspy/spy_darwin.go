@@ -75,5 +75,11 @@ func (s *darwinScanner) Connections() (ConnIter, error) {
 	return &f, nil
 }
 
+// Sockets returns all established (TCP) connections - listening
+// sockets are not implemented on darwin.
+func (s *darwinScanner) Sockets() (ConnIter, error) {
+	return s.Connections()
+}
+
 // Nothing to stop since there's nothing running in the background
 func (s *darwinScanner) Stop() {}
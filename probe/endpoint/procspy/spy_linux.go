@@ -60,7 +60,15 @@ type linuxScanner struct {
 }
 
 func (s *linuxScanner) Connections() (ConnIter, error) {
-	// buffer for contents of /proc/<pid>/net/tcp
+	return s.scan(NewProcNet)
+}
+
+func (s *linuxScanner) Sockets() (ConnIter, error) {
+	return s.scan(NewSocketsProcNet)
+}
+
+func (s *linuxScanner) scan(newProcNet func([]byte) *ProcNet) (ConnIter, error) {
+	// buffer for contents of /proc/<pid>/net/{tcp,udp}{,6}
 	buf := bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 
@@ -73,14 +81,16 @@ func (s *linuxScanner) Connections() (ConnIter, error) {
 	}
 
 	if buf.Len() == 0 {
-		readFile(procRoot+"/net/tcp", buf)
-		if ipv6IsSupported {
-			readFile(procRoot+"/net/tcp6", buf)
+		for _, protocol := range []string{"tcp", "udp"} {
+			readFile(procRoot+"/net/"+protocol, buf)
+			if ipv6IsSupported {
+				readFile(procRoot+"/net/"+protocol+"6", buf)
+			}
 		}
 	}
 
 	return &pnConnIter{
-		pn:    NewProcNet(buf.Bytes()),
+		pn:    newProcNet(buf.Bytes()),
 		buf:   buf,
 		procs: procs,
 	}, nil
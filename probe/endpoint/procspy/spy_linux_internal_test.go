@@ -14,7 +14,7 @@ import (
 func TestLinuxConnections(t *testing.T) {
 	fs_hook.Mock(mockFS)
 	defer fs_hook.Restore()
-	scanner := NewConnectionScanner(process.NewWalker("/proc", false), true)
+	scanner := NewConnectionScanner(process.NewWalker("/proc", false, false), true)
 	defer scanner.Stop()
 
 	// let the background scanner finish its first pass
@@ -30,6 +30,7 @@ func TestLinuxConnections(t *testing.T) {
 		LocalPort:     42688,
 		RemoteAddress: net.ParseIP("0.0.0.0").To4(),
 		RemotePort:    0,
+		Transport:     "tcp",
 		Inode:         5107,
 		Proc: Proc{
 			PID:  1,
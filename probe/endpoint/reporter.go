@@ -14,6 +14,7 @@ const (
 	ReverseDNSNames = report.ReverseDNSNames
 	SnoopedDNSNames = report.SnoopedDNSNames
 	CopyOf          = report.CopyOf
+	Transport       = report.Transport
 )
 
 // ReporterConfig are the config options for the endpoint reporter.
@@ -24,6 +25,7 @@ type ReporterConfig struct {
 	UseConntrack bool
 	WalkProc     bool
 	UseEbpfConn  bool
+	EbpfListen   bool
 	ProcRoot     string
 	BufferSize   int
 	ProcessCache *process.CachingWalker
@@ -65,6 +67,7 @@ func NewReporter(conf ReporterConfig) *Reporter {
 			UseConntrack: conf.UseConntrack,
 			WalkProc:     conf.WalkProc,
 			UseEbpfConn:  conf.UseEbpfConn,
+			EbpfListen:   conf.EbpfListen,
 			ProcRoot:     conf.ProcRoot,
 			BufferSize:   conf.BufferSize,
 			ProcessCache: conf.ProcessCache,
@@ -2,11 +2,13 @@ package endpoint_test
 
 import (
 	"net"
+	"reflect"
 	"strconv"
 	"testing"
 
 	"github.com/weaveworks/scope/probe/endpoint"
 	"github.com/weaveworks/scope/probe/endpoint/procspy"
+	"github.com/weaveworks/scope/probe/process"
 	"github.com/weaveworks/scope/report"
 )
 
@@ -126,3 +128,50 @@ func TestSpyWithProcesses(t *testing.T) {
 		}
 	}
 }
+
+func TestSpyListening(t *testing.T) {
+	const (
+		nodeID   = "nikon"
+		nodeName = "fishermans-friend"
+	)
+
+	scanner := procspy.FixedScanner(append(fixConnectionsWithProcesses, procspy.Connection{
+		Transport:     "tcp",
+		LocalAddress:  net.ParseIP("0.0.0.0"),
+		LocalPort:     fixLocalPort,
+		RemoteAddress: net.ParseIP("0.0.0.0"),
+		Proc: procspy.Proc{
+			PID:  fixProcessPID,
+			Name: fixProcessName,
+		},
+	}))
+	reporter := endpoint.NewReporter(endpoint.ReporterConfig{
+		HostID:     nodeID,
+		HostName:   nodeName,
+		SpyProcs:   true,
+		WalkProc:   true,
+		BufferSize: bufferSize,
+		Scanner:    scanner,
+	})
+	r, _ := reporter.Report()
+
+	processNodeID := report.MakeProcessNodeID(nodeID, strconv.FormatUint(uint64(fixProcessPID), 10))
+	node, ok := r.Process.Nodes[processNodeID]
+	if !ok {
+		t.Fatalf("Expected process node %q", processNodeID)
+	}
+	rows := node.ExtractMulticolumnTable(process.TableTemplates[process.ListeningSocketsTablePrefix])
+	want := []report.Row{
+		{
+			ID: "0.0.0.0:80/tcp",
+			Entries: map[string]string{
+				process.ListeningSocketProtocol: "tcp",
+				process.ListeningSocketAddress:  "0.0.0.0",
+				process.ListeningSocketPort:     "80",
+			},
+		},
+	}
+	if !reflect.DeepEqual(want, rows) {
+		t.Errorf("want %v, have %v", want, rows)
+	}
+}
@@ -0,0 +1,145 @@
+package host
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/weaveworks/scope/report"
+)
+
+const (
+	kb = 1024
+	gb = kb * kb * kb
+)
+
+// Filesystem is the usage of a mounted filesystem, in bytes.
+type Filesystem struct {
+	MountPoint, Device, Type string
+	Size, Used               uint64
+}
+
+// DiskStats are the bytes transferred by a block device since boot.
+type DiskStats struct {
+	Name                  string
+	ReadBytes, WriteBytes uint64
+}
+
+// InterfaceStats are the traffic counters of a network interface since
+// boot.
+type InterfaceStats struct {
+	Name               string
+	RxBytes, TxBytes   uint64
+	RxErrors, TxErrors uint64
+}
+
+// addDeviceMetrics adds the totals of the filesystems, disks and network
+// interfaces of the host to metrics, and their breakdown per device as
+// tables of node. Rates are computed since the previous call, and are
+// missing on the first one.
+func (r *Reporter) addDeviceMetrics(node report.Node, metrics report.Metrics, now time.Time) report.Node {
+	if filesystems, err := GetFilesystems(); err == nil && len(filesystems) > 0 {
+		var size, used uint64
+		rows := make([]report.Row, 0, len(filesystems))
+		for _, f := range filesystems {
+			size += f.Size
+			used += f.Used
+			rows = append(rows, filesystemRow(f))
+		}
+		metrics[FilesystemUsage] = report.MakeSingletonMetric(now, float64(used)).WithMax(float64(size))
+		node = node.AddPrefixMulticolumnTable(FilesystemsTablePrefix, rows)
+	}
+
+	disks, _ := GetDiskStats()
+	interfaces, _ := GetInterfaceStats()
+	interval := now.Sub(r.previousTime).Seconds()
+	if r.previousDisks != nil && interval > 0 {
+		var readRate, writeRate float64
+		rows := []report.Row{}
+		for _, d := range disks {
+			prev, ok := r.previousDisks[d.Name]
+			if !ok || d.ReadBytes < prev.ReadBytes || d.WriteBytes < prev.WriteBytes {
+				continue
+			}
+			read := float64(d.ReadBytes-prev.ReadBytes) / interval
+			write := float64(d.WriteBytes-prev.WriteBytes) / interval
+			readRate += read
+			writeRate += write
+			rows = append(rows, report.Row{
+				ID: d.Name,
+				Entries: map[string]string{
+					DiskName:  d.Name,
+					DiskRead:  fmt.Sprintf("%.1f", read/kb),
+					DiskWrite: fmt.Sprintf("%.1f", write/kb),
+				},
+			})
+		}
+		if len(rows) > 0 {
+			metrics[DiskReadRate] = report.MakeSingletonMetric(now, readRate)
+			metrics[DiskWriteRate] = report.MakeSingletonMetric(now, writeRate)
+			node = node.AddPrefixMulticolumnTable(DisksTablePrefix, rows)
+		}
+	}
+	if r.previousInterfaces != nil && interval > 0 {
+		var rxRate, txRate, errorsRate float64
+		rows := []report.Row{}
+		for _, i := range interfaces {
+			prev, ok := r.previousInterfaces[i.Name]
+			if !ok || i.RxBytes < prev.RxBytes || i.TxBytes < prev.TxBytes ||
+				i.RxErrors < prev.RxErrors || i.TxErrors < prev.TxErrors {
+				continue
+			}
+			rx := float64(i.RxBytes-prev.RxBytes) / interval
+			tx := float64(i.TxBytes-prev.TxBytes) / interval
+			rxErrors := float64(i.RxErrors-prev.RxErrors) / interval
+			txErrors := float64(i.TxErrors-prev.TxErrors) / interval
+			rxRate += rx
+			txRate += tx
+			errorsRate += rxErrors + txErrors
+			rows = append(rows, report.Row{
+				ID: i.Name,
+				Entries: map[string]string{
+					InterfaceName:     i.Name,
+					InterfaceRx:       fmt.Sprintf("%.1f", rx/kb),
+					InterfaceTx:       fmt.Sprintf("%.1f", tx/kb),
+					InterfaceRxErrors: fmt.Sprintf("%.2f", rxErrors),
+					InterfaceTxErrors: fmt.Sprintf("%.2f", txErrors),
+				},
+			})
+		}
+		if len(rows) > 0 {
+			metrics[NetworkRxRate] = report.MakeSingletonMetric(now, rxRate)
+			metrics[NetworkTxRate] = report.MakeSingletonMetric(now, txRate)
+			metrics[NetworkErrorsRate] = report.MakeSingletonMetric(now, errorsRate)
+			node = node.AddPrefixMulticolumnTable(InterfacesTablePrefix, rows)
+		}
+	}
+
+	r.previousDisks = map[string]DiskStats{}
+	for _, d := range disks {
+		r.previousDisks[d.Name] = d
+	}
+	r.previousInterfaces = map[string]InterfaceStats{}
+	for _, i := range interfaces {
+		r.previousInterfaces[i.Name] = i
+	}
+	r.previousTime = now
+	return node
+}
+
+func filesystemRow(f Filesystem) report.Row {
+	var usedPercent float64
+	if f.Size > 0 {
+		usedPercent = float64(f.Used) * 100 / float64(f.Size)
+	}
+	return report.Row{
+		ID: f.MountPoint,
+		Entries: map[string]string{
+			FilesystemMountPoint:  f.MountPoint,
+			FilesystemDevice:      f.Device,
+			FilesystemType:        f.Type,
+			FilesystemSize:        fmt.Sprintf("%.1f", float64(f.Size)/gb),
+			FilesystemUsed:        fmt.Sprintf("%.1f", float64(f.Used)/gb),
+			FilesystemUsedPercent: fmt.Sprintf("%.0f", usedPercent),
+		},
+	}
+}
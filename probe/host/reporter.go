@@ -21,9 +21,43 @@ const (
 	KernelVersion = "kernel_version"
 	Uptime        = "uptime"
 	Load1         = "load1"
+	Load5         = "load5"
+	Load15        = "load15"
 	CPUUsage      = "host_cpu_usage_percent"
 	MemoryUsage   = "host_mem_usage_bytes"
+	SwapUsage     = "host_swap_usage_bytes"
+	OpenFiles     = "host_open_files"
 	ScopeVersion  = "host_scope_version"
+
+	FilesystemUsage   = "host_filesystem_usage_bytes"
+	DiskReadRate      = "host_disk_read_bytes_per_second"
+	DiskWriteRate     = "host_disk_write_bytes_per_second"
+	NetworkRxRate     = "host_network_rx_bytes_per_second"
+	NetworkTxRate     = "host_network_tx_bytes_per_second"
+	NetworkErrorsRate = "host_network_errors_per_second"
+)
+
+// Keys of the tables breaking down host metrics per device.
+const (
+	FilesystemsTablePrefix = "filesystems_table_"
+	FilesystemMountPoint   = "filesystem_mount_point"
+	FilesystemDevice       = "filesystem_device"
+	FilesystemType         = "filesystem_type"
+	FilesystemSize         = "filesystem_size"
+	FilesystemUsed         = "filesystem_used"
+	FilesystemUsedPercent  = "filesystem_used_percent"
+
+	DisksTablePrefix = "disks_table_"
+	DiskName         = "disk_name"
+	DiskRead         = "disk_read"
+	DiskWrite        = "disk_write"
+
+	InterfacesTablePrefix = "network_interfaces_table_"
+	InterfaceName         = "network_interface_name"
+	InterfaceRx           = "network_interface_rx"
+	InterfaceTx           = "network_interface_tx"
+	InterfaceRxErrors     = "network_interface_rx_errors"
+	InterfaceTxErrors     = "network_interface_tx_errors"
 )
 
 // Exposed for testing.
@@ -32,6 +66,13 @@ const (
 	ProcLoad    = "/proc/loadavg"
 	ProcStat    = "/proc/stat"
 	ProcMemInfo = "/proc/meminfo"
+	ProcFileNr  = "/proc/sys/fs/file-nr"
+	// The mounts of the host, rather than those of the probe's container
+	ProcMounts    = "/proc/1/mounts"
+	HostRoot      = "/proc/1/root"
+	ProcDiskStats = "/proc/diskstats"
+	ProcNetDev    = "/proc/net/dev"
+	SysClassNet   = "/sys/class/net"
 )
 
 // Exposed for testing.
@@ -46,9 +87,60 @@ var (
 	}
 
 	MetricTemplates = report.MetricTemplates{
-		CPUUsage:    {ID: CPUUsage, Label: "CPU", Format: report.PercentFormat, Priority: 1},
-		MemoryUsage: {ID: MemoryUsage, Label: "Memory", Format: report.FilesizeFormat, Priority: 2},
-		Load1:       {ID: Load1, Label: "Load (1m)", Format: report.DefaultFormat, Group: "load", Priority: 11},
+		CPUUsage:          {ID: CPUUsage, Label: "CPU", Format: report.PercentFormat, Priority: 1},
+		MemoryUsage:       {ID: MemoryUsage, Label: "Memory", Format: report.FilesizeFormat, Priority: 2},
+		SwapUsage:         {ID: SwapUsage, Label: "Swap", Format: report.FilesizeFormat, Priority: 3},
+		FilesystemUsage:   {ID: FilesystemUsage, Label: "Filesystems", Format: report.FilesizeFormat, Priority: 4},
+		DiskReadRate:      {ID: DiskReadRate, Label: "Disk read/s", Format: report.FilesizeFormat, Priority: 5},
+		DiskWriteRate:     {ID: DiskWriteRate, Label: "Disk write/s", Format: report.FilesizeFormat, Priority: 6},
+		NetworkRxRate:     {ID: NetworkRxRate, Label: "Network received/s", Format: report.FilesizeFormat, Priority: 7},
+		NetworkTxRate:     {ID: NetworkTxRate, Label: "Network sent/s", Format: report.FilesizeFormat, Priority: 8},
+		NetworkErrorsRate: {ID: NetworkErrorsRate, Label: "Network errors/s", Format: report.DefaultFormat, Priority: 9},
+		OpenFiles:         {ID: OpenFiles, Label: "Open files", Format: report.IntegerFormat, Priority: 10},
+		Load1:             {ID: Load1, Label: "Load (1m)", Format: report.DefaultFormat, Group: "load", Priority: 11},
+		Load5:             {ID: Load5, Label: "Load (5m)", Format: report.DefaultFormat, Group: "load", Priority: 12},
+		Load15:            {ID: Load15, Label: "Load (15m)", Format: report.DefaultFormat, Group: "load", Priority: 13},
+	}
+
+	TableTemplates = report.TableTemplates{
+		FilesystemsTablePrefix: {
+			ID:     FilesystemsTablePrefix,
+			Label:  "Filesystems",
+			Type:   report.MulticolumnTableType,
+			Prefix: FilesystemsTablePrefix,
+			Columns: []report.Column{
+				{ID: FilesystemMountPoint, Label: "Mount point"},
+				{ID: FilesystemDevice, Label: "Device"},
+				{ID: FilesystemType, Label: "Type"},
+				{ID: FilesystemSize, Label: "Size (GB)", DataType: report.Number},
+				{ID: FilesystemUsed, Label: "Used (GB)", DataType: report.Number},
+				{ID: FilesystemUsedPercent, Label: "Used (%)", DataType: report.Number},
+			},
+		},
+		DisksTablePrefix: {
+			ID:     DisksTablePrefix,
+			Label:  "Disks",
+			Type:   report.MulticolumnTableType,
+			Prefix: DisksTablePrefix,
+			Columns: []report.Column{
+				{ID: DiskName, Label: "Device"},
+				{ID: DiskRead, Label: "Read (KB/s)", DataType: report.Number},
+				{ID: DiskWrite, Label: "Write (KB/s)", DataType: report.Number},
+			},
+		},
+		InterfacesTablePrefix: {
+			ID:     InterfacesTablePrefix,
+			Label:  "Network interfaces",
+			Type:   report.MulticolumnTableType,
+			Prefix: InterfacesTablePrefix,
+			Columns: []report.Column{
+				{ID: InterfaceName, Label: "Interface"},
+				{ID: InterfaceRx, Label: "Received (KB/s)", DataType: report.Number},
+				{ID: InterfaceTx, Label: "Sent (KB/s)", DataType: report.Number},
+				{ID: InterfaceRxErrors, Label: "Receive errors/s", DataType: report.Number},
+				{ID: InterfaceTxErrors, Label: "Send errors/s", DataType: report.Number},
+			},
+		},
 	}
 )
 
@@ -63,6 +155,11 @@ type Reporter struct {
 	hostShellCmd    []string
 	handlerRegistry *controls.HandlerRegistry
 	pipeIDToTTY     map[string]uintptr
+
+	// Counters of the previous report, to compute rates
+	previousDisks      map[string]DiskStats
+	previousInterfaces map[string]InterfaceStats
+	previousTime       time.Time
 }
 
 // NewReporter returns a Reporter which produces a report containing host
@@ -116,15 +213,23 @@ func (r *Reporter) Report() (report.Report, error) {
 
 	rep.Host = rep.Host.WithMetadataTemplates(MetadataTemplates)
 	rep.Host = rep.Host.WithMetricTemplates(MetricTemplates)
+	rep.Host = rep.Host.WithTableTemplates(TableTemplates)
 
 	now := mtime.Now()
 	metrics := GetLoad(now)
+	if metrics == nil {
+		metrics = report.Metrics{}
+	}
 	cpuUsage, max := GetCPUUsagePercent()
 	metrics[CPUUsage] = report.MakeSingletonMetric(now, cpuUsage).WithMax(max)
 	memoryUsage, max := GetMemoryUsageBytes()
 	metrics[MemoryUsage] = report.MakeSingletonMetric(now, memoryUsage).WithMax(max)
+	swapUsage, max := GetSwapUsageBytes()
+	metrics[SwapUsage] = report.MakeSingletonMetric(now, swapUsage).WithMax(max)
+	openFiles, max := GetOpenFiles()
+	metrics[OpenFiles] = report.MakeSingletonMetric(now, openFiles).WithMax(max)
 
-	rep.Host.AddNode(
+	node := r.addDeviceMetrics(
 		report.MakeNodeWith(report.MakeHostNodeID(r.hostID), map[string]string{
 			report.ControlProbeID: r.probeID,
 			Timestamp:             mtime.Now().UTC().Format(time.RFC3339Nano),
@@ -137,9 +242,10 @@ func (r *Reporter) Report() (report.Report, error) {
 			WithSets(report.MakeSets().
 				Add(LocalNetworks, report.MakeStringSet(localCIDRs...)),
 			).
-			WithMetrics(metrics).
 			WithLatestActiveControls(ExecHost),
+		metrics, now,
 	)
+	rep.Host.AddNode(node.WithMetrics(metrics))
 
 	rep.Host.Controls.AddControl(report.Control{
 		ID:    ExecHost,
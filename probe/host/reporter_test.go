@@ -2,11 +2,13 @@ package host_test
 
 import (
 	"net"
+	"reflect"
 	"runtime"
 	"testing"
 	"time"
 
 	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/common/test"
 	"github.com/weaveworks/scope/probe/controls"
 	"github.com/weaveworks/scope/probe/host"
 	"github.com/weaveworks/scope/report"
@@ -24,6 +26,8 @@ func TestReporter(t *testing.T) {
 			host.Load1:       report.MakeSingletonMetric(timestamp, 1.0),
 			host.CPUUsage:    report.MakeSingletonMetric(timestamp, 30.0).WithMax(100.0),
 			host.MemoryUsage: report.MakeSingletonMetric(timestamp, 60.0).WithMax(100.0),
+			host.SwapUsage:   report.MakeSingletonMetric(timestamp, 20.0).WithMax(50.0),
+			host.OpenFiles:   report.MakeSingletonMetric(timestamp, 1000.0).WithMax(10000.0),
 		}
 		uptime      = "3600" // one hour
 		kernel      = "release version"
@@ -39,6 +43,11 @@ func TestReporter(t *testing.T) {
 		oldGetUptime                  = host.GetUptime
 		oldGetCPUUsagePercent         = host.GetCPUUsagePercent
 		oldGetMemoryUsageBytes        = host.GetMemoryUsageBytes
+		oldGetSwapUsageBytes          = host.GetSwapUsageBytes
+		oldGetOpenFiles               = host.GetOpenFiles
+		oldGetFilesystems             = host.GetFilesystems
+		oldGetDiskStats               = host.GetDiskStats
+		oldGetInterfaceStats          = host.GetInterfaceStats
 		oldGetLocalNetworks           = host.GetLocalNetworks
 	)
 	defer func() {
@@ -47,6 +56,11 @@ func TestReporter(t *testing.T) {
 		host.GetUptime = oldGetUptime
 		host.GetCPUUsagePercent = oldGetCPUUsagePercent
 		host.GetMemoryUsageBytes = oldGetMemoryUsageBytes
+		host.GetSwapUsageBytes = oldGetSwapUsageBytes
+		host.GetOpenFiles = oldGetOpenFiles
+		host.GetFilesystems = oldGetFilesystems
+		host.GetDiskStats = oldGetDiskStats
+		host.GetInterfaceStats = oldGetInterfaceStats
 		host.GetLocalNetworks = oldGetLocalNetworks
 	}()
 	host.GetKernelReleaseAndVersion = func() (string, string, error) { return release, version, nil }
@@ -54,6 +68,11 @@ func TestReporter(t *testing.T) {
 	host.GetUptime = func() (time.Duration, error) { return time.Hour, nil }
 	host.GetCPUUsagePercent = func() (float64, float64) { return 30.0, 100.0 }
 	host.GetMemoryUsageBytes = func() (float64, float64) { return 60.0, 100.0 }
+	host.GetSwapUsageBytes = func() (float64, float64) { return 20.0, 50.0 }
+	host.GetOpenFiles = func() (float64, float64) { return 1000.0, 10000.0 }
+	host.GetFilesystems = func() ([]host.Filesystem, error) { return nil, nil }
+	host.GetDiskStats = func() ([]host.DiskStats, error) { return nil, nil }
+	host.GetInterfaceStats = func() ([]host.InterfaceStats, error) { return nil, nil }
 	host.GetLocalNetworks = func() ([]*net.IPNet, error) { return []*net.IPNet{ipnet}, nil }
 
 	hr := controls.NewDefaultHandlerRegistry()
@@ -101,3 +120,106 @@ func TestReporter(t *testing.T) {
 		}
 	}
 }
+
+func TestDeviceMetrics(t *testing.T) {
+	var (
+		oldGetFilesystems    = host.GetFilesystems
+		oldGetDiskStats      = host.GetDiskStats
+		oldGetInterfaceStats = host.GetInterfaceStats
+		start                = time.Now()
+		disks                = []host.DiskStats{{Name: "sda", ReadBytes: 1024, WriteBytes: 2048}}
+		interfaces           = []host.InterfaceStats{{Name: "eth0", RxBytes: 4096, TxBytes: 1024, RxErrors: 1}}
+	)
+	defer func() {
+		host.GetFilesystems = oldGetFilesystems
+		host.GetDiskStats = oldGetDiskStats
+		host.GetInterfaceStats = oldGetInterfaceStats
+		mtime.NowReset()
+	}()
+	host.GetFilesystems = func() ([]host.Filesystem, error) {
+		return []host.Filesystem{
+			{MountPoint: "/", Device: "/dev/sda1", Type: "ext4", Size: 4 << 30, Used: 1 << 30},
+			{MountPoint: "/data", Device: "/dev/sdb1", Type: "xfs", Size: 6 << 30, Used: 3 << 30},
+		}, nil
+	}
+	host.GetDiskStats = func() ([]host.DiskStats, error) { return disks, nil }
+	host.GetInterfaceStats = func() ([]host.InterfaceStats, error) { return interfaces, nil }
+
+	r := host.NewReporter("hostid", "hostname", "probe-id", "", nil, controls.NewDefaultHandlerRegistry())
+	mtime.NowForce(start)
+	if _, err := r.Report(); err != nil {
+		t.Fatal(err)
+	}
+
+	disks = []host.DiskStats{{Name: "sda", ReadBytes: 1024 + 20*1024, WriteBytes: 2048 + 10*1024}}
+	interfaces = []host.InterfaceStats{{Name: "eth0", RxBytes: 4096 + 40*1024, TxBytes: 1024 + 10*1024, RxErrors: 3, TxErrors: 2}}
+	mtime.NowForce(start.Add(10 * time.Second))
+	rpt, err := r.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := rpt.Host.Nodes[report.MakeHostNodeID("hostid")]
+
+	for key, want := range map[string]float64{
+		host.FilesystemUsage:   4 << 30,
+		host.DiskReadRate:      2 * 1024,
+		host.DiskWriteRate:     1024,
+		host.NetworkRxRate:     4 * 1024,
+		host.NetworkTxRate:     1024,
+		host.NetworkErrorsRate: 0.4,
+	} {
+		metric, ok := node.Metrics[key]
+		if !ok {
+			t.Errorf("Expected %s metric, but not found", key)
+			continue
+		}
+		if sample, _ := metric.LastSample(); sample.Value != want {
+			t.Errorf("Expected %s metric sample %f, got %f", key, want, sample.Value)
+		}
+	}
+	if max := node.Metrics[host.FilesystemUsage].Max; max != 10<<30 {
+		t.Errorf("Expected filesystem usage max %d, got %f", 10<<30, max)
+	}
+
+	for prefix, want := range map[string][]report.Row{
+		host.FilesystemsTablePrefix: {
+			{ID: "/", Entries: map[string]string{
+				host.FilesystemMountPoint:  "/",
+				host.FilesystemDevice:      "/dev/sda1",
+				host.FilesystemType:        "ext4",
+				host.FilesystemSize:        "4.0",
+				host.FilesystemUsed:        "1.0",
+				host.FilesystemUsedPercent: "25",
+			}},
+			{ID: "/data", Entries: map[string]string{
+				host.FilesystemMountPoint:  "/data",
+				host.FilesystemDevice:      "/dev/sdb1",
+				host.FilesystemType:        "xfs",
+				host.FilesystemSize:        "6.0",
+				host.FilesystemUsed:        "3.0",
+				host.FilesystemUsedPercent: "50",
+			}},
+		},
+		host.DisksTablePrefix: {
+			{ID: "sda", Entries: map[string]string{
+				host.DiskName:  "sda",
+				host.DiskRead:  "2.0",
+				host.DiskWrite: "1.0",
+			}},
+		},
+		host.InterfacesTablePrefix: {
+			{ID: "eth0", Entries: map[string]string{
+				host.InterfaceName:     "eth0",
+				host.InterfaceRx:       "4.0",
+				host.InterfaceTx:       "1.0",
+				host.InterfaceRxErrors: "0.20",
+				host.InterfaceTxErrors: "0.20",
+			}},
+		},
+	} {
+		have := node.ExtractMulticolumnTable(host.TableTemplates[prefix])
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%s: %s", prefix, test.Diff(want, have))
+		}
+	}
+}
@@ -41,13 +41,15 @@ var GetLoad = func(now time.Time) report.Metrics {
 		return nil
 	}
 
-	one, err := strconv.ParseFloat(matches[0][1], 64)
-	if err != nil {
-		return nil
-	}
-	return report.Metrics{
-		Load1: report.MakeSingletonMetric(now, one),
+	metrics := report.Metrics{}
+	for i, key := range []string{Load1, Load5, Load15} {
+		load, err := strconv.ParseFloat(matches[0][i+1], 64)
+		if err != nil {
+			return nil
+		}
+		metrics[key] = report.MakeSingletonMetric(now, load)
 	}
+	return metrics
 }
 
 // GetUptime returns the uptime of the host.
@@ -84,3 +86,32 @@ var GetCPUUsagePercent = func() (float64, float64) {
 var GetMemoryUsageBytes = func() (float64, float64) {
 	return 0.0, 0.0
 }
+
+// GetSwapUsageBytes returns the bytes swap usage and max
+var GetSwapUsageBytes = func() (float64, float64) {
+	return 0.0, 0.0
+}
+
+// GetOpenFiles returns the number of open file descriptors of the host
+// and max
+var GetOpenFiles = func() (float64, float64) {
+	return 0.0, 0.0
+}
+
+// GetFilesystems returns the usage of the filesystems mounted on the
+// host.
+var GetFilesystems = func() ([]Filesystem, error) {
+	return nil, nil
+}
+
+// GetDiskStats returns the bytes transferred by the block devices of
+// the host.
+var GetDiskStats = func() ([]DiskStats, error) {
+	return nil, nil
+}
+
+// GetInterfaceStats returns the traffic counters of the network
+// interfaces of the host.
+var GetInterfaceStats = func() ([]InterfaceStats, error) {
+	return nil, nil
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -15,8 +17,6 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-const kb = 1024
-
 // Uname is swappable for mocking in tests.
 var Uname = unix.Uname
 
@@ -41,13 +41,15 @@ var GetLoad = func(now time.Time) report.Metrics {
 	if len(toks) < 3 {
 		return nil
 	}
-	one, err := strconv.ParseFloat(toks[0], 64)
-	if err != nil {
-		return nil
-	}
-	return report.Metrics{
-		Load1: report.MakeSingletonMetric(now, one),
+	metrics := report.Metrics{}
+	for i, key := range []string{Load1, Load5, Load15} {
+		load, err := strconv.ParseFloat(toks[i], 64)
+		if err != nil {
+			return nil
+		}
+		metrics[key] = report.MakeSingletonMetric(now, load)
 	}
+	return metrics
 }
 
 // GetUptime returns the uptime of the host.
@@ -108,3 +110,127 @@ var GetMemoryUsageBytes = func() (float64, float64) {
 	used := meminfo.MemTotal - meminfo.MemFree - meminfo.Buffers - meminfo.Cached
 	return float64(used * kb), float64(meminfo.MemTotal * kb)
 }
+
+// GetSwapUsageBytes returns the bytes swap usage and max
+var GetSwapUsageBytes = func() (float64, float64) {
+	meminfo, err := linuxproc.ReadMemInfo(ProcMemInfo)
+	if err != nil {
+		return 0.0, 0.0
+	}
+
+	used := meminfo.SwapTotal - meminfo.SwapFree
+	return float64(used * kb), float64(meminfo.SwapTotal * kb)
+}
+
+// GetOpenFiles returns the number of open file descriptors of the host
+// and max
+var GetOpenFiles = func() (float64, float64) {
+	buf, err := ioutil.ReadFile(ProcFileNr)
+	if err != nil {
+		return 0.0, 0.0
+	}
+	// allocated, allocated but unused, and max file handles
+	toks := strings.Fields(string(buf))
+	if len(toks) < 3 {
+		return 0.0, 0.0
+	}
+	var values [3]float64
+	for i := range values {
+		if values[i], err = strconv.ParseFloat(toks[i], 64); err != nil {
+			return 0.0, 0.0
+		}
+	}
+	return values[0] - values[1], values[2]
+}
+
+// GetFilesystems returns the usage of the filesystems on block devices
+// mounted on the host. Filesystems mounted more than once are reported
+// at their first mount point.
+var GetFilesystems = func() ([]Filesystem, error) {
+	mounts, err := linuxproc.ReadMounts(ProcMounts)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		result  []Filesystem
+		devices = map[string]struct{}{}
+	)
+	for _, mount := range mounts.Mounts {
+		if !strings.HasPrefix(mount.Device, "/dev/") {
+			continue
+		}
+		if _, ok := devices[mount.Device]; ok {
+			continue
+		}
+		disk, err := linuxproc.ReadDisk(filepath.Join(HostRoot, mount.MountPoint))
+		if err != nil {
+			continue
+		}
+		devices[mount.Device] = struct{}{}
+		result = append(result, Filesystem{
+			MountPoint: mount.MountPoint,
+			Device:     mount.Device,
+			Type:       mount.FSType,
+			Size:       disk.All,
+			Used:       disk.Used,
+		})
+	}
+	return result, nil
+}
+
+// GetDiskStats returns the bytes transferred by the block devices of
+// the host. Partitions are left out, as their disk accounts for them.
+var GetDiskStats = func() ([]DiskStats, error) {
+	stats, err := linuxproc.ReadDiskStats(ProcDiskStats)
+	if err != nil {
+		return nil, err
+	}
+	var result []DiskStats
+	for _, stat := range stats {
+		if stat.ReadIOs == 0 && stat.WriteIOs == 0 {
+			continue
+		}
+		// Only whole disks are in /sys/block; '/' in names is replaced by '!'
+		if _, err := os.Stat(filepath.Join("/sys/block", strings.Replace(stat.Name, "/", "!", -1))); err != nil {
+			continue
+		}
+		result = append(result, DiskStats{
+			Name:       stat.Name,
+			ReadBytes:  uint64(stat.GetReadBytes()),
+			WriteBytes: uint64(stat.GetWriteBytes()),
+		})
+	}
+	return result, nil
+}
+
+// GetInterfaceStats returns the traffic counters of the physical
+// network interfaces of the host. Virtual ones are left out: loopback
+// traffic never leaves the host, and that of veths, bridges and tunnels
+// goes through physical interfaces too, so would be counted twice.
+var GetInterfaceStats = func() ([]InterfaceStats, error) {
+	stats, err := linuxproc.ReadNetworkStat(ProcNetDev)
+	if err != nil {
+		return nil, err
+	}
+	var result []InterfaceStats
+	for _, stat := range stats {
+		if stat.Iface == "" || !isPhysicalInterface(stat.Iface) {
+			continue
+		}
+		result = append(result, InterfaceStats{
+			Name:     stat.Iface,
+			RxBytes:  stat.RxBytes,
+			TxBytes:  stat.TxBytes,
+			RxErrors: stat.RxErrs,
+			TxErrors: stat.TxErrs,
+		})
+	}
+	return result, nil
+}
+
+// isPhysicalInterface tells whether a network interface is backed by
+// a device, which virtual interfaces are not.
+func isPhysicalInterface(name string) bool {
+	_, err := os.Stat(filepath.Join(SysClassNet, name, "device"))
+	return err == nil
+}
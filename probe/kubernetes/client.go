@@ -1,32 +1,48 @@
 package kubernetes
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/weaveworks/common/backoff"
+	"github.com/weaveworks/common/mtime"
 
 	log "github.com/Sirupsen/logrus"
+	apiappsv1 "k8s.io/api/apps/v1"
 	apiappsv1beta1 "k8s.io/api/apps/v1beta1"
 	apibatchv1 "k8s.io/api/batch/v1"
 	apibatchv1beta1 "k8s.io/api/batch/v1beta1"
 	apibatchv2alpha1 "k8s.io/api/batch/v2alpha1"
 	apiv1 "k8s.io/api/core/v1"
 	apiextensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apistoragev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
+// restartedAtAnnotation is set on the pod template of a controller to
+// make it roll out new pods, as `kubectl rollout restart` does.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// revisionAnnotation holds the rollout revision of a deployment and of
+// each of its replicasets.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
 // Client keeps track of running kubernetes pods and services
 type Client interface {
 	Stop()
@@ -36,31 +52,51 @@ type Client interface {
 	WalkDaemonSets(f func(DaemonSet) error) error
 	WalkStatefulSets(f func(StatefulSet) error) error
 	WalkCronJobs(f func(CronJob) error) error
+	WalkJobs(f func(Job) error) error
+	WalkIngresses(f func(Ingress) error) error
+	WalkPersistentVolumeClaims(f func(PersistentVolumeClaim) error) error
+	WalkPersistentVolumes(f func(PersistentVolume) error) error
+	WalkStorageClasses(f func(StorageClass) error) error
 	WalkNamespaces(f func(NamespaceResource) error) error
+	WalkNodes(f func(Node) error) error
 
 	WatchPods(f func(Event, Pod))
+	WatchEvents(f func(Event, ObjectEvent))
 
 	GetLogs(namespaceID, podID string, containerNames []string) (io.ReadCloser, error)
 	DeletePod(namespaceID, podID string) error
 	ScaleUp(resource, namespaceID, id string) error
 	ScaleDown(resource, namespaceID, id string) error
+	RestartRollout(resource, namespaceID, id string) error
+	Rollback(namespaceID, id string) error
+	Exec(namespaceID, podID, containerName string, command []string, stdin io.Reader, stdout io.Writer, sizes remotecommand.TerminalSizeQueue) error
+	Describe(resource, namespaceID, id string) ([]byte, error)
 }
 
 type client struct {
-	quit             chan struct{}
-	client           *kubernetes.Clientset
-	podStore         cache.Store
-	serviceStore     cache.Store
-	deploymentStore  cache.Store
-	daemonSetStore   cache.Store
-	statefulSetStore cache.Store
-	jobStore         cache.Store
-	cronJobStore     cache.Store
-	nodeStore        cache.Store
-	namespaceStore   cache.Store
+	quit              chan struct{}
+	config            *rest.Config
+	client            *kubernetes.Clientset
+	podStore          cache.Store
+	serviceStore      cache.Store
+	deploymentStore   cache.Store
+	daemonSetStore    cache.Store
+	statefulSetStore  cache.Store
+	jobStore          cache.Store
+	cronJobStore      cache.Store
+	ingressStore      cache.Store
+	pvcStore          cache.Store
+	pvStore           cache.Store
+	storageClassStore cache.Store
+	nodeStore         cache.Store
+	namespaceStore    cache.Store
+	eventStore        cache.Store
 
 	podWatchesMutex sync.Mutex
 	podWatches      []func(Event, Pod)
+
+	eventWatchesMutex sync.Mutex
+	eventWatches      []func(Event, ObjectEvent)
 }
 
 // ClientConfig establishes the configuration for the kubernetes client
@@ -128,6 +164,7 @@ func NewClient(config ClientConfig) (Client, error) {
 
 	result := &client{
 		quit:   make(chan struct{}),
+		config: restConfig,
 		client: c,
 	}
 
@@ -142,6 +179,10 @@ func NewClient(config ClientConfig) (Client, error) {
 	result.jobStore = result.setupStore("jobs")
 	result.statefulSetStore = result.setupStore("statefulsets")
 	result.cronJobStore = result.setupStore("cronjobs")
+	result.ingressStore = result.setupStore("ingresses")
+	result.pvcStore = result.setupStore("persistentvolumeclaims")
+	result.pvStore = result.setupStore("persistentvolumes")
+	result.storageClassStore = result.setupStore("storageclasses")
 
 	return result, nil
 }
@@ -180,10 +221,20 @@ func (c *client) clientAndType(resource string) (rest.Interface, interface{}, er
 		return c.client.CoreV1().RESTClient(), &apiv1.Node{}, nil
 	case "namespaces":
 		return c.client.CoreV1().RESTClient(), &apiv1.Namespace{}, nil
+	case "events":
+		return c.client.CoreV1().RESTClient(), &apiv1.Event{}, nil
+	case "persistentvolumeclaims":
+		return c.client.CoreV1().RESTClient(), &apiv1.PersistentVolumeClaim{}, nil
+	case "persistentvolumes":
+		return c.client.CoreV1().RESTClient(), &apiv1.PersistentVolume{}, nil
+	case "storageclasses":
+		return c.client.StorageV1().RESTClient(), &apistoragev1.StorageClass{}, nil
 	case "deployments":
 		return c.client.ExtensionsV1beta1().RESTClient(), &apiextensionsv1beta1.Deployment{}, nil
 	case "daemonsets":
 		return c.client.ExtensionsV1beta1().RESTClient(), &apiextensionsv1beta1.DaemonSet{}, nil
+	case "ingresses":
+		return c.client.ExtensionsV1beta1().RESTClient(), &apiextensionsv1beta1.Ingress{}, nil
 	case "jobs":
 		return c.client.BatchV1().RESTClient(), &apibatchv1.Job{}, nil
 	case "statefulsets":
@@ -252,6 +303,27 @@ func (c *client) triggerPodWatches(e Event, pod interface{}) {
 	}
 }
 
+// WatchEvents calls f for each change of the events of the cluster.
+// Events are only listed and watched once something watches them, as
+// there are lots of them.
+func (c *client) WatchEvents(f func(Event, ObjectEvent)) {
+	c.eventWatchesMutex.Lock()
+	defer c.eventWatchesMutex.Unlock()
+	c.eventWatches = append(c.eventWatches, f)
+	if c.eventStore == nil {
+		c.eventStore = NewEventStore(c.triggerEventWatches, cache.MetaNamespaceKeyFunc)
+		c.runReflectorUntil("events", c.eventStore)
+	}
+}
+
+func (c *client) triggerEventWatches(e Event, event interface{}) {
+	c.eventWatchesMutex.Lock()
+	defer c.eventWatchesMutex.Unlock()
+	for _, watch := range c.eventWatches {
+		watch(e, NewObjectEvent(event.(*apiv1.Event)))
+	}
+}
+
 func (c *client) WalkPods(f func(Pod) error) error {
 	for _, m := range c.podStore.List() {
 		pod := m.(*apiv1.Pod)
@@ -332,6 +404,76 @@ func (c *client) WalkCronJobs(f func(CronJob) error) error {
 	return nil
 }
 
+// WalkJobs calls f for each job
+func (c *client) WalkJobs(f func(Job) error) error {
+	if c.jobStore == nil {
+		return nil
+	}
+	for _, m := range c.jobStore.List() {
+		j := m.(*apibatchv1.Job)
+		if err := f(NewJob(j)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkIngresses calls f for each ingress
+func (c *client) WalkIngresses(f func(Ingress) error) error {
+	if c.ingressStore == nil {
+		return nil
+	}
+	for _, m := range c.ingressStore.List() {
+		i := m.(*apiextensionsv1beta1.Ingress)
+		if err := f(NewIngress(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkPersistentVolumeClaims calls f for each persistent volume claim
+func (c *client) WalkPersistentVolumeClaims(f func(PersistentVolumeClaim) error) error {
+	if c.pvcStore == nil {
+		return nil
+	}
+	for _, m := range c.pvcStore.List() {
+		p := m.(*apiv1.PersistentVolumeClaim)
+		if err := f(NewPersistentVolumeClaim(p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkPersistentVolumes calls f for each persistent volume
+func (c *client) WalkPersistentVolumes(f func(PersistentVolume) error) error {
+	if c.pvStore == nil {
+		return nil
+	}
+	for _, m := range c.pvStore.List() {
+		p := m.(*apiv1.PersistentVolume)
+		if err := f(NewPersistentVolume(p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkStorageClasses calls f for each storage class
+func (c *client) WalkStorageClasses(f func(StorageClass) error) error {
+	if c.storageClassStore == nil {
+		return nil
+	}
+	for _, m := range c.storageClassStore.List() {
+		s := m.(*apistoragev1.StorageClass)
+		if err := f(NewStorageClass(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *client) WalkNamespaces(f func(NamespaceResource) error) error {
 	for _, m := range c.namespaceStore.List() {
 		namespace := m.(*apiv1.Namespace)
@@ -342,6 +484,17 @@ func (c *client) WalkNamespaces(f func(NamespaceResource) error) error {
 	return nil
 }
 
+// WalkNodes calls f for each node
+func (c *client) WalkNodes(f func(Node) error) error {
+	for _, m := range c.nodeStore.List() {
+		n := m.(*apiv1.Node)
+		if err := f(NewNode(n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *client) GetLogs(namespaceID, podID string, containerNames []string) (io.ReadCloser, error) {
 	readClosersWithLabel := map[io.ReadCloser]string{}
 	for _, container := range containerNames {
@@ -393,6 +546,153 @@ func (c *client) modifyScale(resource, namespace, id string, f func(*apiextensio
 	return err
 }
 
+// RestartRollout makes a deployment, daemonset or statefulset replace
+// all its pods.
+func (c *client) RestartRollout(resource, namespaceID, id string) error {
+	restClient, _, err := c.clientAndType(resource)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: mtime.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return restClient.Patch(types.StrategicMergePatchType).
+		Namespace(namespaceID).
+		Resource(resource).
+		Name(id).
+		Body(patch).
+		Do().
+		Error()
+}
+
+// Rollback rolls a deployment back to its previous revision. Under
+// apps/v1, which has no rollback subresource, this is done as `kubectl
+// rollout undo` does: the pod template of the replicaset of that revision
+// is patched back into the deployment. Older servers roll back through
+// extensions/v1beta1, as the other deployment controls go.
+func (c *client) Rollback(namespaceID, id string) error {
+	ok, err := c.isResourceSupported(c.client.AppsV1().RESTClient().APIVersion(), "deployments")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// kubernetes < 1.9
+		return c.client.ExtensionsV1beta1().Deployments(namespaceID).Rollback(&apiextensionsv1beta1.DeploymentRollback{
+			Name: id,
+		})
+	}
+	return c.rollbackAppsV1(namespaceID, id)
+}
+
+func (c *client) rollbackAppsV1(namespaceID, id string) error {
+	deployment, err := c.client.AppsV1().Deployments(namespaceID).Get(id, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.Paused {
+		return fmt.Errorf("cannot roll back paused deployment %s", id)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	replicaSets, err := c.client.AppsV1().ReplicaSets(namespaceID).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	current := revisionOf(deployment.ObjectMeta)
+	var (
+		previous *apiappsv1.ReplicaSet
+		revision int64
+	)
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if !metav1.IsControlledBy(rs, deployment) {
+			continue
+		}
+		if r := revisionOf(rs.ObjectMeta); r < current && r > revision {
+			previous, revision = rs, r
+		}
+	}
+	if previous == nil {
+		return fmt.Errorf("no previous revision of deployment %s to roll back to", id)
+	}
+
+	template := previous.Spec.Template.DeepCopy()
+	delete(template.Labels, apiappsv1.DefaultDeploymentUniqueLabelKey)
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "replace", "path": "/spec/template", "value": template},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.AppsV1().Deployments(namespaceID).Patch(id, types.JSONPatchType, patch)
+	return err
+}
+
+// revisionOf returns the rollout revision the deployment controller
+// annotated an object with, or 0 if it has none.
+func revisionOf(meta metav1.ObjectMeta) int64 {
+	revision, _ := strconv.ParseInt(meta.Annotations[revisionAnnotation], 10, 64)
+	return revision
+}
+
+// Exec runs command in a container of a pod, through the API server, with
+// a TTY attached to stdin and stdout. It blocks until command exits.
+func (c *client) Exec(namespaceID, podID, containerName string, command []string, stdin io.Reader, stdout io.Writer, sizes remotecommand.TerminalSizeQueue) error {
+	req := c.client.CoreV1().RESTClient().Post().
+		Namespace(namespaceID).
+		Resource("pods").
+		Name(podID).
+		SubResource("exec").
+		VersionedParams(&apiv1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdin:     true,
+			Stdout:    true,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(c.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.Stream(remotecommand.StreamOptions{
+		Stdin:             stdin,
+		Stdout:            stdout,
+		Tty:               true,
+		TerminalSizeQueue: sizes,
+	})
+}
+
+// Describe returns the YAML of an object, as the API server has it.
+func (c *client) Describe(resource, namespaceID, id string) ([]byte, error) {
+	restClient, _, err := c.clientAndType(resource)
+	if err != nil {
+		return nil, err
+	}
+	body, err := restClient.Get().
+		NamespaceIfScoped(namespaceID, namespaceID != "").
+		Resource(resource).
+		Name(id).
+		Do().
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(body)
+}
+
 func (c *client) Stop() {
 	close(c.quit)
 }
@@ -1,22 +1,34 @@
 package kubernetes
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 
+	log "github.com/Sirupsen/logrus"
 	"github.com/weaveworks/scope/common/xfer"
 	"github.com/weaveworks/scope/probe/controls"
 	"github.com/weaveworks/scope/report"
+
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 // Control IDs used by the kubernetes integration.
 const (
-	GetLogs   = report.KubernetesGetLogs
-	DeletePod = report.KubernetesDeletePod
-	ScaleUp   = report.KubernetesScaleUp
-	ScaleDown = report.KubernetesScaleDown
+	GetLogs        = report.KubernetesGetLogs
+	DeletePod      = report.KubernetesDeletePod
+	ScaleUp        = report.KubernetesScaleUp
+	ScaleDown      = report.KubernetesScaleDown
+	RestartRollout = report.KubernetesRestartRollout
+	Rollback       = report.KubernetesRollback
+	ExecContainer  = report.KubernetesExecContainer
+	ResizeExecTTY  = report.KubernetesResizeExecTTY
+	Describe       = report.KubernetesDescribe
 )
 
+// execShellCommand runs the login shell of root, if there is one, or sh.
+var execShellCommand = []string{"/bin/sh", "-c", "TERM=xterm exec $( (type getent > /dev/null 2>&1  && getent passwd root | cut -d: -f7 2>/dev/null) || echo /bin/sh)"}
+
 // GetLogs is the control to get the logs for a kubernetes pod
 func (r *Reporter) GetLogs(req xfer.Request, namespaceID, podID string, containerNames []string) xfer.Response {
 	readCloser, err := r.client.GetLogs(namespaceID, podID, containerNames)
@@ -95,6 +107,42 @@ func (r *Reporter) CaptureDeployment(f func(xfer.Request, string, string) xfer.R
 	}
 }
 
+// CaptureResource is exported for testing
+func (r *Reporter) CaptureResource(f func(xfer.Request, string, string, string) xfer.Response) func(xfer.Request) xfer.Response {
+	return func(req xfer.Request) xfer.Response {
+		var (
+			resource, kind, uid string
+			ok                  bool
+			object              Meta
+			find                = func(m Meta) error {
+				if m.UID() == uid {
+					object = m
+				}
+				return nil
+			}
+		)
+		if uid, ok = report.ParsePodNodeID(req.NodeID); ok {
+			resource, kind = "pods", "Pod"
+			r.client.WalkPods(func(p Pod) error { return find(p) })
+		} else if uid, ok = report.ParseDeploymentNodeID(req.NodeID); ok {
+			resource, kind = "deployments", "Deployment"
+			r.client.WalkDeployments(func(d Deployment) error { return find(d) })
+		} else if uid, ok = report.ParseDaemonSetNodeID(req.NodeID); ok {
+			resource, kind = "daemonsets", "DaemonSet"
+			r.client.WalkDaemonSets(func(d DaemonSet) error { return find(d) })
+		} else if uid, ok = report.ParseStatefulSetNodeID(req.NodeID); ok {
+			resource, kind = "statefulsets", "StatefulSet"
+			r.client.WalkStatefulSets(func(s StatefulSet) error { return find(s) })
+		} else {
+			return xfer.ResponseErrorf("Invalid ID: %s", req.NodeID)
+		}
+		if object == nil {
+			return xfer.ResponseErrorf("%s not found: %s", kind, uid)
+		}
+		return f(req, resource, object.Namespace(), object.Name())
+	}
+}
+
 // ScaleUp is the control to scale up a deployment
 func (r *Reporter) ScaleUp(req xfer.Request, namespace, id string) xfer.Response {
 	return xfer.ResponseError(r.client.ScaleUp(report.Deployment, namespace, id))
@@ -105,12 +153,133 @@ func (r *Reporter) ScaleDown(req xfer.Request, namespace, id string) xfer.Respon
 	return xfer.ResponseError(r.client.ScaleDown(report.Deployment, namespace, id))
 }
 
+func (r *Reporter) restartRollout(req xfer.Request, resource, namespace, id string) xfer.Response {
+	return xfer.ResponseError(r.client.RestartRollout(resource, namespace, id))
+}
+
+// rollback rolls a deployment back to its previous revision
+func (r *Reporter) rollback(req xfer.Request, namespace, id string) xfer.Response {
+	return xfer.ResponseError(r.client.Rollback(namespace, id))
+}
+
+// Describe is the control to get the YAML of a kubernetes object
+func (r *Reporter) Describe(req xfer.Request, resource, namespace, id string) xfer.Response {
+	description, err := r.client.Describe(resource, namespace, id)
+	if err != nil {
+		return xfer.ResponseError(err)
+	}
+
+	readWriter := struct {
+		io.Reader
+		io.Writer
+	}{
+		bytes.NewReader(description),
+		ioutil.Discard,
+	}
+	pipeID, _, err := controls.NewPipeFromEnds(nil, readWriter, r.pipes, req.AppID)
+	if err != nil {
+		return xfer.ResponseError(err)
+	}
+	return xfer.Response{
+		Pipe: pipeID,
+	}
+}
+
+// terminalSizes passes the sizes of the terminal of an exec pipe on to
+// the API server.
+type terminalSizes chan remotecommand.TerminalSize
+
+// Next implements remotecommand.TerminalSizeQueue
+func (t terminalSizes) Next() *remotecommand.TerminalSize {
+	size, ok := <-t
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+// ExecContainer is the control to run a shell in a container of a pod,
+// through the API server. The container is the one named in the control's
+// arguments, if any, or else the first one of the pod.
+func (r *Reporter) ExecContainer(req xfer.Request, namespaceID, podID string, containerNames []string) xfer.Response {
+	containerName, ok := req.ControlArgs["container"]
+	if !ok {
+		if len(containerNames) == 0 {
+			return xfer.ResponseErrorf("Pod has no containers: %s", podID)
+		}
+		containerName = containerNames[0]
+	} else if !contains(containerNames, containerName) {
+		return xfer.ResponseErrorf("Container not found: %s", containerName)
+	}
+
+	pipeID, pipe, err := controls.NewPipe(r.pipes, req.AppID)
+	if err != nil {
+		return xfer.ResponseError(err)
+	}
+
+	sizes := make(terminalSizes, 1)
+	r.execTTYsMutex.Lock()
+	r.execTTYs[pipeID] = sizes
+	r.execTTYsMutex.Unlock()
+
+	pipe.OnClose(func() {
+		r.execTTYsMutex.Lock()
+		delete(r.execTTYs, pipeID)
+		r.execTTYsMutex.Unlock()
+		close(sizes)
+	})
+	local, _ := pipe.Ends()
+	go func() {
+		if err := r.client.Exec(namespaceID, podID, containerName, execShellCommand, local, local, sizes); err != nil {
+			log.Errorf("Error in exec in container %s of pod %s/%s: %v", containerName, namespaceID, podID, err)
+		}
+		pipe.Close()
+	}()
+	return xfer.Response{
+		Pipe:             pipeID,
+		RawTTY:           true,
+		ResizeTTYControl: ResizeExecTTY,
+	}
+}
+
+func (r *Reporter) resizeExecTTY(pipeID string, height, width uint) xfer.Response {
+	r.execTTYsMutex.Lock()
+	defer r.execTTYsMutex.Unlock()
+	sizes, ok := r.execTTYs[pipeID]
+	if !ok {
+		return xfer.ResponseErrorf("Unknown pipeID (%q)", pipeID)
+	}
+	size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+	// Only the latest size matters, so replace any which wasn't passed on yet
+	for {
+		select {
+		case sizes <- size:
+			return xfer.Response{}
+		case <-sizes:
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Reporter) registerControls() {
 	controls := map[string]xfer.ControlHandlerFunc{
-		GetLogs:   r.CapturePod(r.GetLogs),
-		DeletePod: r.CapturePod(r.deletePod),
-		ScaleUp:   r.CaptureDeployment(r.ScaleUp),
-		ScaleDown: r.CaptureDeployment(r.ScaleDown),
+		GetLogs:        r.CapturePod(r.GetLogs),
+		DeletePod:      r.CapturePod(r.deletePod),
+		ScaleUp:        r.CaptureDeployment(r.ScaleUp),
+		ScaleDown:      r.CaptureDeployment(r.ScaleDown),
+		RestartRollout: r.CaptureResource(r.restartRollout),
+		Rollback:       r.CaptureDeployment(r.rollback),
+		ExecContainer:  r.CapturePod(r.ExecContainer),
+		ResizeExecTTY:  xfer.ResizeTTYControlWrapper(r.resizeExecTTY),
+		Describe:       r.CaptureResource(r.Describe),
 	}
 	r.handlerRegistry.Batch(nil, controls)
 }
@@ -121,6 +290,11 @@ func (r *Reporter) deregisterControls() {
 		DeletePod,
 		ScaleUp,
 		ScaleDown,
+		RestartRollout,
+		Rollback,
+		ExecContainer,
+		ResizeExecTTY,
+		Describe,
 	}
 	r.handlerRegistry.Batch(controls, nil)
 }
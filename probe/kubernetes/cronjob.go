@@ -26,6 +26,7 @@ const (
 type CronJob interface {
 	Meta
 	Selectors() ([]labels.Selector, error)
+	JobUIDs() []string
 	GetNode(probeID string) report.Node
 }
 
@@ -74,6 +75,16 @@ func (cj *cronJob) Selectors() ([]labels.Selector, error) {
 	return selectors, nil
 }
 
+// JobUIDs returns the UIDs of the active jobs of the cron job, whose
+// pods Selectors selects.
+func (cj *cronJob) JobUIDs() []string {
+	uids := make([]string, 0, len(cj.jobs))
+	for _, j := range cj.jobs {
+		uids = append(uids, string(j.UID))
+	}
+	return uids
+}
+
 func (cj *cronJob) GetNode(probeID string) report.Node {
 	latest := map[string]string{
 		NodeType:              "CronJob",
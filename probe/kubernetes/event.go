@@ -0,0 +1,125 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/weaveworks/scope/report"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// These constants are keys used in node metadata
+const (
+	EventsPrefix = "kubernetes_events_"
+	EventTime    = "kubernetes_event_time"
+	EventType    = "kubernetes_event_type"
+	EventReason  = "kubernetes_event_reason"
+	EventMessage = "kubernetes_event_message"
+	EventCount   = "kubernetes_event_count"
+)
+
+// maxObjectEvents is how many of the most recent events of an object
+// are reported.
+const maxObjectEvents = 10
+
+// ObjectEvent represents a Kubernetes event, e.g. a scheduling failure,
+// about some object, e.g. a pod.
+type ObjectEvent interface {
+	UID() string
+	InvolvedObject() (kind, namespace, name string)
+	LastTimestamp() time.Time
+	Row() report.Row
+}
+
+type objectEvent struct {
+	*apiv1.Event
+	Meta
+}
+
+// NewObjectEvent creates a new ObjectEvent
+func NewObjectEvent(e *apiv1.Event) ObjectEvent {
+	return &objectEvent{Event: e, Meta: meta{e.ObjectMeta}}
+}
+
+func (e *objectEvent) InvolvedObject() (string, string, string) {
+	o := e.Event.InvolvedObject
+	return o.Kind, o.Namespace, o.Name
+}
+
+func (e *objectEvent) LastTimestamp() time.Time {
+	if e.Event.LastTimestamp.IsZero() {
+		return e.Event.FirstTimestamp.Time
+	}
+	return e.Event.LastTimestamp.Time
+}
+
+func (e *objectEvent) Row() report.Row {
+	last := e.LastTimestamp()
+	return report.Row{
+		// Keyed by event, which is updated as it recurs; rows are
+		// ordered by time when rendered
+		ID: e.UID(),
+		Entries: map[string]string{
+			EventTime:    last.UTC().Format(time.RFC3339Nano),
+			EventType:    e.Type,
+			EventReason:  e.Reason,
+			EventMessage: e.Message,
+			EventCount:   fmt.Sprint(e.Count),
+		},
+	}
+}
+
+// objectEvents keeps the events about objects which the API server
+// still has.
+type objectEvents struct {
+	sync.Mutex
+	events map[string]map[string]ObjectEvent // object key -> event UID -> event
+}
+
+func newObjectEvents() *objectEvents {
+	return &objectEvents{events: map[string]map[string]ObjectEvent{}}
+}
+
+func objectKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func (o *objectEvents) update(e Event, event ObjectEvent) {
+	o.Lock()
+	defer o.Unlock()
+	key := objectKey(event.InvolvedObject())
+	switch e {
+	case ADD, UPDATE:
+		if o.events[key] == nil {
+			o.events[key] = map[string]ObjectEvent{}
+		}
+		o.events[key][event.UID()] = event
+	case DELETE:
+		delete(o.events[key], event.UID())
+		if len(o.events[key]) == 0 {
+			delete(o.events, key)
+		}
+	}
+}
+
+// rows returns the most recent events about an object.
+func (o *objectEvents) rows(kind, namespace, name string) []report.Row {
+	o.Lock()
+	defer o.Unlock()
+	events := make([]ObjectEvent, 0, len(o.events[objectKey(kind, namespace, name)]))
+	for _, event := range o.events[objectKey(kind, namespace, name)] {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].LastTimestamp().After(events[j].LastTimestamp()) })
+	if len(events) > maxObjectEvents {
+		events = events[:maxObjectEvents]
+	}
+	rows := make([]report.Row, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, event.Row())
+	}
+	return rows
+}
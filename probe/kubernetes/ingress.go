@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/weaveworks/scope/report"
+
+	apiextensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+)
+
+// These constants are keys used in node metadata
+const (
+	IngressHosts       = report.KubernetesIngressHosts
+	IngressAddresses   = report.KubernetesIngressAddresses
+	IngressTLSSecrets  = report.KubernetesIngressTLSSecrets
+	IngressServices    = report.KubernetesIngressServices
+	IngressRulesPrefix = "kubernetes_ingress_rules_"
+	IngressRuleHost    = "kubernetes_ingress_rule_host"
+	IngressRulePath    = "kubernetes_ingress_rule_path"
+	IngressRuleBackend = "kubernetes_ingress_rule_backend"
+)
+
+// anyHost stands for the host of rules which don't specify one, and
+// for the path of the default backend.
+const anyHost = "*"
+
+// Ingress represents a Kubernetes ingress
+type Ingress interface {
+	Meta
+	GetNode(probeID string) report.Node
+	ServiceNames() []string
+}
+
+type ingress struct {
+	*apiextensionsv1beta1.Ingress
+	Meta
+}
+
+// NewIngress creates a new Ingress
+func NewIngress(i *apiextensionsv1beta1.Ingress) Ingress {
+	return &ingress{Ingress: i, Meta: meta{i.ObjectMeta}}
+}
+
+// human-readable version of a Kubernetes IngressBackend
+func ingressBackendString(b apiextensionsv1beta1.IngressBackend) string {
+	return fmt.Sprintf("%s:%s", b.ServiceName, b.ServicePort.String())
+}
+
+// ServiceNames returns the names of the services, in the namespace of
+// the ingress, which traffic is routed to.
+func (i *ingress) ServiceNames() []string {
+	names := []string{}
+	seen := map[string]struct{}{}
+	i.walkBackends(func(_, _ string, b apiextensionsv1beta1.IngressBackend) {
+		if _, ok := seen[b.ServiceName]; ok {
+			return
+		}
+		seen[b.ServiceName] = struct{}{}
+		names = append(names, b.ServiceName)
+	})
+	return names
+}
+
+// walkBackends calls f with the host, path and backend of every path of
+// every rule, and with those of the default backend, if any.
+func (i *ingress) walkBackends(f func(host, path string, b apiextensionsv1beta1.IngressBackend)) {
+	if i.Spec.Backend != nil {
+		f(anyHost, anyHost, *i.Spec.Backend)
+	}
+	for _, rule := range i.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		host := rule.Host
+		if host == "" {
+			host = anyHost
+		}
+		for _, p := range rule.HTTP.Paths {
+			path := p.Path
+			if path == "" {
+				path = "/"
+			}
+			f(host, path, p.Backend)
+		}
+	}
+}
+
+func (i *ingress) rules() []report.Row {
+	rows := []report.Row{}
+	i.walkBackends(func(host, path string, b apiextensionsv1beta1.IngressBackend) {
+		rows = append(rows, report.Row{
+			ID: host + " " + path,
+			Entries: map[string]string{
+				IngressRuleHost:    host,
+				IngressRulePath:    path,
+				IngressRuleBackend: ingressBackendString(b),
+			},
+		})
+	})
+	return rows
+}
+
+func (i *ingress) GetNode(probeID string) report.Node {
+	latest := map[string]string{
+		report.ControlProbeID: probeID,
+	}
+	hosts := []string{}
+	for _, rule := range i.Spec.Rules {
+		if rule.Host != "" {
+			hosts = append(hosts, rule.Host)
+		}
+	}
+	if len(hosts) != 0 {
+		latest[IngressHosts] = strings.Join(hosts, ",")
+	}
+	secrets := []string{}
+	for _, tls := range i.Spec.TLS {
+		if tls.SecretName != "" {
+			secrets = append(secrets, tls.SecretName)
+		}
+	}
+	if len(secrets) != 0 {
+		latest[IngressTLSSecrets] = strings.Join(secrets, ",")
+	}
+	addresses := []string{}
+	for _, lb := range i.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+	if len(addresses) != 0 {
+		latest[IngressAddresses] = strings.Join(addresses, ",")
+	}
+	return i.MetaNode(report.MakeIngressNodeID(i.UID())).
+		WithLatests(latest).
+		AddPrefixMulticolumnTable(IngressRulesPrefix, i.rules())
+}
@@ -0,0 +1,107 @@
+package kubernetes
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/scope/report"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// These constants are keys used in node metadata
+const (
+	Completions = report.KubernetesCompletions
+	Failures    = report.KubernetesFailures
+	Duration    = report.KubernetesDuration
+)
+
+// Job states, as kubectl describes them
+const (
+	JobRunning  = "Running"
+	JobComplete = "Complete"
+	JobFailed   = "Failed"
+)
+
+// Job represents a Kubernetes job
+type Job interface {
+	Meta
+	Selector() (labels.Selector, error)
+	GetNode(probeID string) report.Node
+}
+
+type job struct {
+	*batchv1.Job
+	Meta
+}
+
+// NewJob creates a new Job
+func NewJob(j *batchv1.Job) Job {
+	return &job{Job: j, Meta: meta{j.ObjectMeta}}
+}
+
+func (j *job) Selector() (labels.Selector, error) {
+	return metav1.LabelSelectorAsSelector(j.Spec.Selector)
+}
+
+// state returns how the job went, and when it finished, if it did.
+func (j *job) state() (string, time.Time) {
+	for _, c := range j.Status.Conditions {
+		if c.Status != apiv1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return JobComplete, c.LastTransitionTime.Time
+		case batchv1.JobFailed:
+			return JobFailed, c.LastTransitionTime.Time
+		}
+	}
+	return JobRunning, time.Time{}
+}
+
+// completions is the number of pods which succeeded out of those wanted,
+// or just the former for jobs which run until any of their pods succeeds.
+func (j *job) completions() string {
+	if j.Spec.Completions == nil {
+		return fmt.Sprint(j.Status.Succeeded)
+	}
+	return fmt.Sprintf("%d/%d", j.Status.Succeeded, *j.Spec.Completions)
+}
+
+// cronJobParents returns the cron job which spawned this job, if any.
+func (j *job) cronJobParents() report.Sets {
+	parents := report.MakeSets()
+	for _, owner := range j.OwnerReferences {
+		if owner.Kind == "CronJob" {
+			parents = parents.Add(report.CronJob, report.MakeStringSet(report.MakeCronJobNodeID(string(owner.UID))))
+		}
+	}
+	return parents
+}
+
+func (j *job) GetNode(probeID string) report.Node {
+	state, finished := j.state()
+	latest := map[string]string{
+		NodeType:              "Job",
+		State:                 state,
+		Completions:           j.completions(),
+		Failures:              fmt.Sprint(j.Status.Failed),
+		report.ControlProbeID: probeID,
+	}
+	if j.Status.CompletionTime != nil {
+		finished = j.Status.CompletionTime.Time
+	} else if finished.IsZero() {
+		finished = mtime.Now()
+	}
+	if j.Status.StartTime != nil {
+		latest[Duration] = finished.Sub(j.Status.StartTime.Time).Round(time.Second).String()
+	}
+	return j.MetaNode(report.MakeJobNodeID(j.UID())).
+		WithLatests(latest).
+		WithParents(j.cronJobParents())
+}
@@ -0,0 +1,132 @@
+package kubernetes
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/weaveworks/scope/report"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// These constants are keys used in node metadata
+const (
+	KubeletVersion              = report.KubernetesKubeletVersion
+	Taints                      = report.KubernetesTaints
+	NodeConditionsPrefix        = "kubernetes_node_conditions_"
+	NodeConditionStatus         = "kubernetes_node_condition_status"
+	NodeConditionReason         = "kubernetes_node_condition_reason"
+	NodeConditionTransitionTime = "kubernetes_node_condition_transition_time"
+	NodeResourcesPrefix         = "kubernetes_node_resources_"
+	NodeResourceAllocatable     = "kubernetes_node_resource_allocatable"
+	NodeResourceCapacity        = "kubernetes_node_resource_capacity"
+)
+
+// Node states, as kubectl describes them
+const (
+	NodeReady              = "Ready"
+	NodeNotReady           = "NotReady"
+	NodeUnknown            = "Unknown"
+	NodeSchedulingDisabled = "SchedulingDisabled"
+)
+
+// Node represents a Kubernetes node
+type Node interface {
+	Meta
+	GetNode(probeID string) report.Node
+}
+
+type node struct {
+	*apiv1.Node
+	Meta
+}
+
+// NewNode creates a new Node
+func NewNode(n *apiv1.Node) Node {
+	return &node{Node: n, Meta: meta{n.ObjectMeta}}
+}
+
+func (n *node) state() string {
+	state := NodeUnknown
+	for _, c := range n.Status.Conditions {
+		if c.Type != apiv1.NodeReady {
+			continue
+		}
+		switch c.Status {
+		case apiv1.ConditionTrue:
+			state = NodeReady
+		case apiv1.ConditionFalse:
+			state = NodeNotReady
+		}
+	}
+	if n.Spec.Unschedulable {
+		state += "," + NodeSchedulingDisabled
+	}
+	return state
+}
+
+// human-readable version of a Kubernetes Taint
+func taintString(t apiv1.Taint) string {
+	if t.Value == "" {
+		return t.Key + ":" + string(t.Effect)
+	}
+	return t.Key + "=" + t.Value + ":" + string(t.Effect)
+}
+
+func (n *node) conditions() []report.Row {
+	rows := make([]report.Row, 0, len(n.Status.Conditions))
+	for _, c := range n.Status.Conditions {
+		rows = append(rows, report.Row{
+			ID: string(c.Type),
+			Entries: map[string]string{
+				NodeConditionStatus:         string(c.Status),
+				NodeConditionReason:         c.Reason,
+				NodeConditionTransitionTime: c.LastTransitionTime.Format(time.RFC3339Nano),
+			},
+		})
+	}
+	return rows
+}
+
+// resources compares what pods can be scheduled with on the node to what
+// it has in total.
+func (n *node) resources() []report.Row {
+	names := []string{}
+	for name := range n.Status.Capacity {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	rows := make([]report.Row, 0, len(names))
+	for _, name := range names {
+		capacity := n.Status.Capacity[apiv1.ResourceName(name)]
+		entries := map[string]string{
+			NodeResourceCapacity: capacity.String(),
+		}
+		if allocatable, ok := n.Status.Allocatable[apiv1.ResourceName(name)]; ok {
+			entries[NodeResourceAllocatable] = allocatable.String()
+		}
+		rows = append(rows, report.Row{ID: name, Entries: entries})
+	}
+	return rows
+}
+
+func (n *node) GetNode(probeID string) report.Node {
+	latest := map[string]string{
+		State:                 n.state(),
+		KubeletVersion:        n.Status.NodeInfo.KubeletVersion,
+		report.ControlProbeID: probeID,
+	}
+	if len(n.Spec.Taints) != 0 {
+		taints := make([]string, 0, len(n.Spec.Taints))
+		for _, t := range n.Spec.Taints {
+			taints = append(taints, taintString(t))
+		}
+		latest[Taints] = strings.Join(taints, ",")
+	}
+	return n.MetaNode(report.MakeKubernetesNodeID(n.UID())).
+		WithLatests(latest).
+		WithParents(report.MakeSets().Add(report.Host, report.MakeStringSet(report.MakeHostNodeID(n.Name())))).
+		AddPrefixMulticolumnTable(NodeConditionsPrefix, n.conditions()).
+		AddPrefixMulticolumnTable(NodeResourcesPrefix, n.resources())
+}
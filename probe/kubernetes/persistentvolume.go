@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"github.com/weaveworks/scope/report"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// These constants are keys used in node metadata
+const (
+	ReclaimPolicy = report.KubernetesReclaimPolicy
+	VolumeClaim   = report.KubernetesVolumeClaim
+)
+
+// PersistentVolume represents a Kubernetes persistent volume
+type PersistentVolume interface {
+	Meta
+	GetNode(probeID string) report.Node
+	StorageClassName() string
+}
+
+type persistentVolume struct {
+	*apiv1.PersistentVolume
+	Meta
+}
+
+// NewPersistentVolume creates a new PersistentVolume
+func NewPersistentVolume(p *apiv1.PersistentVolume) PersistentVolume {
+	return &persistentVolume{PersistentVolume: p, Meta: meta{p.ObjectMeta}}
+}
+
+func (p *persistentVolume) StorageClassName() string {
+	if p.Spec.StorageClassName != "" {
+		return p.Spec.StorageClassName
+	}
+	return p.ObjectMeta.Annotations[betaStorageClassAnnotation]
+}
+
+func (p *persistentVolume) GetNode(probeID string) report.Node {
+	latest := map[string]string{
+		State:                 string(p.Status.Phase),
+		ReclaimPolicy:         string(p.Spec.PersistentVolumeReclaimPolicy),
+		report.ControlProbeID: probeID,
+	}
+	if capacity, ok := storageString(p.Spec.Capacity); ok {
+		latest[StorageCapacity] = capacity
+	}
+	if modes := p.Spec.AccessModes; len(modes) != 0 {
+		latest[AccessModes] = accessModesString(modes)
+	}
+	if name := p.StorageClassName(); name != "" {
+		latest[StorageClassName] = name
+	}
+	if claim := p.Spec.ClaimRef; claim != nil {
+		latest[VolumeClaim] = claim.Namespace + "/" + claim.Name
+	}
+	return p.MetaNode(report.MakePersistentVolumeNodeID(p.UID())).WithLatests(latest)
+}
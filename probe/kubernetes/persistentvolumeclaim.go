@@ -0,0 +1,104 @@
+package kubernetes
+
+import (
+	"strings"
+
+	"github.com/weaveworks/scope/report"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// These constants are keys used in node metadata
+const (
+	StorageCapacity  = report.KubernetesStorageCapacity
+	StorageRequest   = report.KubernetesStorageRequest
+	AccessModes      = report.KubernetesAccessModes
+	StorageClassName = report.KubernetesStorageClassName
+	VolumeName       = report.KubernetesVolumeName
+)
+
+// betaStorageClassAnnotation is how claims referred to their storage
+// class before StorageClassName was added to their spec.
+const betaStorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+// PersistentVolumeClaim represents a Kubernetes persistent volume claim
+type PersistentVolumeClaim interface {
+	Meta
+	GetNode(probeID string) report.Node
+	VolumeName() string
+	StorageClassName() string
+}
+
+type persistentVolumeClaim struct {
+	*apiv1.PersistentVolumeClaim
+	Meta
+}
+
+// NewPersistentVolumeClaim creates a new PersistentVolumeClaim
+func NewPersistentVolumeClaim(p *apiv1.PersistentVolumeClaim) PersistentVolumeClaim {
+	return &persistentVolumeClaim{PersistentVolumeClaim: p, Meta: meta{p.ObjectMeta}}
+}
+
+func (p *persistentVolumeClaim) VolumeName() string {
+	return p.Spec.VolumeName
+}
+
+func (p *persistentVolumeClaim) StorageClassName() string {
+	if p.Spec.StorageClassName != nil {
+		return *p.Spec.StorageClassName
+	}
+	return p.ObjectMeta.Annotations[betaStorageClassAnnotation]
+}
+
+// human-readable version of Kubernetes access modes, as kubectl shows them
+func accessModesString(modes []apiv1.PersistentVolumeAccessMode) string {
+	short := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		switch mode {
+		case apiv1.ReadWriteOnce:
+			short = append(short, "RWO")
+		case apiv1.ReadOnlyMany:
+			short = append(short, "ROX")
+		case apiv1.ReadWriteMany:
+			short = append(short, "RWX")
+		default:
+			short = append(short, string(mode))
+		}
+	}
+	return strings.Join(short, ",")
+}
+
+// human-readable storage size of Kubernetes resources, if any
+func storageString(resources apiv1.ResourceList) (string, bool) {
+	quantity, ok := resources[apiv1.ResourceStorage]
+	if !ok {
+		return "", false
+	}
+	return quantity.String(), true
+}
+
+func (p *persistentVolumeClaim) GetNode(probeID string) report.Node {
+	latest := map[string]string{
+		State:                 string(p.Status.Phase),
+		report.ControlProbeID: probeID,
+	}
+	if capacity, ok := storageString(p.Status.Capacity); ok {
+		latest[StorageCapacity] = capacity
+	}
+	if request, ok := storageString(p.Spec.Resources.Requests); ok {
+		latest[StorageRequest] = request
+	}
+	// Bound claims report the access modes of their volume
+	if modes := p.Status.AccessModes; len(modes) != 0 {
+		latest[AccessModes] = accessModesString(modes)
+	} else if modes := p.Spec.AccessModes; len(modes) != 0 {
+		latest[AccessModes] = accessModesString(modes)
+	}
+	if name := p.StorageClassName(); name != "" {
+		latest[StorageClassName] = name
+	}
+	if name := p.VolumeName(); name != "" {
+		latest[VolumeName] = name
+	}
+	return p.MetaNode(report.MakePersistentVolumeClaimNodeID(p.UID())).WithLatests(latest)
+}
@@ -29,6 +29,7 @@ type Pod interface {
 	GetNode(probeID string) report.Node
 	RestartCount() uint
 	ContainerNames() []string
+	VolumeClaimNames() []string
 }
 
 type pod struct {
@@ -75,21 +76,26 @@ func (p *pod) RestartCount() uint {
 	return count
 }
 
+// GetNode returns the node of the pod, controlled by the probe probeID,
+// or without controls if probeID is empty.
 func (p *pod) GetNode(probeID string) report.Node {
 	latests := map[string]string{
-		State: p.State(),
-		IP:    p.Status.PodIP,
-		report.ControlProbeID: probeID,
-		RestartCount:          strconv.FormatUint(uint64(p.RestartCount()), 10),
+		State:        p.State(),
+		IP:           p.Status.PodIP,
+		RestartCount: strconv.FormatUint(uint64(p.RestartCount()), 10),
 	}
 
 	if p.Pod.Spec.HostNetwork {
 		latests[IsInHostNetwork] = "true"
 	}
 
-	return p.MetaNode(report.MakePodNodeID(p.UID())).WithLatests(latests).
-		WithParents(p.parents).
-		WithLatestActiveControls(GetLogs, DeletePod)
+	node := p.MetaNode(report.MakePodNodeID(p.UID())).WithParents(p.parents)
+	if probeID == "" {
+		return node.WithLatests(latests)
+	}
+	latests[report.ControlProbeID] = probeID
+	return node.WithLatests(latests).
+		WithLatestActiveControls(GetLogs, DeletePod, ExecContainer, Describe)
 }
 
 func (p *pod) ContainerNames() []string {
@@ -99,3 +105,15 @@ func (p *pod) ContainerNames() []string {
 	}
 	return containerNames
 }
+
+// VolumeClaimNames returns the names of the persistent volume claims, in
+// the namespace of the pod, which the pod mounts volumes from.
+func (p *pod) VolumeClaimNames() []string {
+	claimNames := []string{}
+	for _, v := range p.Pod.Spec.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			claimNames = append(claimNames, v.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return claimNames
+}
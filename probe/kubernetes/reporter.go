@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -98,6 +99,115 @@ var (
 
 	CronJobMetricTemplates = PodMetricTemplates
 
+	JobMetadataTemplates = report.MetadataTemplates{
+		NodeType:    {ID: NodeType, Label: "Type", From: report.FromLatest, Priority: 1},
+		Namespace:   {ID: Namespace, Label: "Namespace", From: report.FromLatest, Priority: 2},
+		Created:     {ID: Created, Label: "Created", From: report.FromLatest, Datatype: report.DateTime, Priority: 3},
+		State:       {ID: State, Label: "Status", From: report.FromLatest, Priority: 4},
+		Completions: {ID: Completions, Label: "Completions", From: report.FromLatest, Priority: 5},
+		Failures:    {ID: Failures, Label: "Failures", From: report.FromLatest, Datatype: report.Number, Priority: 6},
+		Duration:    {ID: Duration, Label: "Duration", From: report.FromLatest, Priority: 7},
+		report.Pod:  {ID: report.Pod, Label: "# Pods", From: report.FromCounters, Datatype: report.Number, Priority: 8},
+	}
+
+	JobMetricTemplates = PodMetricTemplates
+
+	NodeMetadataTemplates = report.MetadataTemplates{
+		State:          {ID: State, Label: "Status", From: report.FromLatest, Priority: 1},
+		Created:        {ID: Created, Label: "Created", From: report.FromLatest, Datatype: report.DateTime, Priority: 3},
+		KubeletVersion: {ID: KubeletVersion, Label: "Kubelet version", From: report.FromLatest, Priority: 4},
+		Taints:         {ID: Taints, Label: "Taints", From: report.FromLatest, Priority: 5},
+	}
+
+	EventTableTemplates = report.TableTemplates{
+		EventsPrefix: {
+			ID:     EventsPrefix,
+			Label:  "Events",
+			Type:   report.MulticolumnTableType,
+			Prefix: EventsPrefix,
+			Columns: []report.Column{
+				{ID: EventTime, Label: "Last seen", DataType: report.DateTime},
+				{ID: EventType, Label: "Type"},
+				{ID: EventReason, Label: "Reason"},
+				{ID: EventMessage, Label: "Message"},
+				{ID: EventCount, Label: "Count", DataType: report.Number},
+			},
+		},
+	}
+
+	NodeTableTemplates = TableTemplates.Merge(EventTableTemplates).Merge(report.TableTemplates{
+		NodeConditionsPrefix: {
+			ID:     NodeConditionsPrefix,
+			Label:  "Conditions",
+			Type:   report.MulticolumnTableType,
+			Prefix: NodeConditionsPrefix,
+			Columns: []report.Column{
+				{ID: NodeConditionStatus, Label: "Status"},
+				{ID: NodeConditionReason, Label: "Reason"},
+				{ID: NodeConditionTransitionTime, Label: "Last transition", DataType: report.DateTime},
+			},
+		},
+		NodeResourcesPrefix: {
+			ID:     NodeResourcesPrefix,
+			Label:  "Resources",
+			Type:   report.MulticolumnTableType,
+			Prefix: NodeResourcesPrefix,
+			Columns: []report.Column{
+				{ID: NodeResourceAllocatable, Label: "Allocatable"},
+				{ID: NodeResourceCapacity, Label: "Capacity"},
+			},
+		},
+	})
+
+	IngressMetadataTemplates = report.MetadataTemplates{
+		Namespace:         {ID: Namespace, Label: "Namespace", From: report.FromLatest, Priority: 2},
+		Created:           {ID: Created, Label: "Created", From: report.FromLatest, Datatype: report.DateTime, Priority: 3},
+		IngressHosts:      {ID: IngressHosts, Label: "Hosts", From: report.FromLatest, Priority: 4},
+		IngressAddresses:  {ID: IngressAddresses, Label: "Addresses", From: report.FromLatest, Priority: 5},
+		IngressTLSSecrets: {ID: IngressTLSSecrets, Label: "TLS secrets", From: report.FromLatest, Priority: 6},
+	}
+
+	PersistentVolumeClaimMetadataTemplates = report.MetadataTemplates{
+		State:            {ID: State, Label: "Phase", From: report.FromLatest, Priority: 1},
+		Namespace:        {ID: Namespace, Label: "Namespace", From: report.FromLatest, Priority: 2},
+		Created:          {ID: Created, Label: "Created", From: report.FromLatest, Datatype: report.DateTime, Priority: 3},
+		StorageCapacity:  {ID: StorageCapacity, Label: "Capacity", From: report.FromLatest, Priority: 4},
+		StorageRequest:   {ID: StorageRequest, Label: "Requested", From: report.FromLatest, Priority: 5},
+		AccessModes:      {ID: AccessModes, Label: "Access modes", From: report.FromLatest, Priority: 6},
+		StorageClassName: {ID: StorageClassName, Label: "Storage class", From: report.FromLatest, Priority: 7},
+		VolumeName:       {ID: VolumeName, Label: "Volume", From: report.FromLatest, Priority: 8},
+	}
+
+	PersistentVolumeMetadataTemplates = report.MetadataTemplates{
+		State:            {ID: State, Label: "Phase", From: report.FromLatest, Priority: 1},
+		Created:          {ID: Created, Label: "Created", From: report.FromLatest, Datatype: report.DateTime, Priority: 3},
+		StorageCapacity:  {ID: StorageCapacity, Label: "Capacity", From: report.FromLatest, Priority: 4},
+		AccessModes:      {ID: AccessModes, Label: "Access modes", From: report.FromLatest, Priority: 5},
+		ReclaimPolicy:    {ID: ReclaimPolicy, Label: "Reclaim policy", From: report.FromLatest, Priority: 6},
+		StorageClassName: {ID: StorageClassName, Label: "Storage class", From: report.FromLatest, Priority: 7},
+		VolumeClaim:      {ID: VolumeClaim, Label: "Claim", From: report.FromLatest, Priority: 8},
+	}
+
+	StorageClassMetadataTemplates = report.MetadataTemplates{
+		Created:       {ID: Created, Label: "Created", From: report.FromLatest, Datatype: report.DateTime, Priority: 3},
+		Provisioner:   {ID: Provisioner, Label: "Provisioner", From: report.FromLatest, Priority: 4},
+		ReclaimPolicy: {ID: ReclaimPolicy, Label: "Reclaim policy", From: report.FromLatest, Priority: 5},
+	}
+
+	IngressTableTemplates = TableTemplates.Merge(report.TableTemplates{
+		IngressRulesPrefix: {
+			ID:     IngressRulesPrefix,
+			Label:  "Rules",
+			Type:   report.MulticolumnTableType,
+			Prefix: IngressRulesPrefix,
+			Columns: []report.Column{
+				{ID: IngressRuleHost, Label: "Host"},
+				{ID: IngressRulePath, Label: "Path"},
+				{ID: IngressRuleBackend, Label: "Backend"},
+			},
+		},
+	})
+
 	TableTemplates = report.TableTemplates{
 		LabelPrefix: {
 			ID:     LabelPrefix,
@@ -121,6 +231,28 @@ var (
 			Rank:  1,
 		},
 	}
+
+	RolloutControls = []report.Control{
+		{
+			ID:    RestartRollout,
+			Human: "Restart rollout",
+			Icon:  "fa-refresh",
+			Rank:  2,
+		},
+		{
+			ID:    Describe,
+			Human: "Describe",
+			Icon:  "fa-file-text-o",
+			Rank:  4,
+		},
+	}
+
+	RollbackControl = report.Control{
+		ID:    Rollback,
+		Human: "Roll back",
+		Icon:  "fa-undo",
+		Rank:  3,
+	}
 )
 
 // Reporter generate Reports containing Container and ContainerImage topologies
@@ -133,10 +265,17 @@ type Reporter struct {
 	handlerRegistry *controls.HandlerRegistry
 	nodeName        string
 	kubeletPort     uint
+	events          *objectEvents // nil unless reporting events
+
+	execTTYsMutex sync.Mutex
+	execTTYs      map[string]terminalSizes // pipe ID -> terminal sizes of the exec
 }
 
-// NewReporter makes a new Reporter
-func NewReporter(client Client, pipes controls.PipeClient, probeID string, hostID string, probe *probe.Probe, handlerRegistry *controls.HandlerRegistry, nodeName string, kubeletPort uint) *Reporter {
+// NewReporter makes a new Reporter. Events are only watched, across
+// the whole cluster, when reportingEvents is set; they are then
+// reported for every pod, not only the local ones, so it only needs to
+// be set in one probe.
+func NewReporter(client Client, pipes controls.PipeClient, probeID string, hostID string, probe *probe.Probe, handlerRegistry *controls.HandlerRegistry, nodeName string, kubeletPort uint, reportingEvents bool) *Reporter {
 	reporter := &Reporter{
 		client:          client,
 		pipes:           pipes,
@@ -146,9 +285,14 @@ func NewReporter(client Client, pipes controls.PipeClient, probeID string, hostI
 		handlerRegistry: handlerRegistry,
 		nodeName:        nodeName,
 		kubeletPort:     kubeletPort,
+		execTTYs:        map[string]terminalSizes{},
 	}
 	reporter.registerControls()
 	client.WatchPods(reporter.podEvent)
+	if reportingEvents {
+		reporter.events = newObjectEvents()
+		client.WatchEvents(reporter.events.update)
+	}
 	return reporter
 }
 
@@ -158,7 +302,7 @@ func (r *Reporter) Stop() {
 }
 
 // Name of this reporter, for metrics gathering
-func (Reporter) Name() string { return "K8s" }
+func (*Reporter) Name() string { return "K8s" }
 
 func (r *Reporter) podEvent(e Event, pod Pod) {
 	switch e {
@@ -180,6 +324,14 @@ func (r *Reporter) podEvent(e Event, pod Pod) {
 	}
 }
 
+// withEvents adds the most recent events about an object to its node.
+func (r *Reporter) withEvents(n report.Node, kind, namespace, name string) report.Node {
+	if r.events == nil {
+		return n
+	}
+	return n.AddPrefixMulticolumnTable(EventsPrefix, r.events.rows(kind, namespace, name))
+}
+
 // IsPauseImageName indicates whether an image name corresponds to a
 // kubernetes pause container image.
 func IsPauseImageName(imageName string) bool {
@@ -247,11 +399,31 @@ func (r *Reporter) Report() (report.Report, error) {
 	if err != nil {
 		return result, err
 	}
+	jobTopology, jobs, err := r.jobTopology()
+	if err != nil {
+		return result, err
+	}
 	deploymentTopology, deployments, err := r.deploymentTopology()
 	if err != nil {
 		return result, err
 	}
-	podTopology, err := r.podTopology(services, deployments, daemonSets, statefulSets, cronJobs)
+	storageClassTopology, storageClasses, err := r.storageClassTopology()
+	if err != nil {
+		return result, err
+	}
+	persistentVolumeTopology, persistentVolumes, err := r.persistentVolumeTopology(storageClasses)
+	if err != nil {
+		return result, err
+	}
+	persistentVolumeClaimTopology, persistentVolumeClaims, err := r.persistentVolumeClaimTopology(persistentVolumes, storageClasses)
+	if err != nil {
+		return result, err
+	}
+	podTopology, err := r.podTopology(services, deployments, daemonSets, statefulSets, cronJobs, jobs, persistentVolumeClaims)
+	if err != nil {
+		return result, err
+	}
+	ingressTopology, err := r.ingressTopology(services)
 	if err != nil {
 		return result, err
 	}
@@ -259,14 +431,24 @@ func (r *Reporter) Report() (report.Report, error) {
 	if err != nil {
 		return result, err
 	}
+	nodeTopology, err := r.nodeTopology()
+	if err != nil {
+		return result, err
+	}
 	result.Pod = result.Pod.Merge(podTopology)
 	result.Service = result.Service.Merge(serviceTopology)
 	result.Host = result.Host.Merge(hostTopology)
 	result.DaemonSet = result.DaemonSet.Merge(daemonSetTopology)
 	result.StatefulSet = result.StatefulSet.Merge(statefulSetTopology)
 	result.CronJob = result.CronJob.Merge(cronJobTopology)
+	result.Job = result.Job.Merge(jobTopology)
 	result.Deployment = result.Deployment.Merge(deploymentTopology)
+	result.Ingress = result.Ingress.Merge(ingressTopology)
+	result.PersistentVolumeClaim = result.PersistentVolumeClaim.Merge(persistentVolumeClaimTopology)
+	result.PersistentVolume = result.PersistentVolume.Merge(persistentVolumeTopology)
+	result.StorageClass = result.StorageClass.Merge(storageClassTopology)
 	result.Namespace = result.Namespace.Merge(namespaceTopology)
+	result.KubernetesNode = result.KubernetesNode.Merge(nodeTopology)
 	return result, nil
 }
 
@@ -290,29 +472,43 @@ func (r *Reporter) serviceTopology() (report.Topology, []Service, error) {
 // virtual service IPs attributed to the internet. The global
 // service-cluster-ip-range is not exposed by the API server (see
 // https://github.com/kubernetes/kubernetes/issues/25533), so instead
-// we synthesise it by computing the smallest network that contains
-// all service IPs. That network may be smaller than the actual range
-// but that is ok, since in the end all we care about is that it
-// contains all the service IPs.
+// we synthesise it by computing the smallest networks, one per IP
+// version, that contain all service IPs. Those networks may be smaller
+// than the actual ranges but that is ok, since in the end all we care
+// about is that they contain all the service IPs.
 //
 // The right way of fixing this is performing DNAT mapping on
 // persistent connections for which we don't have a robust solution
 // (see https://github.com/weaveworks/scope/issues/1491).
 func (r *Reporter) hostTopology(services []Service) report.Topology {
-	serviceIPs := make([]net.IP, 0, len(services))
+	var serviceIPv4s, serviceIPv6s []net.IP
 	for _, service := range services {
-		if ip := net.ParseIP(service.ClusterIP()).To4(); ip != nil {
-			serviceIPs = append(serviceIPs, ip)
+		ip := net.ParseIP(service.ClusterIP())
+		if ip == nil {
+			continue
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			serviceIPv4s = append(serviceIPv4s, ip4)
+		} else {
+			serviceIPv6s = append(serviceIPv6s, ip)
+		}
+	}
+	serviceNetworks := []string{}
+	for _, network := range []*net.IPNet{
+		report.ContainingIPv4Network(serviceIPv4s),
+		report.ContainingIPv6Network(serviceIPv6s),
+	} {
+		if network != nil {
+			serviceNetworks = append(serviceNetworks, network.String())
 		}
 	}
-	serviceNetwork := report.ContainingIPv4Network(serviceIPs)
-	if serviceNetwork == nil {
+	if len(serviceNetworks) == 0 {
 		return report.MakeTopology()
 	}
 	t := report.MakeTopology()
 	t.AddNode(
 		report.MakeNode(report.MakeHostNodeID(r.hostID)).
-			WithSets(report.MakeSets().Add(host.LocalNetworks, report.MakeStringSet(serviceNetwork.String()))))
+			WithSets(report.MakeSets().Add(host.LocalNetworks, report.MakeStringSet(serviceNetworks...))))
 	return t
 }
 
@@ -321,13 +517,15 @@ func (r *Reporter) deploymentTopology() (report.Topology, []Deployment, error) {
 		result = report.MakeTopology().
 			WithMetadataTemplates(DeploymentMetadataTemplates).
 			WithMetricTemplates(DeploymentMetricTemplates).
-			WithTableTemplates(TableTemplates)
+			WithTableTemplates(TableTemplates.Merge(EventTableTemplates))
 		deployments = []Deployment{}
 	)
 	result.Controls.AddControls(ScalingControls)
+	result.Controls.AddControls(RolloutControls)
+	result.Controls.AddControl(RollbackControl)
 
 	err := r.client.WalkDeployments(func(d Deployment) error {
-		result.AddNode(d.GetNode(r.probeID))
+		result.AddNode(r.withEvents(d.GetNode(r.probeID), "Deployment", d.Namespace(), d.Name()))
 		deployments = append(deployments, d)
 		return nil
 	})
@@ -340,6 +538,7 @@ func (r *Reporter) daemonSetTopology() (report.Topology, []DaemonSet, error) {
 		WithMetadataTemplates(DaemonSetMetadataTemplates).
 		WithMetricTemplates(DaemonSetMetricTemplates).
 		WithTableTemplates(TableTemplates)
+	result.Controls.AddControls(RolloutControls)
 	err := r.client.WalkDaemonSets(func(d DaemonSet) error {
 		result.AddNode(d.GetNode(r.probeID))
 		daemonSets = append(daemonSets, d)
@@ -353,9 +552,10 @@ func (r *Reporter) statefulSetTopology() (report.Topology, []StatefulSet, error)
 	result := report.MakeTopology().
 		WithMetadataTemplates(StatefulSetMetadataTemplates).
 		WithMetricTemplates(StatefulSetMetricTemplates).
-		WithTableTemplates(TableTemplates)
+		WithTableTemplates(TableTemplates.Merge(EventTableTemplates))
+	result.Controls.AddControls(RolloutControls)
 	err := r.client.WalkStatefulSets(func(s StatefulSet) error {
-		result.AddNode(s.GetNode(r.probeID))
+		result.AddNode(r.withEvents(s.GetNode(r.probeID), "StatefulSet", s.Namespace(), s.Name()))
 		statefulSets = append(statefulSets, s)
 		return nil
 	})
@@ -376,6 +576,109 @@ func (r *Reporter) cronJobTopology() (report.Topology, []CronJob, error) {
 	return result, cronJobs, err
 }
 
+func (r *Reporter) jobTopology() (report.Topology, []Job, error) {
+	jobs := []Job{}
+	result := report.MakeTopology().
+		WithMetadataTemplates(JobMetadataTemplates).
+		WithMetricTemplates(JobMetricTemplates).
+		WithTableTemplates(TableTemplates)
+	err := r.client.WalkJobs(func(j Job) error {
+		result.AddNode(j.GetNode(r.probeID))
+		jobs = append(jobs, j)
+		return nil
+	})
+	return result, jobs, err
+}
+
+// ingressTopology reports ingresses, along with the services they route
+// traffic to; those are resolved by name, so only services reported
+// here can be linked to.
+func (r *Reporter) ingressTopology(services []Service) (report.Topology, error) {
+	serviceIDs := map[string]string{}
+	for _, service := range services {
+		serviceIDs[service.Namespace()+"/"+service.Name()] = report.MakeServiceNodeID(service.UID())
+	}
+	result := report.MakeTopology().
+		WithMetadataTemplates(IngressMetadataTemplates).
+		WithTableTemplates(IngressTableTemplates)
+	err := r.client.WalkIngresses(func(i Ingress) error {
+		node := i.GetNode(r.probeID)
+		ids := []string{}
+		for _, name := range i.ServiceNames() {
+			if id, ok := serviceIDs[i.Namespace()+"/"+name]; ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) != 0 {
+			node = node.WithSets(report.MakeSets().Add(IngressServices, report.MakeStringSet(ids...)))
+		}
+		result.AddNode(node)
+		return nil
+	})
+	return result, err
+}
+
+func (r *Reporter) storageClassTopology() (report.Topology, []StorageClass, error) {
+	storageClasses := []StorageClass{}
+	result := report.MakeTopology().
+		WithMetadataTemplates(StorageClassMetadataTemplates).
+		WithTableTemplates(TableTemplates)
+	err := r.client.WalkStorageClasses(func(s StorageClass) error {
+		result.AddNode(s.GetNode(r.probeID))
+		storageClasses = append(storageClasses, s)
+		return nil
+	})
+	return result, storageClasses, err
+}
+
+// storageClassParents returns the parents of objects of the storage
+// class called name, if it is one of storageClasses.
+func storageClassParents(storageClasses []StorageClass, name string) report.Sets {
+	parents := report.MakeSets()
+	for _, s := range storageClasses {
+		if s.Name() == name {
+			parents = parents.Add(report.StorageClass, report.MakeStringSet(report.MakeStorageClassNodeID(s.UID())))
+		}
+	}
+	return parents
+}
+
+func (r *Reporter) persistentVolumeTopology(storageClasses []StorageClass) (report.Topology, []PersistentVolume, error) {
+	persistentVolumes := []PersistentVolume{}
+	result := report.MakeTopology().
+		WithMetadataTemplates(PersistentVolumeMetadataTemplates).
+		WithTableTemplates(TableTemplates)
+	err := r.client.WalkPersistentVolumes(func(p PersistentVolume) error {
+		result.AddNode(p.GetNode(r.probeID).WithParents(storageClassParents(storageClasses, p.StorageClassName())))
+		persistentVolumes = append(persistentVolumes, p)
+		return nil
+	})
+	return result, persistentVolumes, err
+}
+
+// persistentVolumeClaimTopology reports claims, with the volumes they
+// are bound to and their storage classes as parents.
+func (r *Reporter) persistentVolumeClaimTopology(persistentVolumes []PersistentVolume, storageClasses []StorageClass) (report.Topology, []PersistentVolumeClaim, error) {
+	volumeIDs := map[string]string{}
+	for _, p := range persistentVolumes {
+		volumeIDs[p.Name()] = report.MakePersistentVolumeNodeID(p.UID())
+	}
+	persistentVolumeClaims := []PersistentVolumeClaim{}
+	result := report.MakeTopology().
+		WithMetadataTemplates(PersistentVolumeClaimMetadataTemplates).
+		WithTableTemplates(TableTemplates)
+	err := r.client.WalkPersistentVolumeClaims(func(p PersistentVolumeClaim) error {
+		parents := storageClassParents(storageClasses, p.StorageClassName())
+		if id, ok := volumeIDs[p.VolumeName()]; ok {
+			parents = parents.Add(report.PersistentVolume, report.MakeStringSet(id))
+		}
+		result.AddNode(p.GetNode(r.probeID).WithParents(parents))
+		persistentVolumeClaims = append(persistentVolumeClaims, p)
+		return nil
+	})
+	return result, persistentVolumeClaims, err
+}
+
 type labelledChild interface {
 	Labels() map[string]string
 	AddParent(string, string)
@@ -391,12 +694,12 @@ func match(namespace string, selector labels.Selector, topology, id string) func
 	}
 }
 
-func (r *Reporter) podTopology(services []Service, deployments []Deployment, daemonSets []DaemonSet, statefulSets []StatefulSet, cronJobs []CronJob) (report.Topology, error) {
+func (r *Reporter) podTopology(services []Service, deployments []Deployment, daemonSets []DaemonSet, statefulSets []StatefulSet, cronJobs []CronJob, jobs []Job, persistentVolumeClaims []PersistentVolumeClaim) (report.Topology, error) {
 	var (
 		pods = report.MakeTopology().
 			WithMetadataTemplates(PodMetadataTemplates).
 			WithMetricTemplates(PodMetricTemplates).
-			WithTableTemplates(TableTemplates)
+			WithTableTemplates(TableTemplates.Merge(EventTableTemplates))
 		selectors = []func(labelledChild){}
 	)
 	pods.Controls.AddControl(report.Control{
@@ -411,6 +714,18 @@ func (r *Reporter) podTopology(services []Service, deployments []Deployment, dae
 		Icon:  "fa-trash-o",
 		Rank:  1,
 	})
+	pods.Controls.AddControl(report.Control{
+		ID:    ExecContainer,
+		Human: "Exec shell",
+		Icon:  "fa-terminal",
+		Rank:  2,
+	})
+	pods.Controls.AddControl(report.Control{
+		ID:    Describe,
+		Human: "Describe",
+		Icon:  "fa-file-text-o",
+		Rank:  3,
+	})
 	for _, service := range services {
 		selectors = append(selectors, match(
 			service.Namespace(),
@@ -455,7 +770,13 @@ func (r *Reporter) podTopology(services []Service, deployments []Deployment, dae
 			report.MakeStatefulSetNodeID(statefulSet.UID()),
 		))
 	}
+	// The pods of the jobs of a cron job only get the cron job as a
+	// parent, so that they aren't counted twice
+	cronJobJobs := map[string]struct{}{}
 	for _, cronJob := range cronJobs {
+		for _, uid := range cronJob.JobUIDs() {
+			cronJobJobs[uid] = struct{}{}
+		}
 		cronJobSelectors, err := cronJob.Selectors()
 		if err != nil {
 			return pods, err
@@ -469,6 +790,26 @@ func (r *Reporter) podTopology(services []Service, deployments []Deployment, dae
 			))
 		}
 	}
+	for _, job := range jobs {
+		if _, ok := cronJobJobs[job.UID()]; ok {
+			continue
+		}
+		selector, err := job.Selector()
+		if err != nil {
+			return pods, err
+		}
+		selectors = append(selectors, match(
+			job.Namespace(),
+			selector,
+			report.Job,
+			report.MakeJobNodeID(job.UID()),
+		))
+	}
+
+	claimIDs := map[string]string{}
+	for _, claim := range persistentVolumeClaims {
+		claimIDs[claim.Namespace()+"/"+claim.Name()] = report.MakePersistentVolumeClaimNodeID(claim.UID())
+	}
 
 	var localPodUIDs map[string]struct{}
 	if r.nodeName == "" {
@@ -480,25 +821,41 @@ func (r *Reporter) podTopology(services []Service, deployments []Deployment, dae
 		}
 	}
 	err := r.client.WalkPods(func(p Pod) error {
-		// filter out non-local pods: we only want to report local ones for performance reasons.
-		if r.nodeName != "" {
-			if p.NodeName() != r.nodeName {
-				return nil
-			}
-		} else if localPodUIDs != nil {
-			if _, ok := localPodUIDs[p.UID()]; !ok {
+		// filter out non-local pods: we only want to report local ones for performance reasons,
+		// unless they have events, which no other probe reports. Those are
+		// left for the probe on their node, if any, to control.
+		probeID := r.probeID
+		if !r.isLocalPod(p, localPodUIDs) {
+			if r.events == nil || len(r.events.rows("Pod", p.Namespace(), p.Name())) == 0 {
 				return nil
 			}
+			probeID = ""
 		}
 		for _, selector := range selectors {
 			selector(p)
 		}
-		pods.AddNode(p.GetNode(r.probeID))
+		for _, name := range p.VolumeClaimNames() {
+			if id, ok := claimIDs[p.Namespace()+"/"+name]; ok {
+				p.AddParent(report.PersistentVolumeClaim, id)
+			}
+		}
+		pods.AddNode(r.withEvents(p.GetNode(probeID), "Pod", p.Namespace(), p.Name()))
 		return nil
 	})
 	return pods, err
 }
 
+// isLocalPod tells whether a pod runs on the node of the probe.
+func (r *Reporter) isLocalPod(p Pod, localPodUIDs map[string]struct{}) bool {
+	if r.nodeName != "" {
+		return p.NodeName() == r.nodeName
+	} else if localPodUIDs != nil {
+		_, ok := localPodUIDs[p.UID()]
+		return ok
+	}
+	return true
+}
+
 func (r *Reporter) namespaceTopology() (report.Topology, error) {
 	result := report.MakeTopology()
 	err := r.client.WalkNamespaces(func(ns NamespaceResource) error {
@@ -507,3 +864,14 @@ func (r *Reporter) namespaceTopology() (report.Topology, error) {
 	})
 	return result, err
 }
+
+func (r *Reporter) nodeTopology() (report.Topology, error) {
+	result := report.MakeTopology().
+		WithMetadataTemplates(NodeMetadataTemplates).
+		WithTableTemplates(NodeTableTemplates)
+	err := r.client.WalkNodes(func(n Node) error {
+		result.AddNode(r.withEvents(n.GetNode(r.probeID), "Node", "", n.Name()))
+		return nil
+	})
+	return result, err
+}
@@ -6,10 +6,18 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
+	apibatchv1 "k8s.io/api/batch/v1"
+	apibatchv1beta1 "k8s.io/api/batch/v1beta1"
 	apiv1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apistoragev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/remotecommand"
 
 	"github.com/weaveworks/scope/common/xfer"
 	"github.com/weaveworks/scope/probe/controls"
@@ -24,6 +32,14 @@ var (
 	pod1UID     = "a1b2c3d4e5"
 	pod2UID     = "f6g7h8i9j0"
 	serviceUID  = "service1234"
+	ingressUID  = "ingress1234"
+	pvcUID      = "pvc1234"
+	pvUID       = "pv1234"
+	classUID    = "class1234"
+	className   = "standard"
+	jobUID      = "job1234"
+	cronJobUID  = "cronjob1234"
+	nodeUID     = "node1234"
 	podTypeMeta = metav1.TypeMeta{
 		Kind:       "Pod",
 		APIVersion: "v1",
@@ -47,6 +63,18 @@ var (
 		Spec: apiv1.PodSpec{
 			NodeName:    nodeName,
 			HostNetwork: true,
+			Containers: []apiv1.Container{
+				{Name: "pong"},
+				{Name: "sidecar"},
+			},
+			Volumes: []apiv1.Volume{
+				{
+					Name: "data",
+					VolumeSource: apiv1.VolumeSource{
+						PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: "pongdata"},
+					},
+				},
+			},
 		},
 	}
 	apiPod2 = apiv1.Pod{
@@ -56,7 +84,7 @@ var (
 			UID:               types.UID(pod2UID),
 			Namespace:         "ping",
 			CreationTimestamp: metav1.Now(),
-			Labels:            map[string]string{"ponger": "true"},
+			Labels:            map[string]string{"ponger": "true", "job-name": "pongjob"},
 		},
 		Status: apiv1.PodStatus{
 			HostIP: "1.2.3.4",
@@ -97,23 +125,191 @@ var (
 			},
 		},
 	}
+	apiIngress1 = apiextensionsv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pongingress",
+			UID:               types.UID(ingressUID),
+			Namespace:         "ping",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: apiextensionsv1beta1.IngressSpec{
+			Backend: &apiextensionsv1beta1.IngressBackend{
+				ServiceName: "notfound",
+				ServicePort: intstr.FromString("http"),
+			},
+			TLS: []apiextensionsv1beta1.IngressTLS{
+				{Hosts: []string{"pong.example.com"}, SecretName: "pongcert"},
+			},
+			Rules: []apiextensionsv1beta1.IngressRule{
+				{
+					Host: "pong.example.com",
+					IngressRuleValue: apiextensionsv1beta1.IngressRuleValue{
+						HTTP: &apiextensionsv1beta1.HTTPIngressRuleValue{
+							Paths: []apiextensionsv1beta1.HTTPIngressPath{
+								{
+									Path: "/pong",
+									Backend: apiextensionsv1beta1.IngressBackend{
+										ServiceName: "pongservice",
+										ServicePort: intstr.FromInt(6379),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: apiextensionsv1beta1.IngressStatus{
+			LoadBalancer: apiv1.LoadBalancerStatus{
+				Ingress: []apiv1.LoadBalancerIngress{
+					{IP: "10.0.2.1"},
+				},
+			},
+		},
+	}
+	apiPersistentVolumeClaim1 = apiv1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pongdata",
+			UID:               types.UID(pvcUID),
+			Namespace:         "ping",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: apiv1.PersistentVolumeClaimSpec{
+			AccessModes: []apiv1.PersistentVolumeAccessMode{apiv1.ReadWriteOnce},
+			Resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+			VolumeName:       "pv-pongdata",
+			StorageClassName: &className,
+		},
+		Status: apiv1.PersistentVolumeClaimStatus{
+			Phase:       apiv1.ClaimBound,
+			AccessModes: []apiv1.PersistentVolumeAccessMode{apiv1.ReadWriteOnce},
+			Capacity:    apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("2Gi")},
+		},
+	}
+	apiPersistentVolume1 = apiv1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pv-pongdata",
+			UID:               types.UID(pvUID),
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: apiv1.PersistentVolumeSpec{
+			Capacity:                      apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("2Gi")},
+			AccessModes:                   []apiv1.PersistentVolumeAccessMode{apiv1.ReadWriteOnce, apiv1.ReadOnlyMany},
+			ClaimRef:                      &apiv1.ObjectReference{Namespace: "ping", Name: "pongdata"},
+			PersistentVolumeReclaimPolicy: apiv1.PersistentVolumeReclaimRetain,
+			StorageClassName:              className,
+		},
+		Status: apiv1.PersistentVolumeStatus{
+			Phase: apiv1.VolumeBound,
+		},
+	}
+	apiStorageClass1 = apistoragev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              className,
+			UID:               types.UID(classUID),
+			CreationTimestamp: metav1.Now(),
+		},
+		Provisioner: "kubernetes.io/gce-pd",
+	}
+	jobStart = time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	apiJob1  = apibatchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pongjob",
+			UID:               types.UID(jobUID),
+			Namespace:         "ping",
+			CreationTimestamp: metav1.Now(),
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CronJob", Name: "pongcron", UID: types.UID(cronJobUID)},
+			},
+		},
+		Spec: apibatchv1.JobSpec{
+			Completions: &[]int32{3}[0],
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"job-name": "pongjob"},
+			},
+		},
+		Status: apibatchv1.JobStatus{
+			Conditions: []apibatchv1.JobCondition{
+				{
+					Type:               apibatchv1.JobFailed,
+					Status:             apiv1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(jobStart.Add(90 * time.Second)),
+				},
+			},
+			StartTime: &metav1.Time{Time: jobStart},
+			Succeeded: 1,
+			Failed:    6,
+		},
+	}
+	apiNode1 = apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              nodeName,
+			UID:               types.UID(nodeUID),
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: apiv1.NodeSpec{
+			Unschedulable: true,
+			Taints: []apiv1.Taint{
+				{Key: "dedicated", Value: "pong", Effect: apiv1.TaintEffectNoSchedule},
+			},
+		},
+		Status: apiv1.NodeStatus{
+			Capacity: apiv1.ResourceList{
+				apiv1.ResourceCPU:    resource.MustParse("2"),
+				apiv1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			Allocatable: apiv1.ResourceList{
+				apiv1.ResourceCPU:    resource.MustParse("1900m"),
+				apiv1.ResourceMemory: resource.MustParse("3Gi"),
+			},
+			Conditions: []apiv1.NodeCondition{
+				{Type: apiv1.NodeReady, Status: apiv1.ConditionFalse, Reason: "KubeletNotReady"},
+			},
+			NodeInfo: apiv1.NodeSystemInfo{KubeletVersion: "v1.9.3"},
+		},
+	}
 	pod1     = kubernetes.NewPod(&apiPod1)
 	pod2     = kubernetes.NewPod(&apiPod2)
 	service1 = kubernetes.NewService(&apiService1)
+	ingress1 = kubernetes.NewIngress(&apiIngress1)
+	pvc1     = kubernetes.NewPersistentVolumeClaim(&apiPersistentVolumeClaim1)
+	pv1      = kubernetes.NewPersistentVolume(&apiPersistentVolume1)
+	class1   = kubernetes.NewStorageClass(&apiStorageClass1)
+	job1     = kubernetes.NewJob(&apiJob1)
+	node1    = kubernetes.NewNode(&apiNode1)
 )
 
 func newMockClient() *mockClient {
 	return &mockClient{
-		pods:     []kubernetes.Pod{pod1, pod2},
-		services: []kubernetes.Service{service1},
-		logs:     map[string]io.ReadCloser{},
+		pods:      []kubernetes.Pod{pod1, pod2},
+		services:  []kubernetes.Service{service1},
+		ingresses: []kubernetes.Ingress{ingress1},
+		pvcs:      []kubernetes.PersistentVolumeClaim{pvc1},
+		pvs:       []kubernetes.PersistentVolume{pv1},
+		classes:   []kubernetes.StorageClass{class1},
+		jobs:      []kubernetes.Job{job1},
+		nodes:     []kubernetes.Node{node1},
+		logs:      map[string]io.ReadCloser{},
+		execs:     make(chan string, 1),
 	}
 }
 
 type mockClient struct {
-	pods     []kubernetes.Pod
-	services []kubernetes.Service
-	logs     map[string]io.ReadCloser
+	pods      []kubernetes.Pod
+	services  []kubernetes.Service
+	ingresses []kubernetes.Ingress
+	pvcs      []kubernetes.PersistentVolumeClaim
+	pvs       []kubernetes.PersistentVolume
+	classes   []kubernetes.StorageClass
+	cronJobs  []kubernetes.CronJob
+	jobs      []kubernetes.Job
+	nodes     []kubernetes.Node
+	events    []func(kubernetes.Event, kubernetes.ObjectEvent)
+	logs      map[string]io.ReadCloser
+	rollouts  []string
+	execs     chan string
 }
 
 func (c *mockClient) Stop() {}
@@ -140,15 +336,71 @@ func (c *mockClient) WalkStatefulSets(f func(kubernetes.StatefulSet) error) erro
 	return nil
 }
 func (c *mockClient) WalkCronJobs(f func(kubernetes.CronJob) error) error {
+	for _, cronJob := range c.cronJobs {
+		if err := f(cronJob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (c *mockClient) WalkJobs(f func(kubernetes.Job) error) error {
+	for _, job := range c.jobs {
+		if err := f(job); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 func (c *mockClient) WalkDeployments(f func(kubernetes.Deployment) error) error {
 	return nil
 }
+func (c *mockClient) WalkIngresses(f func(kubernetes.Ingress) error) error {
+	for _, ingress := range c.ingresses {
+		if err := f(ingress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (c *mockClient) WalkPersistentVolumeClaims(f func(kubernetes.PersistentVolumeClaim) error) error {
+	for _, pvc := range c.pvcs {
+		if err := f(pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (c *mockClient) WalkPersistentVolumes(f func(kubernetes.PersistentVolume) error) error {
+	for _, pv := range c.pvs {
+		if err := f(pv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (c *mockClient) WalkStorageClasses(f func(kubernetes.StorageClass) error) error {
+	for _, class := range c.classes {
+		if err := f(class); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 func (c *mockClient) WalkNamespaces(f func(kubernetes.NamespaceResource) error) error {
 	return nil
 }
+func (c *mockClient) WalkNodes(f func(kubernetes.Node) error) error {
+	for _, node := range c.nodes {
+		if err := f(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 func (*mockClient) WatchPods(func(kubernetes.Event, kubernetes.Pod)) {}
+func (c *mockClient) WatchEvents(f func(kubernetes.Event, kubernetes.ObjectEvent)) {
+	c.events = append(c.events, f)
+}
 func (c *mockClient) GetLogs(namespaceID, podName string, _ []string) (io.ReadCloser, error) {
 	r, ok := c.logs[namespaceID+";"+podName]
 	if !ok {
@@ -165,6 +417,20 @@ func (c *mockClient) ScaleUp(resource, namespaceID, id string) error {
 func (c *mockClient) ScaleDown(resource, namespaceID, id string) error {
 	return nil
 }
+func (c *mockClient) RestartRollout(resource, namespaceID, id string) error {
+	c.rollouts = append(c.rollouts, resource+";"+namespaceID+";"+id)
+	return nil
+}
+func (c *mockClient) Rollback(namespaceID, id string) error {
+	return nil
+}
+func (c *mockClient) Exec(namespaceID, podID, containerName string, _ []string, _ io.Reader, _ io.Writer, _ remotecommand.TerminalSizeQueue) error {
+	c.execs <- namespaceID + ";" + podID + ";" + containerName
+	return nil
+}
+func (c *mockClient) Describe(resource, namespaceID, id string) ([]byte, error) {
+	return []byte("resource: " + resource + "\nnamespace: " + namespaceID + "\nname: " + id + "\n"), nil
+}
 
 type mockPipeClient map[string]xfer.Pipe
 
@@ -194,7 +460,7 @@ func TestReporter(t *testing.T) {
 	pod2ID := report.MakePodNodeID(pod2UID)
 	serviceID := report.MakeServiceNodeID(serviceUID)
 	hr := controls.NewDefaultHandlerRegistry()
-	rpt, _ := kubernetes.NewReporter(newMockClient(), nil, "probe-id", "foo", nil, hr, "", 0).Report()
+	rpt, _ := kubernetes.NewReporter(newMockClient(), nil, "probe-id", "foo", nil, hr, "", 0, false).Report()
 
 	// Reporter should have added the following pods
 	for _, pod := range []struct {
@@ -247,6 +513,174 @@ func TestReporter(t *testing.T) {
 		}
 	}
 
+	// Reporter should have added an ingress, routing to the service
+	{
+		ingressID := report.MakeIngressNodeID(ingressUID)
+		node, ok := rpt.Ingress.Nodes[ingressID]
+		if !ok {
+			t.Errorf("Expected report to have ingress %q, but not found", ingressID)
+		}
+
+		for k, want := range map[string]string{
+			kubernetes.Name:              "pongingress",
+			kubernetes.Namespace:         "ping",
+			kubernetes.IngressHosts:      "pong.example.com",
+			kubernetes.IngressAddresses:  "10.0.2.1",
+			kubernetes.IngressTLSSecrets: "pongcert",
+		} {
+			if have, ok := node.Latest.Lookup(k); !ok || have != want {
+				t.Errorf("Expected ingress %s latest %q: %q, got %q", ingressID, k, want, have)
+			}
+		}
+
+		want := report.MakeStringSet(serviceID)
+		if have, _ := node.Sets.Lookup(kubernetes.IngressServices); !reflect.DeepEqual(want, have) {
+			t.Errorf("Expected ingress %s to route to %v, got %v", ingressID, want, have)
+		}
+
+		rules := node.ExtractMulticolumnTable(kubernetes.IngressTableTemplates[kubernetes.IngressRulesPrefix])
+		wantRules := []report.Row{
+			{ID: "* *", Entries: map[string]string{
+				kubernetes.IngressRuleHost:    "*",
+				kubernetes.IngressRulePath:    "*",
+				kubernetes.IngressRuleBackend: "notfound:http",
+			}},
+			{ID: "pong.example.com /pong", Entries: map[string]string{
+				kubernetes.IngressRuleHost:    "pong.example.com",
+				kubernetes.IngressRulePath:    "/pong",
+				kubernetes.IngressRuleBackend: "pongservice:6379",
+			}},
+		}
+		if !reflect.DeepEqual(wantRules, rules) {
+			t.Errorf("Expected ingress %s rules %v, got %v", ingressID, wantRules, rules)
+		}
+	}
+
+	// Reporter should have attached the first pod to its claim, the
+	// claim to its volume, and both to their storage class
+	{
+		pvcID := report.MakePersistentVolumeClaimNodeID(pvcUID)
+		pvID := report.MakePersistentVolumeNodeID(pvUID)
+		classID := report.MakeStorageClassNodeID(classUID)
+		for _, parent := range []struct {
+			topology string
+			child    report.Node
+			parent   string
+		}{
+			{report.PersistentVolumeClaim, rpt.Pod.Nodes[pod1ID], pvcID},
+			{report.PersistentVolume, rpt.PersistentVolumeClaim.Nodes[pvcID], pvID},
+			{report.StorageClass, rpt.PersistentVolumeClaim.Nodes[pvcID], classID},
+			{report.StorageClass, rpt.PersistentVolume.Nodes[pvID], classID},
+		} {
+			if parents, ok := parent.child.Parents.Lookup(parent.topology); !ok || !parents.Contains(parent.parent) {
+				t.Errorf("Expected %q to have parent %q, got %q", parent.child.ID, parent.parent, parents)
+			}
+		}
+		if _, ok := rpt.Pod.Nodes[pod2ID].Parents.Lookup(report.PersistentVolumeClaim); ok {
+			t.Errorf("Expected pod %s not to have a claim", pod2ID)
+		}
+
+		for id, want := range map[string]map[string]string{
+			pvcID: {
+				kubernetes.State:            "Bound",
+				kubernetes.StorageCapacity:  "2Gi",
+				kubernetes.StorageRequest:   "1Gi",
+				kubernetes.AccessModes:      "RWO",
+				kubernetes.StorageClassName: className,
+				kubernetes.VolumeName:       "pv-pongdata",
+			},
+			pvID: {
+				kubernetes.State:           "Bound",
+				kubernetes.StorageCapacity: "2Gi",
+				kubernetes.AccessModes:     "RWO,ROX",
+				kubernetes.ReclaimPolicy:   "Retain",
+				kubernetes.VolumeClaim:     "ping/pongdata",
+			},
+			classID: {
+				kubernetes.Name:        className,
+				kubernetes.Provisioner: "kubernetes.io/gce-pd",
+			},
+		} {
+			node, ok := rpt.PersistentVolumeClaim.Nodes[id]
+			if !ok {
+				node, ok = rpt.PersistentVolume.Nodes[id]
+			}
+			if !ok {
+				node = rpt.StorageClass.Nodes[id]
+			}
+			for k, want := range want {
+				if have, ok := node.Latest.Lookup(k); !ok || have != want {
+					t.Errorf("Expected %s latest %q: %q, got %q", id, k, want, have)
+				}
+			}
+		}
+	}
+
+	// Reporter should have added a failed job, with its pod and cron job
+	{
+		jobID := report.MakeJobNodeID(jobUID)
+		node, ok := rpt.Job.Nodes[jobID]
+		if !ok {
+			t.Errorf("Expected report to have job %q, but not found", jobID)
+		}
+		for k, want := range map[string]string{
+			kubernetes.Name:        "pongjob",
+			kubernetes.State:       kubernetes.JobFailed,
+			kubernetes.Completions: "1/3",
+			kubernetes.Failures:    "6",
+			kubernetes.Duration:    "1m30s",
+		} {
+			if have, ok := node.Latest.Lookup(k); !ok || have != want {
+				t.Errorf("Expected job %s latest %q: %q, got %q", jobID, k, want, have)
+			}
+		}
+		if parents, ok := node.Parents.Lookup(report.CronJob); !ok || !parents.Contains(report.MakeCronJobNodeID(cronJobUID)) {
+			t.Errorf("Expected job %s to have parent cron job, got %q", jobID, parents)
+		}
+		if parents, ok := rpt.Pod.Nodes[pod2ID].Parents.Lookup(report.Job); !ok || !parents.Contains(jobID) {
+			t.Errorf("Expected pod %s to have parent job %q, got %q", pod2ID, jobID, parents)
+		}
+		if _, ok := rpt.Pod.Nodes[pod1ID].Parents.Lookup(report.Job); ok {
+			t.Errorf("Expected pod %s not to have a job", pod1ID)
+		}
+	}
+
+	// Reporter should have added the kubernetes node, joined to its host
+	{
+		nodeID := report.MakeKubernetesNodeID(nodeUID)
+		node, ok := rpt.KubernetesNode.Nodes[nodeID]
+		if !ok {
+			t.Errorf("Expected report to have kubernetes node %q, but not found", nodeID)
+		}
+		for k, want := range map[string]string{
+			kubernetes.Name:           nodeName,
+			kubernetes.State:          "NotReady,SchedulingDisabled",
+			kubernetes.KubeletVersion: "v1.9.3",
+			kubernetes.Taints:         "dedicated=pong:NoSchedule",
+		} {
+			if have, ok := node.Latest.Lookup(k); !ok || have != want {
+				t.Errorf("Expected kubernetes node %s latest %q: %q, got %q", nodeID, k, want, have)
+			}
+		}
+		if parents, ok := node.Parents.Lookup(report.Host); !ok || !parents.Contains(report.MakeHostNodeID(nodeName)) {
+			t.Errorf("Expected kubernetes node %s to have parent host %q, got %q", nodeID, nodeName, parents)
+		}
+		resources := node.ExtractMulticolumnTable(kubernetes.NodeTableTemplates[kubernetes.NodeResourcesPrefix])
+		wantResources := []report.Row{
+			{ID: "cpu", Entries: map[string]string{
+				kubernetes.NodeResourceAllocatable: "1900m",
+				kubernetes.NodeResourceCapacity:    "2",
+			}},
+			{ID: "memory", Entries: map[string]string{
+				kubernetes.NodeResourceAllocatable: "3Gi",
+				kubernetes.NodeResourceCapacity:    "4Gi",
+			}},
+		}
+		if !reflect.DeepEqual(wantResources, resources) {
+			t.Errorf("Expected kubernetes node %s resources %v, got %v", nodeID, wantResources, resources)
+		}
+	}
+
 	// Reporter should allow controls for k8s topologies by providing a probe ID
 	{
 		for _, topologyName := range []string{
@@ -254,9 +688,15 @@ func TestReporter(t *testing.T) {
 			report.CronJob,
 			report.DaemonSet,
 			report.Deployment,
+			report.Ingress,
+			report.Job,
+			report.KubernetesNode,
+			report.PersistentVolume,
+			report.PersistentVolumeClaim,
 			report.Pod,
 			report.Service,
 			report.StatefulSet,
+			report.StorageClass,
 		} {
 			topology, ok := rpt.Topology(topologyName)
 			if !ok {
@@ -273,6 +713,147 @@ func TestReporter(t *testing.T) {
 
 }
 
+func TestReporterCronJobPods(t *testing.T) {
+	oldGetNodeName := kubernetes.GetLocalPodUIDs
+	defer func() { kubernetes.GetLocalPodUIDs = oldGetNodeName }()
+	kubernetes.GetLocalPodUIDs = func(string) (map[string]struct{}, error) {
+		return map[string]struct{}{pod1UID: {}, pod2UID: {}}, nil
+	}
+
+	client := newMockClient()
+	// A new pod, as the reporter adds parents to the pods it walks
+	client.pods = []kubernetes.Pod{kubernetes.NewPod(&apiPod2)}
+	client.cronJobs = []kubernetes.CronJob{kubernetes.NewCronJob(&apibatchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pongcron",
+			UID:       types.UID(cronJobUID),
+			Namespace: "ping",
+		},
+		Status: apibatchv1beta1.CronJobStatus{
+			Active: []apiv1.ObjectReference{{UID: types.UID(jobUID)}},
+		},
+	}, map[types.UID]*apibatchv1.Job{types.UID(jobUID): &apiJob1})}
+	hr := controls.NewDefaultHandlerRegistry()
+	rpt, _ := kubernetes.NewReporter(client, nil, "probe-id", "foo", nil, hr, "", 0, false).Report()
+
+	// The pod of the job of the cron job only has the cron job as a parent
+	pod2ID := report.MakePodNodeID(pod2UID)
+	node, ok := rpt.Pod.Nodes[pod2ID]
+	if !ok {
+		t.Fatalf("Expected report to have pod %q, but not found", pod2ID)
+	}
+	if parents, ok := node.Parents.Lookup(report.CronJob); !ok || !parents.Contains(report.MakeCronJobNodeID(cronJobUID)) {
+		t.Errorf("Expected pod %s to have parent cron job, got %q", pod2ID, parents)
+	}
+	if parents, ok := node.Parents.Lookup(report.Job); ok {
+		t.Errorf("Expected pod %s not to have a parent job, got %q", pod2ID, parents)
+	}
+}
+
+func TestReporterEvents(t *testing.T) {
+	oldGetNodeName := kubernetes.GetLocalPodUIDs
+	defer func() { kubernetes.GetLocalPodUIDs = oldGetNodeName }()
+	kubernetes.GetLocalPodUIDs = func(string) (map[string]struct{}, error) {
+		return map[string]struct{}{pod1UID: {}, pod2UID: {}}, nil
+	}
+
+	var (
+		seen   = time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+		client = newMockClient()
+		hr     = controls.NewDefaultHandlerRegistry()
+	)
+	reporter := kubernetes.NewReporter(client, nil, "probe-id", "foo", nil, hr, "", 0, true)
+	event := func(uid string, kind, namespace, name string, last time.Time, reason string) kubernetes.ObjectEvent {
+		return kubernetes.NewObjectEvent(&apiv1.Event{
+			ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid), Namespace: namespace},
+			InvolvedObject: apiv1.ObjectReference{
+				Kind:      kind,
+				Namespace: namespace,
+				Name:      name,
+			},
+			Reason:        reason,
+			Message:       reason + " happened",
+			Type:          apiv1.EventTypeWarning,
+			Count:         2,
+			LastTimestamp: metav1.NewTime(last),
+		})
+	}
+	backOff := event("event1", "Pod", "ping", "pong-a", seen, "BackOff")
+	for _, watch := range client.events {
+		watch(kubernetes.ADD, backOff)
+		watch(kubernetes.ADD, event("event2", "Pod", "ping", "pong-a", seen.Add(time.Second), "Unhealthy"))
+		watch(kubernetes.ADD, event("event3", "Node", "", nodeName, seen, "NodeNotReady"))
+		watch(kubernetes.DELETE, event("event2", "Pod", "ping", "pong-a", seen.Add(time.Second), "Unhealthy"))
+	}
+	rpt, err := reporter.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := kubernetes.EventTableTemplates[kubernetes.EventsPrefix]
+	want := []report.Row{backOff.Row()}
+	if have := rpt.Pod.Nodes[report.MakePodNodeID(pod1UID)].ExtractMulticolumnTable(template); !reflect.DeepEqual(want, have) {
+		t.Errorf("Expected pod events %v, got %v", want, have)
+	}
+	if want, have := (map[string]string{
+		kubernetes.EventTime:    "2018-01-01T12:00:00Z",
+		kubernetes.EventType:    "Warning",
+		kubernetes.EventReason:  "BackOff",
+		kubernetes.EventMessage: "BackOff happened",
+		kubernetes.EventCount:   "2",
+	}), backOff.Row().Entries; !reflect.DeepEqual(want, have) {
+		t.Errorf("Expected event entries %v, got %v", want, have)
+	}
+	if have := rpt.Pod.Nodes[report.MakePodNodeID(pod2UID)].ExtractMulticolumnTable(template); len(have) != 0 {
+		t.Errorf("Expected no events for pod %s, got %v", pod2UID, have)
+	}
+	if have := rpt.KubernetesNode.Nodes[report.MakeKubernetesNodeID(nodeUID)].ExtractMulticolumnTable(template); len(have) != 1 {
+		t.Errorf("Expected one event for the node, got %v", have)
+	}
+
+	// A recurring event keeps its row
+	for _, watch := range client.events {
+		watch(kubernetes.UPDATE, event("event1", "Pod", "ping", "pong-a", seen.Add(time.Minute), "BackOff"))
+	}
+	rpt, err = reporter.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := rpt.Pod.Nodes[report.MakePodNodeID(pod1UID)].ExtractMulticolumnTable(template); len(have) != 1 || have[0].ID != "event1" {
+		t.Errorf("Expected the updated pod event, got %v", have)
+	}
+
+	// Events are reported for the pods of other nodes too
+	remote := kubernetes.NewReporter(client, nil, "probe-id", "foo", nil, hr, "othernode", 0, true)
+	for _, watch := range client.events[1:] {
+		watch(kubernetes.ADD, backOff)
+	}
+	rpt, err = remote.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	remotePod := rpt.Pod.Nodes[report.MakePodNodeID(pod1UID)]
+	if have := remotePod.ExtractMulticolumnTable(template); len(have) != 1 {
+		t.Errorf("Expected the event of the remote pod, got %v", have)
+	}
+	if probeID, ok := remotePod.Latest.Lookup(report.ControlProbeID); ok {
+		t.Errorf("Expected the remote pod not to be controlled, got probe %q", probeID)
+	}
+	if services, ok := remotePod.Parents.Lookup(report.Service); !ok || !services.Contains(report.MakeServiceNodeID(serviceUID)) {
+		t.Errorf("Expected the remote pod to keep its service parent, got %v", remotePod.Parents)
+	}
+	if _, ok := rpt.Pod.Nodes[report.MakePodNodeID(pod2UID)]; ok {
+		t.Errorf("Expected no remote pod without events")
+	}
+
+	// Events are only watched when reported
+	client = newMockClient()
+	kubernetes.NewReporter(client, nil, "probe-id", "foo", nil, hr, "", 0, false)
+	if len(client.events) != 0 {
+		t.Errorf("Expected events not to be watched")
+	}
+}
+
 func TestTagger(t *testing.T) {
 	rpt := report.MakeReport()
 	rpt.Container.AddNode(report.MakeNodeWith("container1", map[string]string{
@@ -280,7 +861,7 @@ func TestTagger(t *testing.T) {
 	}))
 
 	hr := controls.NewDefaultHandlerRegistry()
-	rpt, err := kubernetes.NewReporter(newMockClient(), nil, "", "", nil, hr, "", 0).Tag(rpt)
+	rpt, err := kubernetes.NewReporter(newMockClient(), nil, "", "", nil, hr, "", 0, false).Tag(rpt)
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -309,7 +890,7 @@ func TestReporterGetLogs(t *testing.T) {
 	client := newMockClient()
 	pipes := mockPipeClient{}
 	hr := controls.NewDefaultHandlerRegistry()
-	reporter := kubernetes.NewReporter(client, pipes, "", "", nil, hr, "", 0)
+	reporter := kubernetes.NewReporter(client, pipes, "", "", nil, hr, "", 0, false)
 
 	// Should error on invalid IDs
 	{
@@ -377,3 +958,105 @@ func TestReporterGetLogs(t *testing.T) {
 		t.Errorf("Expected pipe to close the underlying log stream")
 	}
 }
+
+func TestReporterDescribe(t *testing.T) {
+	client := newMockClient()
+	pipes := mockPipeClient{}
+	hr := controls.NewDefaultHandlerRegistry()
+	reporter := kubernetes.NewReporter(client, pipes, "", "", nil, hr, "", 0, false)
+
+	// Should error on IDs of objects which can't be described
+	{
+		resp := reporter.CaptureResource(reporter.Describe)(xfer.Request{
+			NodeID:  report.MakeServiceNodeID(serviceUID),
+			Control: kubernetes.Describe,
+		})
+		if want := "Invalid ID: " + report.MakeServiceNodeID(serviceUID); resp.Error != want {
+			t.Errorf("Expected error on invalid ID: %q, got %q", want, resp.Error)
+		}
+	}
+
+	// Should push the description of the object into the pipe
+	resp := reporter.CaptureResource(reporter.Describe)(xfer.Request{
+		AppID:   "appID",
+		NodeID:  report.MakePodNodeID(pod1UID),
+		Control: kubernetes.Describe,
+	})
+	pipe, ok := pipes[resp.Pipe]
+	if !ok {
+		t.Fatalf("Expected pipe %q to have been created, but got %#v", resp.Pipe, resp)
+	}
+	_, readWriter := pipe.Ends()
+	contents, err := ioutil.ReadAll(readWriter)
+	if err != nil {
+		t.Error(err)
+	}
+	if want := "resource: pods\nnamespace: ping\nname: pong-a\n"; string(contents) != want {
+		t.Errorf("Expected pipe to contain %q, but got %q", want, string(contents))
+	}
+}
+
+func TestReporterExecContainer(t *testing.T) {
+	client := newMockClient()
+	// Execs close their pipes when they finish, so each gets its own pipe client
+	execContainer := func(req xfer.Request) xfer.Response {
+		hr := controls.NewDefaultHandlerRegistry()
+		reporter := kubernetes.NewReporter(client, mockPipeClient{}, "", "", nil, hr, "", 0, false)
+		return reporter.CapturePod(reporter.ExecContainer)(req)
+	}
+
+	// Should error on containers not in the pod
+	resp := execContainer(xfer.Request{
+		AppID:       "appID",
+		NodeID:      report.MakePodNodeID(pod1UID),
+		Control:     kubernetes.ExecContainer,
+		ControlArgs: map[string]string{"container": "notfound"},
+	})
+	if want := "Container not found: notfound"; resp.Error != want {
+		t.Errorf("Expected error on unknown container: %q, got %q", want, resp.Error)
+	}
+
+	for _, tc := range []struct {
+		args map[string]string
+		want string
+	}{
+		{nil, "ping;pong-a;pong"},
+		{map[string]string{"container": "sidecar"}, "ping;pong-a;sidecar"},
+	} {
+		resp := execContainer(xfer.Request{
+			AppID:       "appID",
+			NodeID:      report.MakePodNodeID(pod1UID),
+			Control:     kubernetes.ExecContainer,
+			ControlArgs: tc.args,
+		})
+		if resp.Pipe == "" || !resp.RawTTY || resp.ResizeTTYControl != kubernetes.ResizeExecTTY {
+			t.Errorf("Expected a raw TTY pipe, but got %#v", resp)
+		}
+		select {
+		case have := <-client.execs:
+			if have != tc.want {
+				t.Errorf("Expected exec in %q, got %q", tc.want, have)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected exec in %q", tc.want)
+		}
+	}
+}
+
+func TestReporterRestartRollout(t *testing.T) {
+	client := newMockClient()
+	hr := controls.NewDefaultHandlerRegistry()
+	reporter := kubernetes.NewReporter(client, nil, "", "", nil, hr, "", 0, false)
+
+	resp := hr.HandleControlRequest(xfer.Request{
+		NodeID:  report.MakeDeploymentNodeID("notfound"),
+		Control: kubernetes.RestartRollout,
+	})
+	if want := "Deployment not found: notfound"; resp.Error != want {
+		t.Errorf("Expected error on unknown deployment: %q, got %q", want, resp.Error)
+	}
+	if len(client.rollouts) != 0 {
+		t.Errorf("Expected no rollouts, got %v", client.rollouts)
+	}
+	reporter.Stop()
+}
@@ -0,0 +1,39 @@
+package kubernetes
+
+import (
+	"github.com/weaveworks/scope/report"
+
+	apistoragev1 "k8s.io/api/storage/v1"
+)
+
+// These constants are keys used in node metadata
+const (
+	Provisioner = report.KubernetesProvisioner
+)
+
+// StorageClass represents a Kubernetes storage class
+type StorageClass interface {
+	Meta
+	GetNode(probeID string) report.Node
+}
+
+type storageClass struct {
+	*apistoragev1.StorageClass
+	Meta
+}
+
+// NewStorageClass creates a new StorageClass
+func NewStorageClass(s *apistoragev1.StorageClass) StorageClass {
+	return &storageClass{StorageClass: s, Meta: meta{s.ObjectMeta}}
+}
+
+func (s *storageClass) GetNode(probeID string) report.Node {
+	latest := map[string]string{
+		Provisioner:           s.Provisioner,
+		report.ControlProbeID: probeID,
+	}
+	if s.ReclaimPolicy != nil {
+		latest[ReclaimPolicy] = string(*s.ReclaimPolicy)
+	}
+	return s.MetaNode(report.MakeStorageClassNodeID(s.UID())).WithLatests(latest)
+}
@@ -1,8 +1,10 @@
 package process
 
 import (
+	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/weaveworks/common/mtime"
 	"github.com/weaveworks/scope/report"
@@ -18,6 +20,17 @@ const (
 	CPUUsage       = "process_cpu_usage_percent"
 	MemoryUsage    = "process_memory_usage_bytes"
 	OpenFilesCount = "open_files_count"
+
+	ReadBytesRate       = "process_read_bytes_per_second"
+	WriteBytesRate      = "process_write_bytes_per_second"
+	ReadSyscallsRate    = "process_read_syscalls_per_second"
+	WriteSyscallsRate   = "process_write_syscalls_per_second"
+	ContextSwitchesRate = "process_context_switches_per_second"
+
+	ListeningSocketsTablePrefix = "listening_sockets_table_"
+	ListeningSocketProtocol     = "listening_socket_protocol"
+	ListeningSocketAddress      = "listening_socket_address"
+	ListeningSocketPort         = "listening_socket_port"
 )
 
 // Exposed for testing
@@ -33,27 +46,85 @@ var (
 		CPUUsage:       {ID: CPUUsage, Label: "CPU", Format: report.PercentFormat, Priority: 1},
 		MemoryUsage:    {ID: MemoryUsage, Label: "Memory", Format: report.FilesizeFormat, Priority: 2},
 		OpenFilesCount: {ID: OpenFilesCount, Label: "Open files", Format: report.IntegerFormat, Priority: 3},
+	}.Merge(IOMetricTemplates)
+
+	// IOMetricTemplates are the templates of the I/O metrics of
+	// processes, which add up across processes.
+	IOMetricTemplates = report.MetricTemplates{
+		ReadBytesRate:       {ID: ReadBytesRate, Label: "Disk read/s", Format: report.FilesizeFormat, Priority: 4},
+		WriteBytesRate:      {ID: WriteBytesRate, Label: "Disk write/s", Format: report.FilesizeFormat, Priority: 5},
+		ReadSyscallsRate:    {ID: ReadSyscallsRate, Label: "Read syscalls/s", Format: report.IntegerFormat, Priority: 6},
+		WriteSyscallsRate:   {ID: WriteSyscallsRate, Label: "Write syscalls/s", Format: report.IntegerFormat, Priority: 7},
+		ContextSwitchesRate: {ID: ContextSwitchesRate, Label: "Context switches/s", Format: report.IntegerFormat, Priority: 8},
+	}
+
+	TableTemplates = report.TableTemplates{
+		ListeningSocketsTablePrefix: {
+			ID:     ListeningSocketsTablePrefix,
+			Label:  "Listening sockets",
+			Type:   report.MulticolumnTableType,
+			Prefix: ListeningSocketsTablePrefix,
+			Columns: []report.Column{
+				{
+					ID:    ListeningSocketProtocol,
+					Label: "Protocol",
+				},
+				{
+					ID:    ListeningSocketAddress,
+					Label: "Address",
+				},
+				{
+					ID:       ListeningSocketPort,
+					Label:    "Port",
+					DataType: report.Number,
+				},
+			},
+		},
 	}
 )
 
+// ListeningSocketRow makes the row of the listening sockets table for
+// a socket listening on addr:port over transport.
+func ListeningSocketRow(transport, addr string, port uint16) report.Row {
+	portStr := strconv.Itoa(int(port))
+	return report.Row{
+		ID: net.JoinHostPort(addr, portStr) + "/" + transport,
+		Entries: map[string]string{
+			ListeningSocketProtocol: transport,
+			ListeningSocketAddress:  addr,
+			ListeningSocketPort:     portStr,
+		},
+	}
+}
+
 // Reporter generates Reports containing the Process topology.
 type Reporter struct {
 	scope                  string
 	walker                 Walker
 	jiffies                Jiffies
 	noCommandLineArguments bool
+	reportingIO            bool
+
+	// The I/O counters of the processes at the previous report, to
+	// turn them into rates; processes whose counters couldn't be read
+	// are left out, so that rates are only computed between two reads
+	// in a row
+	previous     map[int]Process
+	previousTime time.Time
 }
 
 // Jiffies is the type for the function used to fetch the elapsed jiffies.
 type Jiffies func() (uint64, float64, error)
 
-// NewReporter makes a new Reporter.
-func NewReporter(walker Walker, scope string, jiffies Jiffies, noCommandLineArguments bool) *Reporter {
+// NewReporter makes a new Reporter. I/O metrics are only reported when
+// reportingIO is set, as the walker must gather the counters for them.
+func NewReporter(walker Walker, scope string, jiffies Jiffies, noCommandLineArguments, reportingIO bool) *Reporter {
 	return &Reporter{
 		scope:                  scope,
 		walker:                 walker,
 		jiffies:                jiffies,
 		noCommandLineArguments: noCommandLineArguments,
+		reportingIO:            reportingIO,
 	}
 }
 
@@ -74,12 +145,20 @@ func (r *Reporter) Report() (report.Report, error) {
 func (r *Reporter) processTopology() (report.Topology, error) {
 	t := report.MakeTopology().
 		WithMetadataTemplates(MetadataTemplates).
-		WithMetricTemplates(MetricTemplates)
+		WithMetricTemplates(MetricTemplates).
+		WithTableTemplates(TableTemplates)
 	now := mtime.Now()
 	deltaTotal, maxCPU, err := r.jiffies()
 	if err != nil {
 		return t, err
 	}
+	var (
+		current  = map[int]Process{}
+		interval = now.Sub(r.previousTime).Seconds()
+	)
+	defer func() {
+		r.previous, r.previousTime = current, now
+	}()
 
 	err = r.walker.Walk(func(p, prev Process) {
 		pidstr := strconv.Itoa(p.PID)
@@ -111,6 +190,23 @@ func (r *Reporter) processTopology() (report.Topology, error) {
 			cpuUsage := float64(p.Jiffies-prev.Jiffies) / float64(deltaTotal) * 100.
 			metrics[CPUUsage] = report.MakeSingletonMetric(now, cpuUsage).WithMax(maxCPU)
 		}
+		if previous, ok := r.previous[p.PID]; ok && p.HasIO && r.reportingIO && interval > 0 {
+			for id, counters := range map[string][2]uint64{
+				ReadBytesRate:       {previous.ReadBytes, p.ReadBytes},
+				WriteBytesRate:      {previous.WriteBytes, p.WriteBytes},
+				ReadSyscallsRate:    {previous.ReadSyscalls, p.ReadSyscalls},
+				WriteSyscallsRate:   {previous.WriteSyscalls, p.WriteSyscalls},
+				ContextSwitchesRate: {previous.ContextSwitches, p.ContextSwitches},
+			} {
+				// Counters going backwards were not read, or the pid was reused
+				if counters[1] >= counters[0] {
+					metrics[id] = report.MakeSingletonMetric(now, float64(counters[1]-counters[0])/interval)
+				}
+			}
+		}
+		if p.HasIO {
+			current[p.PID] = p
+		}
 
 		node = node.WithMetrics(metrics)
 
@@ -36,7 +36,7 @@ func testReporter(t *testing.T, noCommandLineArguments bool, test func(report.Re
 	mtime.NowForce(now)
 	defer mtime.NowReset()
 
-	rpt, err := process.NewReporter(walker, "", getDeltaTotalJiffies, noCommandLineArguments).Report()
+	rpt, err := process.NewReporter(walker, "", getDeltaTotalJiffies, noCommandLineArguments, false).Report()
 	if err != nil {
 		t.Error(err)
 	}
@@ -135,10 +135,63 @@ func TestCmdlineRemoval(t *testing.T) {
 	testReporter(t, true, test)
 }
 
+func TestIORates(t *testing.T) {
+	walker := &mockWalker{processes: []process.Process{{PID: 1, ReadBytes: 1000, WriteSyscalls: 10, ContextSwitches: 100, HasIO: true}}}
+	reporter := process.NewReporter(walker, "", func() (uint64, float64, error) { return 0, 0., nil }, false, true)
+	now := time.Now()
+	mtime.NowForce(now)
+	defer mtime.NowReset()
+	if _, err := reporter.Report(); err != nil {
+		t.Fatal(err)
+	}
+
+	walker.processes = []process.Process{{PID: 1, ReadBytes: 5000, WriteSyscalls: 30, ContextSwitches: 50, HasIO: true}}
+	mtime.NowForce(now.Add(2 * time.Second))
+	rpt, err := reporter.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := rpt.Process.Nodes[report.MakeProcessNodeID("", "1")]
+	for id, want := range map[string]float64{
+		process.ReadBytesRate:     2000,
+		process.WriteBytesRate:    0,
+		process.WriteSyscallsRate: 10,
+	} {
+		if sample, ok := node.Metrics[id].LastSample(); !ok || sample.Value != want {
+			t.Errorf("%s: expected %f, got %v", id, want, sample.Value)
+		}
+	}
+	// Counters going backwards are ignored
+	if _, ok := node.Metrics[process.ContextSwitchesRate]; ok {
+		t.Errorf("Expected no context switches rate")
+	}
+
+	// A failed read drops the counters: no rate is computed until
+	// they are read twice in a row again
+	for i, p := range []process.Process{
+		{PID: 1},
+		{PID: 1, ReadBytes: 9000, HasIO: true},
+		{PID: 1, ReadBytes: 13000, HasIO: true},
+	} {
+		walker.processes = []process.Process{p}
+		mtime.NowForce(now.Add(time.Duration(4+2*i) * time.Second))
+		rpt, err := reporter.Report()
+		if err != nil {
+			t.Fatal(err)
+		}
+		metric, ok := rpt.Process.Nodes[report.MakeProcessNodeID("", "1")].Metrics[process.ReadBytesRate]
+		if i < 2 && ok {
+			t.Errorf("%d: expected no read rate, got %v", i, metric)
+		} else if sample, _ := metric.LastSample(); i == 2 && (!ok || sample.Value != 2000) {
+			t.Errorf("%d: expected a read rate of 2000, got %v", i, metric)
+		}
+	}
+}
+
 func BenchmarkReporter(t *testing.B) {
 	walker := &mockWalker{processes: processes}
 	getDeltaTotalJiffies := func() (uint64, float64, error) { return 0, 0., nil }
-	reporter := process.NewReporter(walker, "", getDeltaTotalJiffies, false)
+	reporter := process.NewReporter(walker, "", getDeltaTotalJiffies, false, false)
 	t.ResetTimer()
 
 	for i := 0; i < t.N; i++ {
@@ -148,3 +201,25 @@ func BenchmarkReporter(t *testing.B) {
 		}
 	}
 }
+
+func TestUnixSocketReporter(t *testing.T) {
+	walker := &mockWalker{processes: processes}
+	getUnixConnections := func(procRoot string, pids []int) ([]process.UnixConnection, error) {
+		if len(pids) != len(processes) {
+			t.Errorf("Expected %d pids, got %v", len(processes), pids)
+		}
+		return []process.UnixConnection{{Client: 4, Server: 3}}, nil
+	}
+
+	rpt, err := process.NewUnixSocketReporter(walker, "", "/proc", getUnixConnections).Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, ok := rpt.Process.Nodes[report.MakeProcessNodeID("", "4")]
+	if !ok {
+		t.Fatalf("Expected report to include the pid 4 ping")
+	}
+	if want := report.MakeProcessNodeID("", "3"); len(node.Adjacency) != 1 || !node.Adjacency.Contains(want) {
+		t.Errorf("Expected adjacency to %s, got %v", want, node.Adjacency)
+	}
+}
@@ -0,0 +1,64 @@
+package process
+
+import (
+	"strconv"
+
+	"github.com/weaveworks/scope/report"
+)
+
+// A UnixConnection is a connection between two processes over a Unix
+// domain socket. The Server is the process holding the end bound to a
+// path.
+type UnixConnection struct {
+	Client, Server int
+}
+
+// UnixConnections is the type for the function used to fetch the Unix
+// socket connections between the processes pids, looking in procRoot.
+type UnixConnections func(procRoot string, pids []int) ([]UnixConnection, error)
+
+// UnixSocketReporter generates Reports containing the connections
+// over Unix domain sockets between processes, as adjacencies in the
+// Process topology.
+type UnixSocketReporter struct {
+	scope       string
+	procRoot    string
+	walker      Walker
+	connections UnixConnections
+}
+
+// NewUnixSocketReporter makes a new UnixSocketReporter.
+func NewUnixSocketReporter(walker Walker, scope, procRoot string, connections UnixConnections) *UnixSocketReporter {
+	return &UnixSocketReporter{
+		scope:       scope,
+		procRoot:    procRoot,
+		walker:      walker,
+		connections: connections,
+	}
+}
+
+// Name of this reporter, for metrics gathering
+func (UnixSocketReporter) Name() string { return "UnixSocket" }
+
+// Report implements Reporter.
+func (r *UnixSocketReporter) Report() (report.Report, error) {
+	result := report.MakeReport()
+	pids := []int{}
+	if err := r.walker.Walk(func(p, _ Process) {
+		pids = append(pids, p.PID)
+	}); err != nil {
+		return result, err
+	}
+	conns, err := r.connections(r.procRoot, pids)
+	if err != nil {
+		return result, err
+	}
+	for _, c := range conns {
+		var (
+			clientID = report.MakeProcessNodeID(r.scope, strconv.Itoa(c.Client))
+			serverID = report.MakeProcessNodeID(r.scope, strconv.Itoa(c.Server))
+		)
+		result.Process.AddNode(report.MakeNode(clientID).WithAdjacent(serverID))
+	}
+	return result, nil
+}
@@ -0,0 +1,388 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/weaveworks/common/fs"
+)
+
+// From linux/sock_diag.h and linux/unix_diag.h
+const (
+	sockDiagByFamily = 20
+	udiagShowPeer    = 0x4
+	unixDiagPeer     = 2
+
+	unixDiagReqLen = 24
+	unixDiagMsgLen = 16
+
+	// /proc/net/unix socket state of connected sockets
+	unixConnected = 3
+)
+
+var nativeEndian = func() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// NewUnixConnections returns a UnixConnections finding the connections
+// over Unix domain sockets between pids, in all of their network
+// namespaces. The server end of a connection is the one bound to a path
+// in /proc/<pid>/net/unix, and its peer, which may live in any network
+// namespace, is found through sock_diag from within the namespace of
+// the server. Which processes hold which sockets is remembered between
+// calls.
+func NewUnixConnections() UnixConnections {
+	owners := &socketOwners{}
+	return func(procRoot string, pids []int) ([]UnixConnection, error) {
+		paths, peers, err := unixSockets(procRoot, pids)
+		if err != nil {
+			return nil, err
+		}
+		return unixConnections(paths, peers, owners.lookup(procRoot, pids, paths)), nil
+	}
+}
+
+// unixSockets returns the paths and peers of the connected Unix
+// sockets in the network namespaces of pids, by inode. Failing to read
+// the namespace of the probe is an error; other namespaces, whose
+// processes may be gone by now, are skipped.
+func unixSockets(procRoot string, pids []int) (map[uint64]string, map[uint64]uint64, error) {
+	var (
+		paths = map[uint64]string{}
+		peers = map[uint64]uint64{}
+		seen  = map[uint64]struct{}{}
+		statT syscall.Stat_t
+	)
+	if err := fs.Stat(filepath.Join(procRoot, "self", "ns", "net"), &statT); err != nil {
+		return nil, nil, err
+	}
+	seen[statT.Ino] = struct{}{}
+	if err := addUnixSockets(paths, peers, filepath.Join(procRoot, "self"), -1); err != nil {
+		return nil, nil, err
+	}
+
+	for _, pid := range pids {
+		pidDir := filepath.Join(procRoot, strconv.Itoa(pid))
+		if err := fs.Stat(filepath.Join(pidDir, "ns", "net"), &statT); err != nil {
+			continue
+		}
+		if _, ok := seen[statT.Ino]; ok {
+			continue
+		}
+		seen[statT.Ino] = struct{}{}
+		fd, err := sockDiagSocketIn(filepath.Join(pidDir, "ns", "net"))
+		if err != nil {
+			continue
+		}
+		addUnixSockets(paths, peers, pidDir, fd)
+	}
+	return paths, peers, nil
+}
+
+// addUnixSockets adds the connected Unix sockets of the network
+// namespace of the process in pidDir to paths and peers. fd is a
+// sock_diag socket in that namespace, which is closed, or -1 for one
+// in the namespace of the probe.
+func addUnixSockets(paths map[uint64]string, peers map[uint64]uint64, pidDir string, fd int) error {
+	var err error
+	if fd == -1 {
+		if fd, err = sockDiagSocket(); err != nil {
+			return err
+		}
+	}
+	defer syscall.Close(fd)
+
+	buf, err := fs.ReadFile(filepath.Join(pidDir, "net", "unix"))
+	if err != nil {
+		return err
+	}
+	nsPaths, err := parseUnixSockets(buf)
+	if err != nil {
+		return err
+	}
+	nsPeers, err := unixSocketPeers(fd)
+	if err != nil {
+		return err
+	}
+	for inode, path := range nsPaths {
+		paths[inode] = path
+	}
+	for inode, peer := range nsPeers {
+		peers[inode] = peer
+	}
+	return nil
+}
+
+// parseUnixSockets parses /proc/net/unix, returning the paths of
+// connected sockets by inode. Unnamed sockets have an empty path.
+//
+//	Num       RefCount Protocol Flags    Type St Inode Path
+//	0000000000000000: 00000003 00000000 00000000 0001 03 17935 /run/docker.sock
+func parseUnixSockets(buf []byte) (map[uint64]string, error) {
+	paths := map[uint64]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	scanner.Scan() // Skip the header
+	for scanner.Scan() {
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("invalid line in /proc/net/unix: %q", scanner.Text())
+		}
+		state, err := strconv.ParseUint(string(fields[5]), 16, 8)
+		if err != nil {
+			return nil, err
+		}
+		if state != unixConnected {
+			continue
+		}
+		inode, err := strconv.ParseUint(string(fields[6]), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		var path string
+		if len(fields) > 7 {
+			path = string(fields[7])
+		}
+		paths[inode] = path
+	}
+	return paths, scanner.Err()
+}
+
+// sockDiagSocket opens a sock_diag socket, which reports on the
+// sockets of the network namespace it is opened in.
+func sockDiagSocket() (int, error) {
+	// NETLINK_INET_DIAG was renamed NETLINK_SOCK_DIAG, as it is not just for inet sockets
+	return syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_INET_DIAG)
+}
+
+// sockDiagSocketIn opens a sock_diag socket in the network namespace
+// at nsPath. Only opening it needs to happen from within the namespace,
+// so it is done on a thread of its own, which is thrown away if it can't
+// be moved back: from Go 1.10, a thread still locked when its goroutine
+// exits is terminated rather than returned to the scheduler.
+func sockDiagSocketIn(nsPath string) (int, error) {
+	type result struct {
+		fd  int
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		runtime.LockOSThread()
+		fd, err := func() (int, error) {
+			own, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", syscall.Gettid()))
+			if err != nil {
+				return -1, err
+			}
+			defer own.Close()
+			ns, err := os.Open(nsPath)
+			if err != nil {
+				return -1, err
+			}
+			defer ns.Close()
+			if err := unix.Setns(int(ns.Fd()), unix.CLONE_NEWNET); err != nil {
+				return -1, err
+			}
+			fd, err := sockDiagSocket()
+			if err := unix.Setns(int(own.Fd()), unix.CLONE_NEWNET); err != nil {
+				// Leave the thread locked, for it to exit with the goroutine
+				// instead of running others in the wrong namespace
+				return fd, err
+			}
+			runtime.UnlockOSThread()
+			return fd, err
+		}()
+		done <- result{fd, err}
+	}()
+	r := <-done
+	if r.err != nil && r.fd >= 0 {
+		syscall.Close(r.fd)
+	}
+	return r.fd, r.err
+}
+
+// unixSocketPeers returns the inodes of the peers of Unix sockets, by
+// inode, as reported by the sock_diag socket fd.
+func unixSocketPeers(fd int) (map[uint64]uint64, error) {
+	req := make([]byte, syscall.NLMSG_HDRLEN+unixDiagReqLen)
+	nativeEndian.PutUint32(req[0:4], uint32(len(req)))
+	nativeEndian.PutUint16(req[4:6], sockDiagByFamily)
+	nativeEndian.PutUint16(req[6:8], syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP)
+	// struct unix_diag_req: family, protocol, pad, states, ino, show, cookie
+	req[syscall.NLMSG_HDRLEN] = syscall.AF_UNIX
+	nativeEndian.PutUint32(req[syscall.NLMSG_HDRLEN+4:], ^uint32(0))
+	nativeEndian.PutUint32(req[syscall.NLMSG_HDRLEN+12:], udiagShowPeer)
+	if err := syscall.Sendto(fd, req, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, err
+	}
+
+	peers := map[uint64]uint64{}
+	buf := make([]byte, 8*os.Getpagesize())
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return peers, nil
+			case syscall.NLMSG_ERROR:
+				if len(m.Data) >= 4 {
+					if errno := int32(nativeEndian.Uint32(m.Data[0:4])); errno != 0 {
+						return nil, syscall.Errno(-errno)
+					}
+				}
+				return nil, fmt.Errorf("sock_diag error")
+			}
+			if inode, peer, ok := parseUnixDiagMsg(m.Data); ok {
+				peers[inode] = peer
+			}
+		}
+	}
+}
+
+// parseUnixDiagMsg parses a struct unix_diag_msg followed by its
+// attributes, returning the inode of the socket and of its peer.
+func parseUnixDiagMsg(data []byte) (inode, peer uint64, ok bool) {
+	if len(data) < unixDiagMsgLen {
+		return 0, 0, false
+	}
+	inode = uint64(nativeEndian.Uint32(data[4:8]))
+	for attrs := data[unixDiagMsgLen:]; len(attrs) >= syscall.SizeofRtAttr; {
+		length := int(nativeEndian.Uint16(attrs[0:2]))
+		if length < syscall.SizeofRtAttr || length > len(attrs) {
+			break
+		}
+		if nativeEndian.Uint16(attrs[2:4]) == unixDiagPeer && length >= syscall.SizeofRtAttr+4 {
+			return inode, uint64(nativeEndian.Uint32(attrs[4:8])), true
+		}
+		// Attributes are aligned to 4 bytes
+		aligned := (length + syscall.RTA_ALIGNTO - 1) &^ (syscall.RTA_ALIGNTO - 1)
+		if aligned > len(attrs) {
+			break
+		}
+		attrs = attrs[aligned:]
+	}
+	return inode, 0, false
+}
+
+// socketOwners remembers the socket inodes of the file descriptors of
+// processes, so that only new file descriptors need to be stat'ed.
+type socketOwners struct {
+	fds     map[int]map[string]uint64 // by pid, then fd; 0 for other files
+	orphans map[uint64]struct{}       // sockets no process was found holding
+}
+
+// lookup returns the processes, among pids, holding each socket, by
+// inode. A file descriptor may have been closed and reused for a socket
+// since it was stat'ed, so if any of sockets has no known holder, all
+// file descriptors are stat'ed again; once per such socket, as some
+// are held by no process in pids.
+func (o *socketOwners) lookup(procRoot string, pids []int, sockets map[uint64]string) map[uint64]int {
+	owners := o.scan(procRoot, pids, false)
+	for inode := range sockets {
+		_, owned := owners[inode]
+		_, orphan := o.orphans[inode]
+		if !owned && !orphan {
+			owners = o.scan(procRoot, pids, true)
+			break
+		}
+	}
+	o.orphans = map[uint64]struct{}{}
+	for inode := range sockets {
+		if _, ok := owners[inode]; !ok {
+			o.orphans[inode] = struct{}{}
+		}
+	}
+	return owners
+}
+
+// scan stats the file descriptors of pids which it doesn't know about,
+// or all of them, and returns the processes holding each socket.
+func (o *socketOwners) scan(procRoot string, pids []int, all bool) map[uint64]int {
+	var (
+		owners = map[uint64]int{}
+		fds    = map[int]map[string]uint64{}
+		statT  syscall.Stat_t
+	)
+	for _, pid := range pids {
+		fdBase := filepath.Join(procRoot, strconv.Itoa(pid), "fd")
+		names, err := fs.ReadDirNames(fdBase)
+		if err != nil {
+			// Process is gone by now, or we don't have access.
+			continue
+		}
+		known := o.fds[pid]
+		inodes := make(map[string]uint64, len(names))
+		for _, name := range names {
+			inode, ok := known[name]
+			if all || !ok {
+				if err := fs.Stat(filepath.Join(fdBase, name), &statT); err != nil {
+					continue
+				}
+				inode = 0
+				if statT.Mode&syscall.S_IFMT == syscall.S_IFSOCK {
+					inode = statT.Ino
+				}
+			}
+			inodes[name] = inode
+			if inode != 0 {
+				owners[inode] = pid
+			}
+		}
+		fds[pid] = inodes
+	}
+	o.fds = fds
+	return owners
+}
+
+// unixConnections pairs connected sockets bound to a path with their
+// unnamed peers, and returns the connections between the processes
+// owning them. Connections within a process are ignored.
+func unixConnections(paths map[uint64]string, peers map[uint64]uint64, owners map[uint64]int) []UnixConnection {
+	seen := map[UnixConnection]struct{}{}
+	for inode, path := range paths {
+		peer, ok := peers[inode]
+		if path == "" || !ok || paths[peer] != "" {
+			continue
+		}
+		server, ok := owners[inode]
+		if !ok {
+			continue
+		}
+		client, ok := owners[peer]
+		if !ok || client == server {
+			continue
+		}
+		seen[UnixConnection{Client: client, Server: server}] = struct{}{}
+	}
+	result := make([]UnixConnection, 0, len(seen))
+	for c := range seen {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Client != result[j].Client {
+			return result[i].Client < result[j].Client
+		}
+		return result[i].Server < result[j].Server
+	})
+	return result
+}
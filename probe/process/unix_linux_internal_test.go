@@ -0,0 +1,140 @@
+package process
+
+import (
+	"reflect"
+	"syscall"
+	"testing"
+
+	fs_hook "github.com/weaveworks/common/fs"
+	"github.com/weaveworks/common/test/fs"
+)
+
+const procNetUnix = `Num       RefCount Protocol Flags    Type St Inode Path
+0000000000000000: 00000002 00000000 00010000 0001 01 17934 /run/docker.sock
+0000000000000000: 00000003 00000000 00000000 0001 03 17935 /run/docker.sock
+0000000000000000: 00000003 00000000 00000000 0001 03 17936
+0000000000000000: 00000003 00000000 00000000 0001 03 17937 @/containerd-shim/moby.sock
+0000000000000000: 00000003 00000000 00000000 0001 03 17938
+0000000000000000: 00000003 00000000 00000000 0001 03 17939
+0000000000000000: 00000003 00000000 00000000 0001 03 17940
+`
+
+func TestParseUnixSockets(t *testing.T) {
+	have, err := parseUnixSockets([]byte(procNetUnix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint64]string{
+		17935: "/run/docker.sock",
+		17936: "",
+		17937: "@/containerd-shim/moby.sock",
+		17938: "",
+		17939: "",
+		17940: "",
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want %v, have %v", want, have)
+	}
+
+	if _, err := parseUnixSockets([]byte("header\ngarbage\n")); err == nil {
+		t.Error("Expected an error for an invalid line")
+	}
+}
+
+func TestParseUnixDiagMsg(t *testing.T) {
+	msg := make([]byte, unixDiagMsgLen+8+12)
+	nativeEndian.PutUint32(msg[4:8], 17935)
+	// an unrelated attribute, then the peer
+	nativeEndian.PutUint16(msg[unixDiagMsgLen:], 5)
+	nativeEndian.PutUint16(msg[unixDiagMsgLen+2:], 1)
+	nativeEndian.PutUint16(msg[unixDiagMsgLen+8:], 8)
+	nativeEndian.PutUint16(msg[unixDiagMsgLen+10:], unixDiagPeer)
+	nativeEndian.PutUint32(msg[unixDiagMsgLen+12:], 17936)
+
+	inode, peer, ok := parseUnixDiagMsg(msg)
+	if !ok || inode != 17935 || peer != 17936 {
+		t.Errorf("want 17935, 17936, have %d, %d, %v", inode, peer, ok)
+	}
+	if _, _, ok := parseUnixDiagMsg(msg[:unixDiagMsgLen]); ok {
+		t.Error("Expected no peer")
+	}
+}
+
+func TestUnixConnections(t *testing.T) {
+	paths, err := parseUnixSockets([]byte(procNetUnix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	peers := map[uint64]uint64{
+		17935: 17936, 17936: 17935, // docker client -> dockerd
+		17937: 42,                  // containerd -> shim, in another network namespace
+		17938: 17939, 17939: 17938, // socketpair, unnamed
+		17940: 17941, // unknown owner
+	}
+	owners := map[uint64]int{
+		17935: 1, 17936: 2,
+		17937: 3, 42: 4,
+		17938: 5, 17939: 6,
+		17940: 7,
+	}
+	want := []UnixConnection{
+		{Client: 2, Server: 1},
+		{Client: 4, Server: 3},
+	}
+	if have := unixConnections(paths, peers, owners); !reflect.DeepEqual(want, have) {
+		t.Errorf("want %v, have %v", want, have)
+	}
+}
+
+// statCountingFS counts the calls to Stat.
+type statCountingFS struct {
+	fs_hook.Interface
+	stats int
+}
+
+func (f *statCountingFS) Stat(path string, stat *syscall.Stat_t) error {
+	f.stats++
+	return f.Interface.Stat(path, stat)
+}
+
+func TestSocketOwners(t *testing.T) {
+	mockFS := &statCountingFS{Interface: fs.Dir("",
+		fs.Dir("proc",
+			fs.Dir("1", fs.Dir("fd",
+				fs.File{FName: "3", FStat: syscall.Stat_t{Ino: 10, Mode: syscall.S_IFSOCK}},
+			)),
+			fs.Dir("2", fs.Dir("fd",
+				fs.File{FName: "4", FStat: syscall.Stat_t{Ino: 11, Mode: syscall.S_IFSOCK}},
+				fs.File{FName: "5", FStat: syscall.Stat_t{Ino: 12, Mode: syscall.S_IFREG}},
+			)),
+		),
+	)}
+	fs_hook.Mock(mockFS)
+	defer fs_hook.Restore()
+
+	var (
+		o       = &socketOwners{}
+		pids    = []int{1, 2}
+		sockets = map[uint64]string{10: "/run/docker.sock", 11: ""}
+		want    = map[uint64]int{10: 1, 11: 2}
+	)
+	for _, c := range []struct {
+		sockets map[uint64]string
+		stats   int
+	}{
+		{sockets, 3},
+		// Known file descriptors aren't stat'ed again
+		{sockets, 0},
+		// ...unless a socket turns up without a holder, once
+		{map[uint64]string{10: "/run/docker.sock", 11: "", 13: ""}, 3},
+		{map[uint64]string{10: "/run/docker.sock", 11: "", 13: ""}, 0},
+	} {
+		mockFS.stats = 0
+		if have := o.lookup("/proc", pids, c.sockets); !reflect.DeepEqual(want, have) {
+			t.Errorf("want %v, have %v", want, have)
+		}
+		if mockFS.stats != c.stats {
+			t.Errorf("want %d stats, have %d", c.stats, mockFS.stats)
+		}
+	}
+}
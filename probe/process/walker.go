@@ -14,6 +14,12 @@ type Process struct {
 	OpenFilesCount    int
 	OpenFilesLimit    uint64
 	IsWaitingInAccept bool
+
+	// Cumulative I/O counters, and whether they could all be read
+	ReadBytes, WriteBytes       uint64
+	ReadSyscalls, WriteSyscalls uint64
+	ContextSwitches             uint64
+	HasIO                       bool
 }
 
 // Walker is something that walks the /proc directory
@@ -8,7 +8,7 @@ import (
 )
 
 // NewWalker returns a Darwin (lsof-based) walker.
-func NewWalker(_ string, _ bool) Walker {
+func NewWalker(_ string, _, _ bool) Walker {
 	return &walker{}
 }
 
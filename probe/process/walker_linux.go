@@ -19,6 +19,7 @@ import (
 type walker struct {
 	procRoot                 string
 	gatheringWaitingInAccept bool
+	gatheringIO              bool
 }
 
 var (
@@ -39,10 +40,11 @@ const (
 )
 
 // NewWalker creates a new process Walker.
-func NewWalker(procRoot string, gatheringWaitingInAccept bool) Walker {
+func NewWalker(procRoot string, gatheringWaitingInAccept, gatheringIO bool) Walker {
 	return &walker{
 		procRoot:                 procRoot,
 		gatheringWaitingInAccept: gatheringWaitingInAccept,
+		gatheringIO:              gatheringIO,
 	}
 }
 
@@ -149,6 +151,64 @@ func readLimits(path string) (openFilesLimit uint64, err error) {
 	return softLimit, nil
 }
 
+// readIO reads and parses '/proc/<pid>/io' files, which are only
+// readable by the owner of the process or root.
+//
+//	rchar: 323934931
+//	wchar: 323929600
+//	syscr: 632687
+//	syscw: 632675
+//	read_bytes: 0
+//	write_bytes: 323932160
+//	cancelled_write_bytes: 0
+func readIO(path string) (readBytes, writeBytes, readSyscalls, writeSyscalls uint64, err error) {
+	buf, err := fs.ReadFile(path)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(buf), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "read_bytes:":
+			readBytes = value
+		case "write_bytes:":
+			writeBytes = value
+		case "syscr:":
+			readSyscalls = value
+		case "syscw:":
+			writeSyscalls = value
+		}
+	}
+	return
+}
+
+// readContextSwitches reads the number of voluntary and involuntary
+// context switches from '/proc/<pid>/status' files.
+func readContextSwitches(path string) (uint64, error) {
+	buf, err := fs.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var switches uint64
+	for _, line := range strings.Split(string(buf), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || (fields[0] != "voluntary_ctxt_switches:" && fields[0] != "nonvoluntary_ctxt_switches:") {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			switches += value
+		}
+	}
+	return switches, nil
+}
+
 func (w *walker) readCmdline(filename string) (cmdline, name string) {
 	if cmdlineBuf, err := fs.ReadFile(path.Join(w.procRoot, filename, "cmdline")); err == nil {
 		// like proc, treat name as the first element of command line
@@ -248,7 +308,7 @@ func (w *walker) Walk(f func(Process, Process)) error {
 			isWaitingInAccept = IsProcInAccept(w.procRoot, filename)
 		}
 
-		f(Process{
+		p := Process{
 			PID:               pid,
 			PPID:              ppid,
 			Name:              name,
@@ -260,7 +320,17 @@ func (w *walker) Walk(f func(Process, Process)) error {
 			OpenFilesCount:    openFilesCount,
 			OpenFilesLimit:    openFilesLimit,
 			IsWaitingInAccept: isWaitingInAccept,
-		}, Process{})
+		}
+		if w.gatheringIO {
+			// I/O counters are best effort: they are left out rather
+			// than the process when they can't be read.
+			var ioErr, switchesErr error
+			p.ReadBytes, p.WriteBytes, p.ReadSyscalls, p.WriteSyscalls, ioErr = readIO(path.Join(w.procRoot, filename, "io"))
+			p.ContextSwitches, switchesErr = readContextSwitches(path.Join(w.procRoot, filename, "status"))
+			p.HasIO = ioErr == nil && switchesErr == nil
+		}
+
+		f(p, Process{})
 	}
 
 	return nil
@@ -25,6 +25,14 @@ var mockFS = fs.Dir("",
 				FName:     "limits",
 				FContents: "Limit Soft-Limit Hard-Limit Units\nMax open files 32768 65536 files",
 			},
+			fs.File{
+				FName:     "io",
+				FContents: "rchar: 4096\nwchar: 1024\nsyscr: 12\nsyscw: 3\nread_bytes: 8192\nwrite_bytes: 512\ncancelled_write_bytes: 0\n",
+			},
+			fs.File{
+				FName:     "status",
+				FContents: "Name:\tcurl\nThreads:\t1\nvoluntary_ctxt_switches:\t150\nnonvoluntary_ctxt_switches:\t7\n",
+			},
 			fs.Dir("fd", fs.File{FName: "0"}, fs.File{FName: "1"}, fs.File{FName: "2"}),
 		),
 		fs.Dir("2",
@@ -81,14 +89,14 @@ func TestWalker(t *testing.T) {
 	defer fs_hook.Restore()
 
 	want := map[int]process.Process{
-		3: {PID: 3, PPID: 2, Name: "curl", Cmdline: "curl google.com", Threads: 1, RSSBytes: 8192, RSSBytesLimit: 2048, OpenFilesCount: 3, OpenFilesLimit: 32768},
+		3: {PID: 3, PPID: 2, Name: "curl", Cmdline: "curl google.com", Threads: 1, RSSBytes: 8192, RSSBytesLimit: 2048, OpenFilesCount: 3, OpenFilesLimit: 32768, ReadBytes: 8192, WriteBytes: 512, ReadSyscalls: 12, WriteSyscalls: 3, ContextSwitches: 157, HasIO: true},
 		2: {PID: 2, PPID: 1, Name: "bash", Cmdline: "bash", Threads: 1, OpenFilesCount: 2},
 		4: {PID: 4, PPID: 3, Name: "apache", Cmdline: "apache", Threads: 1, OpenFilesCount: 1},
 		1: {PID: 1, PPID: 0, Name: "init", Cmdline: "init", Threads: 1, OpenFilesCount: 0},
 	}
 
 	have := map[int]process.Process{}
-	walker := process.NewWalker("/proc", false)
+	walker := process.NewWalker("/proc", false, true)
 	err := walker.Walk(func(p, _ process.Process) {
 		have[p.PID] = p
 	})
@@ -97,3 +105,18 @@ func TestWalker(t *testing.T) {
 		t.Errorf("%v (%v)", test.Diff(want, have), err)
 	}
 }
+
+func TestWalkerWithoutIO(t *testing.T) {
+	fs_hook.Mock(mockFS)
+	defer fs_hook.Restore()
+
+	walker := process.NewWalker("/proc", false, false)
+	err := walker.Walk(func(p, _ process.Process) {
+		if p.ReadBytes != 0 || p.WriteBytes != 0 || p.ReadSyscalls != 0 || p.WriteSyscalls != 0 || p.ContextSwitches != 0 {
+			t.Errorf("Expected no I/O counters for pid %d, got %+v", p.PID, p)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
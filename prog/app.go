@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	_ "net/http/pprof"
@@ -76,7 +77,7 @@ func router(collector app.Collector, controlRouter app.ControlRouter, pipeRouter
 }
 
 func collectorFactory(userIDer multitenant.UserIDer, collectorURL, s3URL, natsHostname string,
-	memcacheConfig multitenant.MemcacheConfig, window time.Duration, createTables bool) (app.Collector, error) {
+	memcacheConfig multitenant.MemcacheConfig, diskConfig app.DiskCollectorConfig, replayConfig app.ReplayConfig, window time.Duration, createTables bool) (app.Collector, error) {
 	if collectorURL == "local" {
 		return app.NewCollector(window), nil
 	}
@@ -88,7 +89,11 @@ func collectorFactory(userIDer multitenant.UserIDer, collectorURL, s3URL, natsHo
 
 	switch parsed.Scheme {
 	case "file":
-		return app.NewFileCollector(parsed.Path, window)
+		return app.NewFileCollector(parsed.Path, window, replayConfig)
+	case "disk":
+		diskConfig.Path = parsed.Path
+		diskConfig.Window = window
+		return app.NewDiskCollector(diskConfig)
 	case "dynamodb":
 		s3, err := url.Parse(s3URL)
 		if err != nil {
@@ -208,6 +213,18 @@ func appMain(flags appFlags) {
 	log.Infof("app starting, version %s, ID %s", app.Version, app.UniqueID)
 	logCensoredArgs()
 
+	if flags.topologyViews != "" {
+		views, err := app.LoadTopologyViews(flags.topologyViews)
+		if err != nil {
+			log.Fatalf("Error loading topology views: %v", err)
+			return
+		}
+		if err := app.AddTopologyViews(views...); err != nil {
+			log.Fatalf("Error adding topology views: %v", err)
+			return
+		}
+	}
+
 	userIDer := multitenant.NoopUserIDer
 	if flags.userIDHeader != "" {
 		userIDer = multitenant.UserIDHeader(flags.userIDHeader)
@@ -223,11 +240,33 @@ func appMain(flags appFlags) {
 			Service:          flags.memcachedService,
 			CompressionLevel: flags.memcachedCompressionLevel,
 		},
+		app.DiskCollectorConfig{
+			MaxAge:  flags.diskMaxAge,
+			MaxSize: flags.diskMaxSize,
+		},
+		flags.replay,
 		flags.window, flags.awsCreateTables)
 	if err != nil {
 		log.Fatalf("Error creating collector: %v", err)
 		return
 	}
+	if closer, ok := collector.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				log.Errorf("Error closing collector: %v", err)
+			}
+		}()
+	}
+
+	if flags.recordDir != "" {
+		recorder, err := app.NewRecorder(flags.recordDir)
+		if err != nil {
+			log.Fatalf("Error creating recorder: %v", err)
+			return
+		}
+		log.Infof("recording reports to %s", flags.recordDir)
+		collector = app.NewRecordingCollector(collector, recorder)
+	}
 
 	if flags.BillingEmitterConfig.Enabled {
 		billingEmitter, err := emitterFactory(collector, flags.BillingClientConfig, userIDer, flags.BillingEmitterConfig)
@@ -239,6 +278,12 @@ func appMain(flags appFlags) {
 		collector = billingEmitter
 	}
 
+	// Export the rendered topologies on /metrics. This needs a single
+	// tenant, as scrapes carry no user ID.
+	if flags.userIDHeader == "" {
+		prometheus.MustRegister(app.NewTopologyMetrics(collector))
+	}
+
 	controlRouter, err := controlRouterFactory(userIDer, flags.controlRouterURL, flags.controlRPCTimeout)
 	if err != nil {
 		log.Fatalf("Error creating control router: %v", err)
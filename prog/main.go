@@ -78,8 +78,10 @@ func setLogLevel(levelname string) {
 }
 
 type flags struct {
-	probe probeFlags
-	app   appFlags
+	probe  probeFlags
+	app    appFlags
+	query  queryFlags
+	record recordFlags
 
 	mode                             string
 	debug                            bool
@@ -112,17 +114,24 @@ type probeFlags struct {
 
 	spyProcs    bool // Associate endpoints with processes (must be root)
 	procEnabled bool // Produce process topology & process nodes in endpoint
+	procIO      bool // Read the I/O counters of processes
 	useEbpfConn bool // Enable connection tracking with eBPF
+	ebpfListen  bool // Also walk /proc for listening sockets with eBPF
 	procRoot    string
+	httpPorts   string // Server ports to sniff HTTP requests on
 
 	dockerEnabled  bool
 	dockerInterval time.Duration
 	dockerBridge   string
 
+	criEnabled  bool
+	criEndpoint string
+
 	kubernetesEnabled      bool
 	kubernetesNodeName     string
 	kubernetesClientConfig kubernetes.ClientConfig
 	kubernetesKubeletPort  uint
+	kubernetesEvents       bool
 
 	ecsEnabled       bool
 	ecsCacheSize     int
@@ -160,9 +169,14 @@ type appFlags struct {
 	memcachedService          string
 	memcachedExpiration       time.Duration
 	memcachedCompressionLevel int
+	diskMaxAge                time.Duration
+	diskMaxSize               int64
+	replay                    app.ReplayConfig
+	recordDir                 string
 	userIDHeader              string
 	externalUI                bool
 	metricsGraphURL           string
+	topologyViews             string
 
 	blockProfileRate int
 
@@ -295,13 +309,20 @@ func setupFlags(flags *flags) {
 	flag.BoolVar(&flags.probe.spyProcs, "probe.proc.spy", true, "associate endpoints with processes (needs root)")
 	flag.StringVar(&flags.probe.procRoot, "probe.proc.root", "/proc", "location of the proc filesystem")
 	flag.BoolVar(&flags.probe.procEnabled, "probe.processes", true, "produce process topology & include procspied connections")
+	flag.BoolVar(&flags.probe.procIO, "probe.processes.io", false, "report the disk I/O and context switches of processes, reading two more /proc files per process")
 	flag.BoolVar(&flags.probe.useEbpfConn, "probe.ebpf.connections", true, "enable connection tracking with eBPF")
+	flag.BoolVar(&flags.probe.ebpfListen, "probe.ebpf.listening", true, "also walk /proc, every 10s, for the sockets processes listen on when tracking connections with eBPF")
+	flag.StringVar(&flags.probe.httpPorts, "probe.http.ports", "", "comma-separated server ports to sniff HTTP requests on, for request rate, error and latency metrics (needs root); disabled if empty")
 
 	// Docker
 	flag.BoolVar(&flags.probe.dockerEnabled, "probe.docker", false, "collect Docker-related attributes for processes")
 	flag.DurationVar(&flags.probe.dockerInterval, "probe.docker.interval", 10*time.Second, "how often to update Docker attributes")
 	flag.StringVar(&flags.probe.dockerBridge, "probe.docker.bridge", "docker0", "the docker bridge name")
 
+	// CRI
+	flag.BoolVar(&flags.probe.criEnabled, "probe.cri", false, "collect container attributes from a CRI runtime (e.g. containerd or CRI-O) instead of Docker")
+	flag.StringVar(&flags.probe.criEndpoint, "probe.cri.endpoint", "unix:///run/containerd/containerd.sock", "the endpoint of the CRI runtime (e.g. unix:///var/run/crio/crio.sock for CRI-O)")
+
 	// K8s
 	flag.BoolVar(&flags.probe.kubernetesEnabled, "probe.kubernetes", false, "collect kubernetes-related attributes for containers")
 	flag.StringVar(&flags.probe.kubernetesClientConfig.Server, "probe.kubernetes.api", "", "The address and port of the Kubernetes API server (deprecated in favor of equivalent probe.kubernetes.server)")
@@ -319,6 +340,7 @@ func setupFlags(flags *flags) {
 	flag.StringVar(&flags.probe.kubernetesClientConfig.Username, "probe.kubernetes.username", "", "Username for basic authentication to the API server")
 	flag.StringVar(&flags.probe.kubernetesNodeName, "probe.kubernetes.node-name", "", "Name of this node, for filtering pods")
 	flag.UintVar(&flags.probe.kubernetesKubeletPort, "probe.kubernetes.kubelet-port", 10255, "Node-local TCP port for contacting kubelet")
+	flag.BoolVar(&flags.probe.kubernetesEvents, "probe.kubernetes.events", false, "report the events of all the pods and other objects of the cluster, watching them all; enable in a single probe, as in examples/k8s/cluster-agent.yaml")
 
 	// AWS ECS
 	flag.BoolVar(&flags.probe.ecsEnabled, "probe.ecs", false, "Collect ecs-related attributes for containers on this node")
@@ -346,7 +368,7 @@ func setupFlags(flags *flags) {
 	flag.Var(&flags.containerLabelFilterFlags, "app.container-label-filter", "Add container label-based view filter, specified as title:label. Multiple flags are accepted. Example: --app.container-label-filter='Database Containers:role=db'")
 	flag.Var(&flags.containerLabelFilterFlagsExclude, "app.container-label-filter-exclude", "Add container label-based view filter that excludes containers with the given label, specified as title:label. Multiple flags are accepted. Example: --app.container-label-filter-exclude='Database Containers:role=db'")
 
-	flag.StringVar(&flags.app.collectorURL, "app.collector", "local", "Collector to use (local, dynamodb, or file/directory)")
+	flag.StringVar(&flags.app.collectorURL, "app.collector", "local", "Collector to use (local, dynamodb, disk:///path/to/history, or file/directory)")
 	flag.StringVar(&flags.app.s3URL, "app.collector.s3", "local", "S3 URL to use (when collector is dynamodb)")
 	flag.StringVar(&flags.app.controlRouterURL, "app.control.router", "local", "Control router to use (local or sqs)")
 	flag.DurationVar(&flags.app.controlRPCTimeout, "app.control.rpctimeout", time.Minute, "Timeout for control RPC")
@@ -357,14 +379,35 @@ func setupFlags(flags *flags) {
 	flag.DurationVar(&flags.app.memcachedExpiration, "app.memcached.expiration", 2*15*time.Second, "How long reports stay in the memcache.")
 	flag.StringVar(&flags.app.memcachedService, "app.memcached.service", "memcached", "SRV service used to discover memcache servers.")
 	flag.IntVar(&flags.app.memcachedCompressionLevel, "app.memcached.compression", gzip.DefaultCompression, "How much to compress reports stored in memcached.")
+	flag.DurationVar(&flags.app.diskMaxAge, "app.collector.disk.max-age", 24*time.Hour, "How long reports are kept (when collector is disk). 0 keeps them forever.")
+	flag.Int64Var(&flags.app.diskMaxSize, "app.collector.disk.max-size", 1<<30, "Maximum bytes of reports to keep (when collector is disk). 0 means no limit.")
+	flag.Float64Var(&flags.app.replay.Speed, "app.collector.replay.speed", 1, "Playback speed when replaying recorded reports (when collector is a directory)")
+	flag.DurationVar(&flags.app.replay.Start, "app.collector.replay.start", 0, "Offset into the recording at which to start replaying (when collector is a directory)")
+	flag.DurationVar(&flags.app.replay.End, "app.collector.replay.end", 0, "Offset into the recording at which to stop replaying; 0 replays until the end (when collector is a directory)")
+	flag.BoolVar(&flags.app.replay.Loop, "app.collector.replay.loop", true, "Start over when the end of the recording is reached (when collector is a directory)")
+	flag.StringVar(&flags.app.recordDir, "app.record.dir", "", "Directory to record every received report to, for later replay with --app.collector=file:///path/to/dir")
 	flag.StringVar(&flags.app.userIDHeader, "app.userid.header", "", "HTTP header to use as userid")
 	flag.BoolVar(&flags.app.externalUI, "app.externalUI", false, "Point to externally hosted static UI assets")
 	flag.StringVar(&flags.app.metricsGraphURL, "app.metrics-graph", "", "Enable extended metrics graph by providing a templated URL (supports :orgID and :query). Example: --app.metric-graph=/prom/:orgID/notebook/new")
+	flag.StringVar(&flags.app.topologyViews, "app.topology-views", "", "YAML or JSON file declaring extra topology views, grouping and filtering the nodes of existing topologies")
 
 	flag.IntVar(&flags.app.blockProfileRate, "app.block.profile.rate", 0, "If more than 0, enable block profiling. The profiler aims to sample an average of one blocking event per rate nanoseconds spent blocked.")
 
 	flag.BoolVar(&flags.app.awsCreateTables, "app.aws.create.tables", false, "Create the tables in DynamoDB")
 	flag.StringVar(&flags.app.consulInf, "app.consul.inf", "", "The interface who's address I should advertise myself under in consul")
+
+	// Query flags
+	flag.StringVar(&flags.query.appURL, "query.app", "http://localhost:"+strconv.Itoa(xfer.AppPort), "URL of the app to query")
+	flag.BoolVar(&flags.query.json, "query.json", false, "Print the JSON returned by the app instead of tables")
+	flag.BoolVar(&flags.query.watch, "query.watch", false, "Keep printing the changes to the topology")
+	flag.Var(&flags.query.options, "query.option", "Topology option, as <option group>=<value>, e.g. --query.option=system=application. Can be repeated.")
+	flag.StringVar(&flags.query.logLevel, "query.log.level", "info", "logging threshold level: debug|info|warn|error|fatal|panic")
+
+	// Record flags
+	flag.StringVar(&flags.record.appURL, "record.app", "http://localhost:"+strconv.Itoa(xfer.AppPort), "URL of the app to record the reports of")
+	flag.StringVar(&flags.record.dir, "record.dir", ".", "Directory to record the reports to, for later replay with --app.collector=file:///path/to/dir")
+	flag.DurationVar(&flags.record.interval, "record.interval", 3*time.Second, "How often to record the report of the app")
+	flag.StringVar(&flags.record.logLevel, "record.log.level", "info", "logging threshold level: debug|info|warn|error|fatal|panic")
 }
 
 func main() {
@@ -380,6 +423,8 @@ func main() {
 	if flags.debug {
 		flags.probe.logLevel = "debug"
 		flags.app.logLevel = "debug"
+		flags.query.logLevel = "debug"
+		flags.record.logLevel = "debug"
 	}
 	if flags.weaveHostname != "" {
 		if flags.probe.weaveHostname == "" {
@@ -443,6 +488,10 @@ func main() {
 		appMain(flags.app)
 	case "probe":
 		probeMain(flags.probe, targets)
+	case "query":
+		queryMain(flags.query, flag.Args())
+	case "record":
+		recordMain(flags.record)
 	case "version":
 		fmt.Println("Weave Scope version", version)
 	case "help":
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -24,6 +25,7 @@ import (
 	"github.com/weaveworks/scope/probe/appclient"
 	"github.com/weaveworks/scope/probe/awsecs"
 	"github.com/weaveworks/scope/probe/controls"
+	"github.com/weaveworks/scope/probe/cri"
 	"github.com/weaveworks/scope/probe/docker"
 	"github.com/weaveworks/scope/probe/endpoint"
 	"github.com/weaveworks/scope/probe/host"
@@ -86,6 +88,19 @@ func maybeExportProfileData(flags probeFlags) {
 	}
 }
 
+// parsePorts parses a comma-separated list of ports.
+func parsePorts(s string) ([]uint16, error) {
+	var ports []uint16
+	for _, field := range strings.Split(s, ",") {
+		port, err := strconv.ParseUint(strings.TrimSpace(field), 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, uint16(port))
+	}
+	return ports, nil
+}
+
 // Main runs the probe
 func probeMain(flags probeFlags, targets []appclient.Target) {
 	setLogLevel(flags.logLevel)
@@ -157,9 +172,12 @@ func probeMain(flags probeFlags, targets []appclient.Target) {
 
 	var processCache *process.CachingWalker
 	if flags.procEnabled {
-		processCache = process.NewCachingWalker(process.NewWalker(flags.procRoot, false))
+		processCache = process.NewCachingWalker(process.NewWalker(flags.procRoot, false, flags.procIO))
 		p.AddTicker(processCache)
-		p.AddReporter(process.NewReporter(processCache, hostID, process.GetDeltaTotalJiffies, flags.noCommandLineArguments))
+		p.AddReporter(process.NewReporter(processCache, hostID, process.GetDeltaTotalJiffies, flags.noCommandLineArguments, flags.procIO))
+		if flags.spyProcs {
+			p.AddReporter(process.NewUnixSocketReporter(processCache, hostID, flags.procRoot, process.NewUnixConnections()))
+		}
 	}
 
 	dnsSnooper, err := endpoint.NewDNSSnooper()
@@ -176,6 +194,7 @@ func probeMain(flags probeFlags, targets []appclient.Target) {
 		UseConntrack: flags.useConntrack,
 		WalkProc:     flags.procEnabled,
 		UseEbpfConn:  flags.useEbpfConn,
+		EbpfListen:   flags.ebpfListen,
 		ProcRoot:     flags.procRoot,
 		BufferSize:   flags.conntrackBufferSize,
 		ProcessCache: processCache,
@@ -184,10 +203,21 @@ func probeMain(flags probeFlags, targets []appclient.Target) {
 	defer endpointReporter.Stop()
 	p.AddReporter(endpointReporter)
 
-	if flags.dockerEnabled {
+	if flags.httpPorts != "" {
+		if ports, err := parsePorts(flags.httpPorts); err != nil {
+			log.Errorf("HTTP sniffer: invalid ports: %v", err)
+		} else if httpSniffer, err := endpoint.NewHTTPSniffer(hostID, ports); err != nil {
+			log.Errorf("Failed to start HTTP sniffer: %v", err)
+		} else {
+			defer httpSniffer.Stop()
+			p.AddReporter(httpSniffer)
+		}
+	}
+
+	if flags.dockerEnabled || flags.criEnabled {
 		// Don't add the bridge in Kubernetes since container IPs are global and
 		// shouldn't be scoped
-		if !flags.kubernetesEnabled {
+		if !flags.kubernetesEnabled && !flags.criEnabled {
 			if err := report.AddLocalBridge(flags.dockerBridge); err != nil {
 				log.Errorf("Docker: problem with bridge %s: %v", flags.dockerBridge, err)
 			}
@@ -201,7 +231,14 @@ func probeMain(flags probeFlags, targets []appclient.Target) {
 			NoCommandLineArguments: flags.noCommandLineArguments,
 			NoEnvironmentVariables: flags.noEnvironmentVariables,
 		}
-		if registry, err := docker.NewRegistry(options); err == nil {
+		var err error
+		if flags.criEnabled {
+			// The containers of CRI runtimes are described as Docker's
+			options.Client, err = cri.NewClient(flags.criEndpoint)
+		}
+		if err != nil {
+			log.Errorf("CRI: failed to connect to %s: %v", flags.criEndpoint, err)
+		} else if registry, err := docker.NewRegistry(options); err == nil {
 			defer registry.Stop()
 			if flags.procEnabled {
 				p.AddTagger(docker.NewTagger(registry, processCache))
@@ -215,7 +252,7 @@ func probeMain(flags probeFlags, targets []appclient.Target) {
 	if flags.kubernetesEnabled {
 		if client, err := kubernetes.NewClient(flags.kubernetesClientConfig); err == nil {
 			defer client.Stop()
-			reporter := kubernetes.NewReporter(client, clients, probeID, hostID, p, handlerRegistry, flags.kubernetesNodeName, flags.kubernetesKubeletPort)
+			reporter := kubernetes.NewReporter(client, clients, probeID, hostID, p, handlerRegistry, flags.kubernetesNodeName, flags.kubernetesKubeletPort, flags.kubernetesEvents)
 			defer reporter.Stop()
 			p.AddReporter(reporter)
 			p.AddTagger(reporter)
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/websocket"
+	"github.com/ugorji/go/codec"
+
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/common/xfer"
+	"github.com/weaveworks/scope/render/detailed"
+	"github.com/weaveworks/scope/report"
+)
+
+const queryUsage = "usage: scope query [topology [node]]"
+
+type queryFlags struct {
+	appURL   string
+	json     bool
+	watch    bool
+	options  queryOptionsFlag
+	logLevel string
+}
+
+// queryOptionsFlag collects topology options, as sent by the UI, given
+// as repeated --query.option=<option group>=<value> flags.
+type queryOptionsFlag struct {
+	values url.Values
+}
+
+func (q *queryOptionsFlag) String() string {
+	return q.values.Encode()
+}
+
+func (q *queryOptionsFlag) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("topology option must be of the form <option group>=<value>, got %q", s)
+	}
+	if q.values == nil {
+		q.values = url.Values{}
+	}
+	q.values.Set(kv[0], kv[1])
+	return nil
+}
+
+// queryClient talks to the HTTP API of a running app.
+type queryClient struct {
+	appURL  string
+	client  *http.Client
+	options url.Values
+	json    bool
+}
+
+func (c queryClient) url(p string, query url.Values) string {
+	u := strings.TrimSuffix(c.appURL, "/") + p
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+func (c queryClient) get(p string, query url.Values) ([]byte, error) {
+	resp, err := c.client.Get(c.url(p, query))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("app responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// fetch gets p from the app, and either writes the raw JSON to w, or
+// decodes it into v.
+func (c queryClient) fetch(w io.Writer, p string, query url.Values, v interface{}) (bool, error) {
+	body, err := c.get(p, query)
+	if err != nil {
+		return false, err
+	}
+	if c.json {
+		_, err := fmt.Fprintf(w, "%s\n", body)
+		return false, err
+	}
+	return true, codec.NewDecoderBytes(body, &codec.JsonHandle{}).Decode(v)
+}
+
+// topologies lists the topologies, with their option groups.
+func (c queryClient) topologies(w io.Writer) error {
+	var topologies []app.APITopologyDesc
+	if decoded, err := c.fetch(w, "/api/topology", c.options, &topologies); err != nil || !decoded {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOPOLOGY\tNAME\tNODES\tOPTIONS")
+	var list func(prefix string, ts []app.APITopologyDesc)
+	list = func(prefix string, ts []app.APITopologyDesc) {
+		for _, t := range ts {
+			options := []string{}
+			for _, group := range t.Options {
+				values := []string{}
+				for _, option := range group.Options {
+					values = append(values, option.Value)
+				}
+				options = append(options, fmt.Sprintf("%s=%s", group.ID, strings.Join(values, "|")))
+			}
+			fmt.Fprintf(tw, "%s\t%s%s\t%d\t%s\n", path.Base(t.URL), prefix, t.Name, t.Stats.NodeCount, strings.Join(options, " "))
+			list(prefix+"  ", t.SubTopologies)
+		}
+	}
+	list("", topologies)
+	return tw.Flush()
+}
+
+// topology lists the nodes of a topology, with their neighbours.
+func (c queryClient) topology(w io.Writer, topologyID string) error {
+	var topology app.APITopology
+	if decoded, err := c.fetch(w, "/api/topology/"+url.PathEscape(topologyID), c.options, &topology); err != nil || !decoded {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tLABEL\tDETAIL\tNEIGHBOURS")
+	for _, n := range sortedSummaries(topology.Nodes) {
+		neighbours := []string{}
+		for _, id := range n.Adjacency {
+			if neighbour, ok := topology.Nodes[id]; ok {
+				neighbours = append(neighbours, neighbour.Label)
+			} else {
+				neighbours = append(neighbours, id)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", n.ID, n.Label, n.LabelMinor, strings.Join(neighbours, ", "))
+	}
+	return tw.Flush()
+}
+
+// node prints the details of a node: metadata, metrics and connections.
+func (c queryClient) node(w io.Writer, topologyID, nodeID string) error {
+	var node app.APINode
+	if decoded, err := c.fetch(w, "/api/topology/"+url.PathEscape(topologyID)+"/"+url.PathEscape(nodeID), c.options, &node); err != nil || !decoded {
+		return err
+	}
+	n := node.Node
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\n", n.Label, n.LabelMinor)
+	fmt.Fprintf(tw, "ID\t%s\n", n.ID)
+	for _, parent := range n.Parents {
+		fmt.Fprintf(tw, "Parent\t%s (%s)\n", parent.Label, parent.TopologyID)
+	}
+	if len(n.Metadata) > 0 {
+		fmt.Fprintln(tw, "\nMETADATA\t")
+		for _, row := range n.Metadata {
+			fmt.Fprintf(tw, "%s\t%s\n", row.Label, row.Value)
+		}
+	}
+	if len(n.Metrics) > 0 {
+		fmt.Fprintln(tw, "\nMETRICS\t")
+		for _, row := range n.Metrics {
+			fmt.Fprintf(tw, "%s\t%s\n", row.Label, formatMetric(row))
+		}
+	}
+	for _, summary := range n.Connections {
+		if len(summary.Connections) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "\n%s\t\n", strings.ToUpper(summary.Label))
+		for _, conn := range summary.Connections {
+			values := []string{}
+			for _, row := range conn.Metadata {
+				values = append(values, row.Value)
+			}
+			fmt.Fprintf(tw, "%s\t%s\n", conn.Label, strings.Join(values, "\t"))
+		}
+	}
+	return tw.Flush()
+}
+
+// watch follows the websocket diff stream of a topology, printing
+// added (+), updated (~) and removed (-) nodes as they change.
+func (c queryClient) watch(w io.Writer, topologyID string) error {
+	u, err := url.Parse(c.url("/api/topology/"+url.PathEscape(topologyID)+"/ws", c.options))
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	conn, _, err := xfer.DialWS(&websocket.Dialer{}, u.String(), http.Header{})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for {
+		var diff detailed.Diff
+		if err := conn.ReadJSON(&diff); err != nil {
+			if xfer.IsExpectedWSCloseError(err) {
+				return nil
+			}
+			return err
+		}
+		if c.json {
+			if err := codec.NewEncoder(w, &codec.JsonHandle{}).Encode(diff); err != nil {
+				return err
+			}
+			fmt.Fprintln(w)
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for _, n := range diff.Add {
+			fmt.Fprintf(tw, "+\t%s\t%s\t%s\n", n.ID, n.Label, n.LabelMinor)
+		}
+		for _, n := range diff.Update {
+			fmt.Fprintf(tw, "~\t%s\t%s\t%s\n", n.ID, n.Label, n.LabelMinor)
+		}
+		for _, id := range diff.Remove {
+			fmt.Fprintf(tw, "-\t%s\t\t\n", id)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+type summariesByLabel []detailed.NodeSummary
+
+func (s summariesByLabel) Len() int      { return len(s) }
+func (s summariesByLabel) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s summariesByLabel) Less(i, j int) bool {
+	if s[i].Label != s[j].Label {
+		return s[i].Label < s[j].Label
+	}
+	return s[i].ID < s[j].ID
+}
+
+func sortedSummaries(nodes detailed.NodeSummaries) []detailed.NodeSummary {
+	result := make([]detailed.NodeSummary, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, n)
+	}
+	sort.Sort(summariesByLabel(result))
+	return result
+}
+
+// formatMetric renders the value of a metric the way the UI does.
+func formatMetric(row report.MetricRow) string {
+	if row.ValueEmpty {
+		return "n/a"
+	}
+	switch row.Format {
+	case report.PercentFormat:
+		return fmt.Sprintf("%.2f%%", row.Value)
+	case report.IntegerFormat:
+		return fmt.Sprintf("%.0f", row.Value)
+	case report.FilesizeFormat:
+		value, units := row.Value, []string{"B", "KB", "MB", "GB", "TB"}
+		i := 0
+		for ; value >= 1024 && i < len(units)-1; i++ {
+			value /= 1024
+		}
+		return fmt.Sprintf("%.1f %s", value, units[i])
+	}
+	return fmt.Sprintf("%.2f", row.Value)
+}
+
+// queryMain prints the topologies, the nodes of a topology or the
+// details of a node of a running app.
+func queryMain(flags queryFlags, args []string) {
+	setLogLevel(flags.logLevel)
+
+	c := queryClient{
+		appURL:  flags.appURL,
+		client:  &http.Client{Timeout: httpTimeout},
+		options: flags.options.values,
+		json:    flags.json,
+	}
+	var err error
+	switch {
+	case len(args) == 0:
+		err = c.topologies(os.Stdout)
+	case len(args) == 1 && flags.watch:
+		err = c.watch(os.Stdout, args[0])
+	case len(args) == 1:
+		err = c.topology(os.Stdout, args[0])
+	case len(args) == 2:
+		err = c.node(os.Stdout, args[0], args[1])
+	default:
+		err = errors.New(queryUsage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
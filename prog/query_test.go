@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/test/fixture"
+)
+
+func queryServer() *httptest.Server {
+	router := mux.NewRouter().SkipClean(true)
+	app.RegisterTopologyRoutes(router, app.StaticCollector(fixture.Report), map[string]bool{})
+	return httptest.NewServer(router)
+}
+
+func TestQuery(t *testing.T) {
+	ts := queryServer()
+	defer ts.Close()
+	c := queryClient{appURL: ts.URL, client: http.DefaultClient}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, c.topologies(buf))
+	assert.Contains(t, buf.String(), "processes-by-name")
+
+	buf.Reset()
+	assert.NoError(t, c.topology(buf, "processes"))
+	assert.Contains(t, buf.String(), fixture.ServerProcessNodeID)
+	assert.Contains(t, buf.String(), "apache")
+
+	buf.Reset()
+	assert.NoError(t, c.node(buf, "processes", fixture.ServerProcessNodeID))
+	assert.True(t, strings.HasPrefix(buf.String(), "apache"), buf.String())
+
+	buf.Reset()
+	c.json = true
+	assert.NoError(t, c.topology(buf, "processes"))
+	assert.True(t, strings.HasPrefix(buf.String(), `{"nodes":`), buf.String())
+
+	assert.Error(t, c.node(buf, "processes", "foo"))
+}
+
+func TestQueryEscapesPath(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+	c := queryClient{appURL: ts.URL, client: http.DefaultClient}
+
+	assert.Error(t, c.node(&bytes.Buffer{}, "containers-by-image", "foo bar"))
+	assert.Equal(t, "/api/topology/containers-by-image/foo bar", path)
+}
+
+func TestQueryOptionsFlag(t *testing.T) {
+	var options queryOptionsFlag
+	assert.NoError(t, options.Set("system=application"))
+	assert.NoError(t, options.Set("namespace=default"))
+	assert.Error(t, options.Set("foo"))
+	assert.Equal(t, "namespace=default&system=application", options.String())
+}
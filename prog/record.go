@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/ugorji/go/codec"
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/report"
+)
+
+type recordFlags struct {
+	appURL   string
+	dir      string
+	interval time.Duration
+	logLevel string
+}
+
+// reportRecorder fetches the reports of a running app, and hands them
+// to a recorder.
+type reportRecorder struct {
+	appURL   string
+	client   *http.Client
+	recorder app.Adder
+}
+
+// record fetches the current report of the app and records it.
+func (r reportRecorder) record(ctx context.Context) error {
+	resp, err := r.client.Get(strings.TrimSuffix(r.appURL, "/") + "/api/report")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching report: %s", resp.Status)
+	}
+	rpt := report.MakeReport()
+	if err := rpt.ReadBinary(resp.Body, false, &codec.JsonHandle{}); err != nil {
+		return err
+	}
+	return r.recorder.Add(ctx, rpt, nil)
+}
+
+// recordMain writes the reports of a running app to a directory, every
+// interval, until killed. The directory can be replayed by an app with
+// --app.collector=file:///path/to/dir.
+func recordMain(flags recordFlags) {
+	setLogLevel(flags.logLevel)
+
+	recorder, err := app.NewRecorder(flags.dir)
+	if err != nil {
+		log.Fatalf("Error creating recorder: %v", err)
+	}
+	r := reportRecorder{
+		appURL:   flags.appURL,
+		client:   &http.Client{Timeout: httpTimeout},
+		recorder: recorder,
+	}
+	log.Infof("recording reports of %s to %s", flags.appURL, flags.dir)
+	ctx := context.Background()
+	for range time.Tick(flags.interval) {
+		if err := r.record(ctx); err != nil {
+			log.Errorf("Error recording report: %v", err)
+		}
+	}
+}
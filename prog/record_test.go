@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	"github.com/weaveworks/scope/app"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/fixture"
+)
+
+func TestRecord(t *testing.T) {
+	ts := queryServer()
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "scope-record")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	recorder, err := app.NewRecorder(dir)
+	assert.NoError(t, err)
+	r := reportRecorder{appURL: ts.URL, client: http.DefaultClient, recorder: recorder}
+
+	assert.NoError(t, r.record(context.Background()))
+	assert.NoError(t, r.record(context.Background()))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	rpt, err := report.MakeFromFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, rpt.Process.Nodes, fixture.ServerProcessNodeID)
+
+	r.appURL = ts.URL + "/nowhere"
+	assert.Error(t, r.record(context.Background()))
+}
@@ -197,7 +197,7 @@ var ContainerHostnameRenderer = FilterEmpty(report.Container,
 	),
 )
 
-var portMappingMatch = regexp.MustCompile(`([0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}):([0-9]+)->([0-9]+)/tcp`)
+var portMappingMatch = regexp.MustCompile(`(?:([0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3}\.[0-9]{1,3})|\[([0-9a-fA-F:.]+)\]):([0-9]+)->([0-9]+)/tcp`)
 
 // MapContainer2IP maps container nodes to their IP addresses (outputs
 // multiple nodes).  This allows container to be joined directly with
@@ -235,7 +235,8 @@ func MapContainer2IP(m report.Node) []string {
 	ports, _ := m.Sets.Lookup(docker.ContainerPorts)
 	for _, portMapping := range ports {
 		if mapping := portMappingMatch.FindStringSubmatch(portMapping); mapping != nil {
-			ip, port := mapping[1], mapping[2]
+			// Only one of the IPv4 and IPv6 groups matches
+			ip, port := mapping[1]+mapping[2], mapping[3]
 			id := report.MakeScopedEndpointNodeID("", ip, port)
 			result = append(result, id)
 		}
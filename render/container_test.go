@@ -36,6 +36,16 @@ func TestMapProcess2Container(t *testing.T) {
 	}
 }
 
+func TestMapContainer2IP(t *testing.T) {
+	node := report.MakeNode("container").WithSets(report.MakeSets().
+		Add(docker.ContainerIPsWithScopes, report.MakeStringSet(";1.2.3.4", ";fd00::4", "scope;::1")).
+		Add(docker.ContainerPorts, report.MakeStringSet("10.0.0.1:8080->80/tcp", "[fd00::1]:8080->80/tcp", "81/tcp")))
+	want := []string{";1.2.3.4;", ";fd00::4;", ";10.0.0.1;8080", ";fd00::1;8080"}
+	if have := render.MapContainer2IP(node); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
 type testcase struct {
 	name string
 	n    report.Node
@@ -178,6 +178,16 @@ var nodeSummaryGroupSpecs = []struct {
 			},
 		},
 	},
+	{
+		topologyID: report.KubernetesNode,
+		NodeSummaryGroup: NodeSummaryGroup{
+			Label: "Kubernetes nodes",
+			Columns: []Column{
+				{ID: kubernetes.State, Label: "Status"},
+				{ID: kubernetes.KubeletVersion, Label: "Kubelet version"},
+			},
+		},
+	},
 	{
 		topologyID: report.ContainerImage,
 		NodeSummaryGroup: NodeSummaryGroup{
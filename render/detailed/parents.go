@@ -20,7 +20,11 @@ var parentTopologies = []string{
 	report.DaemonSet,
 	report.StatefulSet,
 	report.CronJob,
+	report.Job,
 	report.Service,
+	report.PersistentVolumeClaim,
+	report.PersistentVolume,
+	report.StorageClass,
 	report.ECSTask,
 	report.ECSService,
 	report.SwarmService,
@@ -2,10 +2,13 @@ package detailed
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/weaveworks/scope/probe/awsecs"
 	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/probe/endpoint"
 	"github.com/weaveworks/scope/probe/kubernetes"
 	"github.com/weaveworks/scope/probe/overlay"
 	"github.com/weaveworks/scope/probe/process"
@@ -61,42 +64,55 @@ type NodeSummary struct {
 	Metrics   []report.MetricRow   `json:"metrics,omitempty"`
 	Tables    []report.Table       `json:"tables,omitempty"`
 	Adjacency report.IDList        `json:"adjacency,omitempty"`
+	Edges     report.EdgeMetadatas `json:"edges,omitempty"`
 }
 
 var renderers = map[string]func(BasicNodeSummary, report.Node) BasicNodeSummary{
-	render.Pseudo:         pseudoNodeSummary,
-	report.Process:        processNodeSummary,
-	report.Container:      containerNodeSummary,
-	report.ContainerImage: containerImageNodeSummary,
-	report.Pod:            podNodeSummary,
-	report.Service:        podGroupNodeSummary,
-	report.Deployment:     podGroupNodeSummary,
-	report.DaemonSet:      podGroupNodeSummary,
-	report.StatefulSet:    podGroupNodeSummary,
-	report.CronJob:        podGroupNodeSummary,
-	report.ECSTask:        ecsTaskNodeSummary,
-	report.ECSService:     ecsServiceNodeSummary,
-	report.SwarmService:   swarmServiceNodeSummary,
-	report.Host:           hostNodeSummary,
-	report.Overlay:        weaveNodeSummary,
-	report.Endpoint:       nil, // Do not render
+	render.Pseudo:                pseudoNodeSummary,
+	report.Process:               processNodeSummary,
+	report.Container:             containerNodeSummary,
+	report.ContainerImage:        containerImageNodeSummary,
+	report.Pod:                   podNodeSummary,
+	report.Service:               podGroupNodeSummary,
+	report.Deployment:            podGroupNodeSummary,
+	report.DaemonSet:             podGroupNodeSummary,
+	report.StatefulSet:           podGroupNodeSummary,
+	report.CronJob:               podGroupNodeSummary,
+	report.Job:                   podGroupNodeSummary,
+	report.Ingress:               ingressNodeSummary,
+	report.PersistentVolumeClaim: persistentVolumeClaimNodeSummary,
+	report.PersistentVolume:      persistentVolumeNodeSummary,
+	report.StorageClass:          storageClassNodeSummary,
+	report.ECSTask:               ecsTaskNodeSummary,
+	report.ECSService:            ecsServiceNodeSummary,
+	report.SwarmService:          swarmServiceNodeSummary,
+	report.Host:                  hostNodeSummary,
+	report.KubernetesNode:        kubernetesNodeSummary,
+	report.Overlay:               weaveNodeSummary,
+	report.Endpoint:              nil, // Do not render
 }
 
 // For each report.Topology, map to a 'primary' API topology. This can then be used in a variety of places.
 var primaryAPITopology = map[string]string{
-	report.Process:        "processes",
-	report.Container:      "containers",
-	report.ContainerImage: "containers-by-image",
-	report.Pod:            "pods",
-	report.Deployment:     "kube-controllers",
-	report.DaemonSet:      "kube-controllers",
-	report.StatefulSet:    "kube-controllers",
-	report.CronJob:        "kube-controllers",
-	report.Service:        "services",
-	report.ECSTask:        "ecs-tasks",
-	report.ECSService:     "ecs-services",
-	report.SwarmService:   "swarm-services",
-	report.Host:           "hosts",
+	report.Process:               "processes",
+	report.Container:             "containers",
+	report.ContainerImage:        "containers-by-image",
+	report.Pod:                   "pods",
+	report.Deployment:            "kube-controllers",
+	report.DaemonSet:             "kube-controllers",
+	report.StatefulSet:           "kube-controllers",
+	report.CronJob:               "kube-controllers",
+	report.Job:                   "kube-controllers",
+	report.Service:               "services",
+	report.Ingress:               "ingresses",
+	report.PersistentVolumeClaim: "volumes",
+	report.PersistentVolume:      "volumes",
+	report.StorageClass:          "volumes",
+	report.ECSTask:               "ecs-tasks",
+	report.ECSService:            "ecs-services",
+	report.SwarmService:          "swarm-services",
+	report.Host:                  "hosts",
+	report.KubernetesNode:        "kube-nodes",
 }
 
 // MakeBasicNodeSummary returns a basic summary of a node, if
@@ -146,17 +162,126 @@ func MakeNodeSummary(rc RenderContext, n report.Node) (NodeSummary, bool) {
 		Parents:          Parents(rc.Report, n),
 		Adjacency:        n.Adjacency,
 	}
+	if n.Edges.Size() > 0 {
+		summary.Edges = n.Edges
+	}
 	// Only include metadata, metrics, tables when it's not a group node
 	if _, ok := n.Counters.Lookup(n.Topology); !ok {
 		if topology, ok := rc.Topology(n.Topology); ok {
 			summary.Metadata = topology.MetadataTemplates.MetadataRows(n)
 			summary.Metrics = topology.MetricTemplates.MetricRows(n)
 			summary.Tables = topology.TableTemplates.Tables(n)
+			sortEventRows(summary.Tables)
+		}
+		if n.Topology != report.Process {
+			if table, ok := listeningSocketsTable(rc, n); ok {
+				summary.Tables = append(summary.Tables, table)
+			}
+		}
+		if n.Topology != report.Process {
+			summary.Metrics = append(summary.Metrics, aggregateMetricRows(n, report.Process, process.IOMetricTemplates, nil)...)
+		}
+		if n.Topology != report.Endpoint {
+			summary.Metrics = append(summary.Metrics, aggregateMetricRows(n, report.Endpoint, endpoint.HTTPMetricTemplates, endpoint.HTTPLatencyMetrics)...)
 		}
 	}
 	return RenderMetricURLs(summary, n, rc.Report, rc.MetricsGraphURL), true
 }
 
+// sortEventRows puts the most recent Kubernetes events first, as their
+// rows are keyed by event rather than by time.
+func sortEventRows(tables []report.Table) {
+	for _, table := range tables {
+		if table.ID != kubernetes.EventsPrefix {
+			continue
+		}
+		rows := table.Rows
+		sort.SliceStable(rows, func(i, j int) bool {
+			ti, _ := time.Parse(time.RFC3339Nano, rows[i].Entries[kubernetes.EventTime])
+			tj, _ := time.Parse(time.RFC3339Nano, rows[j].Entries[kubernetes.EventTime])
+			return ti.After(tj)
+		})
+	}
+}
+
+// listeningSocketsTable gathers the sockets the processes of a node
+// listen on into a single table, as shown for the processes themselves.
+func listeningSocketsTable(rc RenderContext, n report.Node) (report.Table, bool) {
+	processes, ok := rc.Topology(report.Process)
+	if !ok {
+		return report.Table{}, false
+	}
+	template, ok := processes.TableTemplates[process.ListeningSocketsTablePrefix]
+	if !ok {
+		return report.Table{}, false
+	}
+	rowsByID := map[string]report.Row{}
+	n.Children.ForEach(func(child report.Node) {
+		if child.Topology != report.Process {
+			return
+		}
+		for _, row := range child.ExtractMulticolumnTable(template) {
+			rowsByID[row.ID] = row
+		}
+	})
+	if len(rowsByID) == 0 {
+		return report.Table{}, false
+	}
+	rows := make([]report.Row, 0, len(rowsByID))
+	for _, row := range rowsByID {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return report.Table{
+		ID:      template.ID,
+		Label:   template.Label,
+		Type:    template.Type,
+		Columns: template.Columns,
+		Rows:    rows,
+	}, true
+}
+
+// aggregateMetricRows gathers the metrics of templates of the children
+// of a node in topology, adding them up, except for the ones in highest
+// which are the highest of the children.
+func aggregateMetricRows(n report.Node, topology string, templates report.MetricTemplates, highest map[string]struct{}) []report.MetricRow {
+	var (
+		values = map[string]float64{}
+		last   time.Time
+	)
+	n.Children.ForEach(func(child report.Node) {
+		if child.Topology != topology {
+			return
+		}
+		for id := range templates {
+			metric, ok := child.Metrics.Lookup(id)
+			if !ok {
+				continue
+			}
+			sample, ok := metric.LastSample()
+			if !ok {
+				continue
+			}
+			if _, max := highest[id]; !max {
+				values[id] += sample.Value
+			} else if value, ok := values[id]; !ok || sample.Value > value {
+				values[id] = sample.Value
+			}
+			if sample.Timestamp.After(last) {
+				last = sample.Timestamp
+			}
+		}
+	})
+	if len(values) == 0 {
+		return nil
+	}
+	metrics := report.Metrics{}
+	for id, value := range values {
+		metrics[id] = report.MakeSingletonMetric(last, value)
+	}
+	return templates.MetricRows(report.MakeNode(n.ID).WithMetrics(metrics))
+}
+
 // SummarizeMetrics returns a copy of the NodeSummary where the metrics are
 // replaced with their summaries
 func (n NodeSummary) SummarizeMetrics() NodeSummary {
@@ -306,6 +431,7 @@ var podGroupNodeTypeName = map[string]string{
 	report.DaemonSet:   "DaemonSet",
 	report.StatefulSet: "StatefulSet",
 	report.CronJob:     "CronJob",
+	report.Job:         "Job",
 }
 
 func podGroupNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
@@ -322,6 +448,34 @@ func podGroupNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary
 	return base
 }
 
+func ingressNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
+	base = addKubernetesLabelAndRank(base, n)
+	if hosts, ok := n.Latest.Lookup(kubernetes.IngressHosts); ok {
+		base.LabelMinor = hosts
+	} else {
+		base.LabelMinor = "Ingress"
+	}
+	return base
+}
+
+func persistentVolumeClaimNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
+	base = addKubernetesLabelAndRank(base, n)
+	base.LabelMinor, _ = n.Latest.Lookup(kubernetes.State)
+	return base
+}
+
+func persistentVolumeNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
+	base = addKubernetesLabelAndRank(base, n)
+	base.LabelMinor, _ = n.Latest.Lookup(kubernetes.StorageCapacity)
+	return base
+}
+
+func storageClassNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
+	base = addKubernetesLabelAndRank(base, n)
+	base.LabelMinor, _ = n.Latest.Lookup(kubernetes.Provisioner)
+	return base
+}
+
 func ecsTaskNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
 	base.Label, _ = n.Latest.Lookup(awsecs.TaskFamily)
 	if base.Label == "" {
@@ -357,6 +511,12 @@ func hostNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
 	return base
 }
 
+func kubernetesNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
+	base = addKubernetesLabelAndRank(base, n)
+	base.LabelMinor, _ = n.Latest.Lookup(kubernetes.State)
+	return base
+}
+
 func weaveNodeSummary(base BasicNodeSummary, n report.Node) BasicNodeSummary {
 	var (
 		nickname, _ = n.Latest.Lookup(overlay.WeavePeerNickName)
@@ -8,7 +8,9 @@ import (
 	"github.com/weaveworks/common/mtime"
 	"github.com/weaveworks/common/test"
 	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/probe/endpoint"
 	"github.com/weaveworks/scope/probe/host"
+	"github.com/weaveworks/scope/probe/kubernetes"
 	"github.com/weaveworks/scope/probe/process"
 	"github.com/weaveworks/scope/render"
 	"github.com/weaveworks/scope/render/detailed"
@@ -375,6 +377,47 @@ func TestNodeMetrics(t *testing.T) {
 	}
 }
 
+func TestNodeHTTPMetrics(t *testing.T) {
+	now := time.Now()
+	rpt := report.MakeReport()
+	rpt.Endpoint = rpt.Endpoint.WithMetricTemplates(endpoint.HTTPMetricTemplates)
+	httpEndpoint := func(id string, requests, latency float64) report.Node {
+		return report.MakeNode(id).WithTopology(report.Endpoint).WithMetrics(report.Metrics{
+			endpoint.HTTPRequestRate: report.MakeSingletonMetric(now, requests),
+			endpoint.HTTPLatencyP99:  report.MakeSingletonMetric(now, latency),
+		})
+	}
+	node := report.MakeNode(fixture.ServerContainerNodeID).WithTopology(report.Container).WithChildren(report.MakeNodeSet(
+		httpEndpoint(fixture.Server80NodeID, 10, 25),
+		httpEndpoint(fixture.Server80NodeID+"1", 5, 50),
+	))
+
+	var (
+		requests = report.MakeSingletonMetric(now, 15)
+		latency  = report.MakeSingletonMetric(now, 50)
+		want     = []report.MetricRow{
+			{
+				ID:       endpoint.HTTPRequestRate,
+				Label:    "HTTP requests/s",
+				Value:    15,
+				Priority: 10,
+				Metric:   &requests,
+			},
+			{
+				ID:       endpoint.HTTPLatencyP99,
+				Label:    "HTTP latency p99 (ms)",
+				Value:    50,
+				Priority: 14,
+				Metric:   &latency,
+			},
+		}
+	)
+	summary, _ := detailed.MakeNodeSummary(detailed.RenderContext{Report: rpt}, node)
+	if have := summary.Metrics; !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
 func TestMetricRowSummary(t *testing.T) {
 	var (
 		now    = time.Now()
@@ -426,6 +469,13 @@ func TestNodeTables(t *testing.T) {
 				Add(docker.ContainerIPs, report.MakeStringSet("10.10.10.0/24", "10.10.10.1/24")),
 			),
 			want: []report.Table{
+				{
+					ID:      docker.ContainerEventsPrefix,
+					Type:    report.MulticolumnTableType,
+					Label:   "Events",
+					Columns: docker.ContainerTableTemplates[docker.ContainerEventsPrefix].Columns,
+					Rows:    []report.Row{},
+				},
 				{
 					ID:    docker.EnvPrefix,
 					Type:  report.PropertyListType,
@@ -454,6 +504,63 @@ func TestNodeTables(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "container with listening processes",
+			rpt: report.Report{
+				Container: report.MakeTopology(),
+				Process: report.MakeTopology().
+					WithTableTemplates(process.TableTemplates),
+			},
+			node: report.MakeNodeWith(fixture.ServerContainerNodeID, map[string]string{
+				docker.ContainerID: fixture.ServerContainerID,
+			}).WithTopology(report.Container).WithChildren(report.MakeNodeSet(
+				report.MakeNode(fixture.ServerProcessNodeID).WithTopology(report.Process).
+					AddPrefixMulticolumnTable(process.ListeningSocketsTablePrefix, []report.Row{
+						process.ListeningSocketRow("tcp", "0.0.0.0", 80),
+						process.ListeningSocketRow("udp", "::", 53),
+					}),
+				report.MakeNode(fixture.NonContainerProcessNodeID).WithTopology(report.Process).
+					AddPrefixMulticolumnTable(process.ListeningSocketsTablePrefix, []report.Row{
+						process.ListeningSocketRow("tcp", "0.0.0.0", 80),
+					}),
+			)),
+			want: []report.Table{
+				{
+					ID:      process.ListeningSocketsTablePrefix,
+					Type:    report.MulticolumnTableType,
+					Label:   "Listening sockets",
+					Columns: process.TableTemplates[process.ListeningSocketsTablePrefix].Columns,
+					Rows: []report.Row{
+						process.ListeningSocketRow("tcp", "0.0.0.0", 80),
+						process.ListeningSocketRow("udp", "::", 53),
+					},
+				},
+			},
+		},
+		{
+			name: "pod events",
+			rpt: report.Report{
+				Pod: report.MakeTopology().
+					WithTableTemplates(kubernetes.EventTableTemplates),
+			},
+			node: report.MakeNode(fixture.ClientPodNodeID).WithTopology(report.Pod).
+				AddPrefixMulticolumnTable(kubernetes.EventsPrefix, []report.Row{
+					{ID: "a", Entries: map[string]string{kubernetes.EventTime: "2018-01-01T12:00:00Z", kubernetes.EventReason: "Pulled"}},
+					{ID: "b", Entries: map[string]string{kubernetes.EventTime: "2018-01-01T12:00:00.5Z", kubernetes.EventReason: "BackOff"}},
+				}),
+			want: []report.Table{
+				{
+					ID:      kubernetes.EventsPrefix,
+					Type:    report.MulticolumnTableType,
+					Label:   "Events",
+					Columns: kubernetes.EventTableTemplates[kubernetes.EventsPrefix].Columns,
+					Rows: []report.Row{
+						{ID: "b", Entries: map[string]string{kubernetes.EventTime: "2018-01-01T12:00:00.5Z", kubernetes.EventReason: "BackOff"}},
+						{ID: "a", Entries: map[string]string{kubernetes.EventTime: "2018-01-01T12:00:00Z", kubernetes.EventReason: "Pulled"}},
+					},
+				},
+			},
+		},
 		{
 			name: "unknown topology",
 			rpt:  report.MakeReport(),
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/weaveworks/scope/report"
+)
+
+// Graphviz equivalents of the node shapes used in the UI
+var dotShapes = map[string]string{
+	report.Circle:   "circle",
+	report.Triangle: "triangle",
+	report.Square:   "square",
+	report.Pentagon: "pentagon",
+	report.Hexagon:  "hexagon",
+	report.Heptagon: "septagon",
+	report.Octagon:  "octagon",
+	report.Cloud:    "ellipse",
+}
+
+func writeDOT(w io.Writer, g graph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(g.id))
+	for _, n := range g.nodes {
+		attrs := nodeAttributes(n)
+		for i, a := range attrs {
+			if a.key != "shape" {
+				continue
+			}
+			// Keep the original shape around, as not all of them
+			// have an exact equivalent.
+			attrs[i].value = dotShapes[n.Shape]
+			if attrs[i].value == "" {
+				attrs[i].value = "ellipse"
+			}
+			attrs = append(attrs, attribute{"scope_shape", n.Shape})
+			break
+		}
+		fmt.Fprintf(bw, "\t%s%s;\n", dotQuote(n.ID), dotAttributes(attrs))
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(bw, "\t%s -> %s%s;\n", dotQuote(e.source), dotQuote(e.target), dotAttributes(edgeAttributes(e)))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func dotAttributes(attrs []attribute) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	result := " ["
+	for i, a := range attrs {
+		if i > 0 {
+			result += ", "
+		}
+		switch v := a.value.(type) {
+		case string:
+			result += fmt.Sprintf("%s=%s", a.key, dotQuote(v))
+		default:
+			result += fmt.Sprintf("%s=%v", a.key, v)
+		}
+	}
+	return result + "]"
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// dotQuote quotes s as a DOT string, in which only quotes and
+// backslashes need escaping; other characters are written as they are.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,124 @@
+// Package export writes rendered topologies as standard graph documents,
+// such that they can be loaded into graph drawing and analysis tools.
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/weaveworks/scope/render/detailed"
+	"github.com/weaveworks/scope/report"
+)
+
+// Supported graph formats
+const (
+	DOT     = "dot"
+	GraphML = "graphml"
+	JGF     = "jgf"
+)
+
+var contentTypes = map[string]string{
+	DOT:     "text/vnd.graphviz",
+	GraphML: "application/graphml+xml",
+	JGF:     "application/vnd.jgf+json",
+}
+
+var writers = map[string]func(io.Writer, graph) error{
+	DOT:     writeDOT,
+	GraphML: writeGraphML,
+	JGF:     writeJGF,
+}
+
+// ContentType returns the MIME type of format, and whether the format
+// is supported at all.
+func ContentType(format string) (string, bool) {
+	contentType, ok := contentTypes[format]
+	return contentType, ok
+}
+
+// Write writes the rendered nodes of a topology to w, in the given format.
+func Write(w io.Writer, format, topologyID string, nodes detailed.NodeSummaries) error {
+	write, ok := writers[format]
+	if !ok {
+		return fmt.Errorf("unknown graph format: %q", format)
+	}
+	return write(w, makeGraph(topologyID, nodes))
+}
+
+// graph is the format-independent form of a topology: nodes and edges
+// in a stable order, with their attributes.
+type graph struct {
+	id    string
+	nodes []detailed.NodeSummary
+	edges []edge
+}
+
+type edge struct {
+	source, target string
+	metadata       report.EdgeMetadata
+}
+
+func makeGraph(topologyID string, nodes detailed.NodeSummaries) graph {
+	g := graph{id: topologyID}
+	for _, n := range nodes {
+		g.nodes = append(g.nodes, n)
+	}
+	sort.Sort(byID(g.nodes))
+	for _, n := range g.nodes {
+		for _, dst := range n.Adjacency {
+			// Edges must not dangle, or most tools will refuse the document
+			if _, ok := nodes[dst]; !ok {
+				continue
+			}
+			md, _ := n.Edges.Lookup(dst)
+			g.edges = append(g.edges, edge{source: n.ID, target: dst, metadata: md})
+		}
+	}
+	return g
+}
+
+type byID []detailed.NodeSummary
+
+func (s byID) Len() int           { return len(s) }
+func (s byID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+
+// attribute is a named value attached to a node or an edge.
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+func nodeAttributes(n detailed.NodeSummary) []attribute {
+	attrs := []attribute{{"label", n.Label}}
+	if n.LabelMinor != "" {
+		attrs = append(attrs, attribute{"label_minor", n.LabelMinor})
+	}
+	if n.Rank != "" {
+		attrs = append(attrs, attribute{"rank", n.Rank})
+	}
+	if n.Shape != "" {
+		attrs = append(attrs, attribute{"shape", n.Shape})
+	}
+	if n.Pseudo {
+		attrs = append(attrs, attribute{"pseudo", true})
+	}
+	return attrs
+}
+
+func edgeAttributes(e edge) []attribute {
+	attrs := []attribute{}
+	for _, a := range []attribute{
+		{"connections", e.metadata.Connections},
+		{"egress_packet_count", e.metadata.EgressPacketCount},
+		{"ingress_packet_count", e.metadata.IngressPacketCount},
+		{"egress_byte_count", e.metadata.EgressByteCount},
+		{"ingress_byte_count", e.metadata.IngressByteCount},
+	} {
+		if a.value.(uint64) != 0 {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
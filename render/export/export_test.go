@@ -0,0 +1,120 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/ugorji/go/codec"
+
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/render/detailed"
+	"github.com/weaveworks/scope/render/export"
+	"github.com/weaveworks/scope/report"
+)
+
+var nodes = detailed.NodeSummaries{
+	"a": {
+		BasicNodeSummary: detailed.BasicNodeSummary{ID: "a", Label: "apache", LabelMinor: "server", Rank: "apache", Shape: report.Heptagon},
+		Adjacency:        report.MakeIDList("b", "gone"),
+		Edges:            report.MakeEdgeMetadatas().Add("b", report.EdgeMetadata{Connections: 2, EgressByteCount: 10}),
+	},
+	"b": {
+		BasicNodeSummary: detailed.BasicNodeSummary{ID: "b", Label: "The Internet", Shape: report.Cloud, Pseudo: true},
+	},
+}
+
+func TestWriteDOT(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := export.Write(buf, export.DOT, "processes", nodes); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "processes" {
+	"a" [label="apache", label_minor="server", rank="apache", shape="septagon", scope_shape="heptagon"];
+	"b" [label="The Internet", shape="ellipse", pseudo=true, scope_shape="cloud"];
+	"a" -> "b" [connections=2, egress_byte_count=10];
+}
+`
+	if have := buf.String(); want != have {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestWriteDOTQuoting(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := export.Write(buf, export.DOT, "processes", detailed.NodeSummaries{
+		"c": {BasicNodeSummary: detailed.BasicNodeSummary{ID: `c;"é"\x`, Label: `café\tbar`}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "processes" {
+	"c;\"é\"\\x" [label="café\\tbar"];
+}
+`
+	if have := buf.String(); want != have {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := export.Write(buf, export.GraphML, "processes", nodes); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Graph.Nodes) != 2 || doc.Graph.Nodes[0].ID != "a" || doc.Graph.Nodes[1].ID != "b" {
+		t.Errorf("Unexpected nodes: %v", doc.Graph.Nodes)
+	}
+	// The edge to the missing node must be dropped
+	if len(doc.Graph.Edges) != 1 || doc.Graph.Edges[0].Source != "a" || doc.Graph.Edges[0].Target != "b" {
+		t.Errorf("Unexpected edges: %v", doc.Graph.Edges)
+	}
+}
+
+func TestWriteJGF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := export.Write(buf, export.JGF, "processes", nodes); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID    string `json:"id"`
+				Label string `json:"label"`
+			} `json:"nodes"`
+			Edges []struct {
+				Source   string                 `json:"source"`
+				Target   string                 `json:"target"`
+				Metadata map[string]interface{} `json:"metadata"`
+			} `json:"edges"`
+		} `json:"graph"`
+	}
+	if err := codec.NewDecoder(buf, &codec.JsonHandle{}).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Graph.Nodes) != 2 || doc.Graph.Nodes[1].Label != "The Internet" {
+		t.Errorf("Unexpected nodes: %v", doc.Graph.Nodes)
+	}
+	if len(doc.Graph.Edges) != 1 || doc.Graph.Edges[0].Metadata["connections"] != uint64(2) {
+		t.Errorf("Unexpected edges: %v", doc.Graph.Edges)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := export.Write(&bytes.Buffer{}, "foo", "processes", nodes); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,100 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// All the attributes nodeAttributes and edgeAttributes can produce
+var graphMLKeys = []graphMLKey{
+	{"label", "node", "label", "string"},
+	{"label_minor", "node", "label_minor", "string"},
+	{"rank", "node", "rank", "string"},
+	{"shape", "node", "shape", "string"},
+	{"pseudo", "node", "pseudo", "boolean"},
+	{"connections", "edge", "connections", "long"},
+	{"egress_packet_count", "edge", "egress_packet_count", "long"},
+	{"ingress_packet_count", "edge", "ingress_packet_count", "long"},
+	{"egress_byte_count", "edge", "egress_byte_count", "long"},
+	{"ingress_byte_count", "edge", "ingress_byte_count", "long"},
+}
+
+func graphMLAttributes(attrs []attribute) []graphMLData {
+	data := make([]graphMLData, 0, len(attrs))
+	for _, a := range attrs {
+		data = append(data, graphMLData{Key: a.key, Value: fmt.Sprint(a.value)})
+	}
+	return data
+}
+
+func writeGraphML(w io.Writer, g graph) error {
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  graphMLKeys,
+		Graph: graphMLGraph{
+			ID:          g.id,
+			EdgeDefault: "directed",
+		},
+	}
+	for _, n := range g.nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   n.ID,
+			Data: graphMLAttributes(nodeAttributes(n)),
+		})
+	}
+	for _, e := range g.edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: e.source,
+			Target: e.target,
+			Data:   graphMLAttributes(edgeAttributes(e)),
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
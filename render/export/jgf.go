@@ -0,0 +1,76 @@
+package export
+
+import (
+	"io"
+
+	"github.com/ugorji/go/codec"
+)
+
+// See http://jsongraphformat.info/
+type jgfDocument struct {
+	Graph jgfGraph `json:"graph"`
+}
+
+type jgfGraph struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Directed bool      `json:"directed"`
+	Nodes    []jgfNode `json:"nodes"`
+	Edges    []jgfEdge `json:"edges"`
+}
+
+type jgfNode struct {
+	ID       string                 `json:"id"`
+	Label    string                 `json:"label"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type jgfEdge struct {
+	Source   string                 `json:"source"`
+	Target   string                 `json:"target"`
+	Directed bool                   `json:"directed"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func jgfMetadata(attrs []attribute) map[string]interface{} {
+	if len(attrs) == 0 {
+		return nil
+	}
+	metadata := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		metadata[a.key] = a.value
+	}
+	return metadata
+}
+
+func writeJGF(w io.Writer, g graph) error {
+	doc := jgfDocument{
+		Graph: jgfGraph{
+			ID:       g.id,
+			Type:     "scope",
+			Directed: true,
+			Nodes:    []jgfNode{},
+			Edges:    []jgfEdge{},
+		},
+	}
+	for _, n := range g.nodes {
+		attrs := nodeAttributes(n)
+		// the label is a first-class field in JGF
+		doc.Graph.Nodes = append(doc.Graph.Nodes, jgfNode{
+			ID:       n.ID,
+			Label:    n.Label,
+			Metadata: jgfMetadata(attrs[1:]),
+		})
+	}
+	for _, e := range g.edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, jgfEdge{
+			Source:   e.source,
+			Target:   e.target,
+			Directed: true,
+			Metadata: jgfMetadata(edgeAttributes(e)),
+		})
+	}
+	handle := &codec.JsonHandle{}
+	handle.Canonical = true // stable metadata order
+	return codec.NewEncoder(w, handle).Encode(doc)
+}
@@ -0,0 +1,292 @@
+// Package expr compiles filter expressions over node fields into
+// render.FilterFuncs, e.g.
+//
+//	image =~ "redis.*" and cpu > 50
+//	topology = container and not label.works.weave.role = system
+//
+// Expressions combine comparisons with and, or, not and parentheses.
+// The operators are = and != (string equality), =~ and !~ (regular
+// expression match, unanchored), and <, <=, > and >= (numeric). != and
+// !~ only match nodes which have the field; use not to also match the
+// ones without it. A field on its own matches nodes which have it. See
+// resolveField for fields.
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/weaveworks/scope/render"
+	"github.com/weaveworks/scope/report"
+)
+
+// Compile parses an expression into a FilterFunc.
+func Compile(s string) (render.FilterFunc, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+	}
+	return f, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.value)
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-/:", r)
+}
+
+var operators = []string{"=~", "!~", "!=", "<=", ">=", "=", "<", ">"}
+
+func lex(s string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(s)
+	)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var value []rune
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value = append(value, runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			tokens = append(tokens, token{tokString, string(value), start})
+			i++
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i++; i < len(runes) && isIdentRune(runes[i]); i++ {
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[start:i]), start})
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{tokOp, op, i})
+					i += len([]rune(op))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected %q at offset %d", r, i)
+			}
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && strings.EqualFold(tok.value, keyword)
+}
+
+func (p *parser) parseOr() (render.FilterFunc, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	fs := []render.FilterFunc{f}
+	for p.isKeyword("or") {
+		p.next()
+		f, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	if len(fs) == 1 {
+		return fs[0], nil
+	}
+	return render.AnyFilterFunc(fs...), nil
+}
+
+func (p *parser) parseAnd() (render.FilterFunc, error) {
+	f, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	fs := []render.FilterFunc{f}
+	for p.isKeyword("and") {
+		p.next()
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	if len(fs) == 1 {
+		return fs[0], nil
+	}
+	return render.ComposeFilterFuncs(fs...), nil
+}
+
+func (p *parser) parseNot() (render.FilterFunc, error) {
+	if p.isKeyword("not") {
+		p.next()
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return render.Complement(f), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (render.FilterFunc, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, fmt.Errorf("expected \")\" at offset %d, got %s", tok.pos, tok)
+		}
+		return f, nil
+	case tokIdent:
+		field := resolveField(tok.value)
+		if p.peek().kind != tokOp {
+			return func(n report.Node) bool {
+				return len(field(n)) > 0
+			}, nil
+		}
+		op := p.next()
+		value := p.next()
+		if value.kind != tokIdent && value.kind != tokString && value.kind != tokNumber {
+			return nil, fmt.Errorf("expected a value at offset %d, got %s", value.pos, value)
+		}
+		return compare(field, op.value, value.value)
+	}
+	return nil, fmt.Errorf("expected a field at offset %d, got %s", tok.pos, tok)
+}
+
+// compare returns a FilterFunc matching nodes for which any value of
+// the field compares successfully with value. For != and !~, nodes
+// must have the field, and none of its values may match.
+func compare(field fieldFunc, op, value string) (render.FilterFunc, error) {
+	var match func(string) bool
+	switch op {
+	case "=", "!=":
+		match = func(v string) bool { return v == value }
+	case "=~", "!~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		match = re.MatchString
+	default:
+		want, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number, got %q", op, value)
+		}
+		match = func(v string) bool {
+			have, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return false
+			}
+			switch op {
+			case "<":
+				return have < want
+			case "<=":
+				return have <= want
+			case ">":
+				return have > want
+			}
+			return have >= want
+		}
+	}
+	if op == "!=" || op == "!~" {
+		return func(n report.Node) bool {
+			values := field(n)
+			for _, v := range values {
+				if match(v) {
+					return false
+				}
+			}
+			return len(values) > 0
+		}, nil
+	}
+	return func(n report.Node) bool {
+		for _, v := range field(n) {
+			if match(v) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,106 @@
+package expr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/probe/kubernetes"
+	"github.com/weaveworks/scope/probe/process"
+	"github.com/weaveworks/scope/render/expr"
+	"github.com/weaveworks/scope/report"
+)
+
+var (
+	now   = time.Now()
+	redis = report.MakeNodeWith("redis", map[string]string{
+		docker.ImageName:                 "redis:3.2",
+		docker.ContainerName:             "cache",
+		docker.LabelPrefix + "app":       "cache",
+		kubernetes.LabelPrefix + "owner": "ops",
+	}).WithTopology(report.Container).
+		WithMetrics(report.Metrics{
+			docker.CPUTotalUsage: report.MakeSingletonMetric(now, 75),
+		}).
+		WithSets(report.MakeSets().Add("ports", report.MakeStringSet("6379/tcp")))
+	nginx = report.MakeNodeWith("nginx", map[string]string{
+		docker.ImageName:     "nginx:1.13",
+		docker.ContainerName: "web",
+	}).WithTopology(report.Container).
+		WithMetrics(report.Metrics{
+			docker.CPUTotalUsage: report.MakeSingletonMetric(now, 10),
+		})
+	sh = report.MakeNodeWith("sh", map[string]string{
+		process.Name: "sh",
+	}).WithTopology(report.Process).
+		WithMetrics(report.Metrics{
+			process.CPUUsage: report.MakeSingletonMetric(now, 60),
+		})
+)
+
+func TestCompile(t *testing.T) {
+	for _, c := range []struct {
+		expr string
+		want []string
+	}{
+		{`image =~ "redis.*" and cpu > 50`, []string{"redis"}},
+		{`cpu > 50`, []string{"redis", "sh"}},
+		{`cpu <= 10`, []string{"nginx"}},
+		{`topology = container`, []string{"redis", "nginx"}},
+		{`topology != container`, []string{"sh"}},
+		{`name = web or name = sh`, []string{"nginx", "sh"}},
+		{`not (name = web or name = sh)`, []string{"redis"}},
+		{`label.app = cache`, []string{"redis"}},
+		{`label.owner = 'ops'`, []string{"redis"}},
+		{`label.app`, []string{"redis"}},
+		{`not label.app`, []string{"nginx", "sh"}},
+		{`image !~ "^redis"`, []string{"nginx"}},
+		{`not image =~ "^redis"`, []string{"nginx", "sh"}},
+		{`label.app != foo`, []string{"redis"}},
+		{`set.ports = "6379/tcp"`, []string{"redis"}},
+		{`ports = "6379/tcp"`, []string{"redis"}},
+		{`metric.docker_cpu_total_usage >= 75`, []string{"redis"}},
+		{`docker_container_name = cache AND topology = container`, []string{"redis"}},
+		{`id = sh or id = nginx and cpu > 50`, []string{"sh"}},
+	} {
+		f, err := expr.Compile(c.expr)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		have := map[string]bool{}
+		for _, n := range []report.Node{redis, nginx, sh} {
+			if f(n) {
+				have[n.ID] = true
+			}
+		}
+		if len(have) != len(c.want) {
+			t.Errorf("%s: expected %v, got %v", c.expr, c.want, have)
+			continue
+		}
+		for _, id := range c.want {
+			if !have[id] {
+				t.Errorf("%s: expected %v, got %v", c.expr, c.want, have)
+				break
+			}
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, s := range []string{
+		``,
+		`image =`,
+		`image = "redis`,
+		`cpu > high`,
+		`image =~ "("`,
+		`(image = redis`,
+		`image = redis)`,
+		`image = redis and`,
+		`image # redis`,
+	} {
+		if _, err := expr.Compile(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
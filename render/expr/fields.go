@@ -0,0 +1,114 @@
+package expr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/weaveworks/scope/probe/docker"
+	"github.com/weaveworks/scope/probe/host"
+	"github.com/weaveworks/scope/probe/kubernetes"
+	"github.com/weaveworks/scope/probe/process"
+	"github.com/weaveworks/scope/report"
+)
+
+// fieldFunc returns the values of a field of a node; nodes without the
+// field have no values.
+type fieldFunc func(report.Node) []string
+
+// Aliases for fields reported under different keys by different
+// topologies.
+var (
+	latestAliases = map[string][]string{
+		"image":     {docker.ImageName},
+		"name":      {docker.ContainerName, process.Name, kubernetes.Name, host.HostName},
+		"namespace": {kubernetes.Namespace},
+	}
+	metricAliases = map[string][]string{
+		"cpu":    {process.CPUUsage, docker.CPUTotalUsage, host.CPUUsage},
+		"memory": {process.MemoryUsage, docker.MemoryUsage, host.MemoryUsage},
+	}
+)
+
+// resolveField returns the fieldFunc for a field name:
+//
+//   - id and topology are the ID and topology of the node;
+//   - label.<key> is a docker or kubernetes label;
+//   - latest.<key>, metric.<key> and set.<key> are a Latest value, the
+//     last sample of a metric, and the values of a set;
+//   - image, name, namespace, cpu and memory alias the keys used by the
+//     different topologies;
+//   - any other name is looked up as a Latest key, metric and set.
+func resolveField(name string) fieldFunc {
+	switch name {
+	case "id":
+		return func(n report.Node) []string { return []string{n.ID} }
+	case "topology":
+		return func(n report.Node) []string { return []string{n.Topology} }
+	}
+	if keys, ok := latestAliases[name]; ok {
+		return latestField(keys...)
+	}
+	if keys, ok := metricAliases[name]; ok {
+		return metricField(keys...)
+	}
+	prefix, key := "", name
+	if i := strings.Index(name, "."); i > 0 {
+		prefix, key = name[:i], name[i+1:]
+	}
+	switch prefix {
+	case "label":
+		return latestField(docker.LabelPrefix+key, kubernetes.LabelPrefix+key)
+	case "latest":
+		return latestField(key)
+	case "metric":
+		return metricField(key)
+	case "set":
+		return setField(key)
+	}
+	return anyField(latestField(name), metricField(name), setField(name))
+}
+
+func latestField(keys ...string) fieldFunc {
+	return func(n report.Node) []string {
+		var values []string
+		for _, key := range keys {
+			if value, ok := n.Latest.Lookup(key); ok {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+}
+
+func metricField(keys ...string) fieldFunc {
+	return func(n report.Node) []string {
+		var values []string
+		for _, key := range keys {
+			metric, ok := n.Metrics[key]
+			if !ok {
+				continue
+			}
+			if sample, ok := metric.LastSample(); ok {
+				values = append(values, strconv.FormatFloat(sample.Value, 'f', -1, 64))
+			}
+		}
+		return values
+	}
+}
+
+func setField(key string) fieldFunc {
+	return func(n report.Node) []string {
+		values, _ := n.Sets.Lookup(key)
+		return values
+	}
+}
+
+func anyField(fields ...fieldFunc) fieldFunc {
+	return func(n report.Node) []string {
+		var values []string
+		for _, field := range fields {
+			values = append(values, field(n)...)
+		}
+		return values
+	}
+}
@@ -74,19 +74,34 @@ func (f FilterFunc) Transform(nodes Nodes) Nodes {
 
 	// Deleted nodes also need to be cut as destinations in adjacency lists.
 	for id, node := range output {
-		newAdjacency := report.MakeIDList()
-		for _, dstID := range node.Adjacency {
-			if _, ok := output[dstID]; ok {
-				newAdjacency = newAdjacency.Add(dstID)
-			}
-		}
-		node.Adjacency = newAdjacency
-		output[id] = node
+		output[id] = pruneAdjacency(node, func(dstID string) bool {
+			_, ok := output[dstID]
+			return ok
+		})
 	}
 
 	return Nodes{Nodes: output, Filtered: filtered}
 }
 
+// pruneAdjacency returns a copy of node with only the edges for which
+// keep returns true.
+func pruneAdjacency(node report.Node, keep func(dstID string) bool) report.Node {
+	newAdjacency := report.MakeIDList()
+	newEdges := report.MakeEdgeMetadatas()
+	for _, dstID := range node.Adjacency {
+		if !keep(dstID) {
+			continue
+		}
+		newAdjacency = newAdjacency.Add(dstID)
+		if md, ok := node.Edges.Lookup(dstID); ok {
+			newEdges = newEdges.Add(dstID, md)
+		}
+	}
+	node.Adjacency = newAdjacency
+	node.Edges = newEdges
+	return node
+}
+
 // Filter removes nodes from a view based on a predicate.
 type Filter struct {
 	Renderer
@@ -153,13 +168,9 @@ func filterInternetAdjacencies(nodes report.Nodes) report.Nodes {
 	if !ok {
 		return nodes
 	}
-	newAdjacency := report.MakeIDList()
-	for _, dstID := range incomingInternet.Adjacency {
-		if dstID != OutgoingInternetID {
-			newAdjacency = newAdjacency.Add(dstID)
-		}
-	}
-	incomingInternet.Adjacency = newAdjacency
+	incomingInternet = pruneAdjacency(incomingInternet, func(dstID string) bool {
+		return dstID != OutgoingInternetID
+	})
 	output := nodes.Copy()
 	output[IncomingInternetID] = incomingInternet
 	return output
@@ -262,6 +273,14 @@ func HasLabel(labelKey string, labelValue string) FilterFunc {
 	}
 }
 
+// HasLatest checks if the node has the given value for key in its Latest
+func HasLatest(key, value string) FilterFunc {
+	return func(n report.Node) bool {
+		v, ok := n.Latest.Lookup(key)
+		return ok && v == value
+	}
+}
+
 // DoesNotHaveLabel checks if the node does NOT have the specified docker label
 func DoesNotHaveLabel(labelKey string, labelValue string) FilterFunc {
 	return Complement(HasLabel(labelKey, labelValue))
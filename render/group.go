@@ -0,0 +1,47 @@
+package render
+
+import (
+	"github.com/weaveworks/scope/report"
+)
+
+// MapGroupBy returns a MapFunc which groups nodes by the value of the
+// first of keys present in their Latest. Nodes with none of the keys
+// are dropped; pseudo nodes are propagated.
+func MapGroupBy(keys ...string) MapFunc {
+	return func(n report.Node) report.Node {
+		if n.Topology == Pseudo {
+			return n
+		}
+		for _, key := range keys {
+			value, ok := n.Latest.Lookup(key)
+			if !ok || value == "" {
+				continue
+			}
+			// Add the node to the counters, which will later be counted
+			// to produce the minor label
+			node := NewDerivedNode(value, n).WithTopology(MakeGroupNodeTopology(n.Topology, keys[0]))
+			node.Counters = node.Counters.Add(n.Topology, 1)
+			return node
+		}
+		return report.Node{}
+	}
+}
+
+// AnyGroupMember returns a FilterFunc which applies f to the members of
+// group nodes, keeping the group if any member matches. Other nodes are
+// tested against f directly.
+func AnyGroupMember(f FilterFunc) FilterFunc {
+	return func(n report.Node) bool {
+		topology, _, ok := ParseGroupNodeTopology(n.Topology)
+		if !ok {
+			return f(n)
+		}
+		found := false
+		n.Children.ForEach(func(child report.Node) {
+			if !found && child.Topology == topology && f(child) {
+				found = true
+			}
+		})
+		return found
+	}
+}
@@ -13,6 +13,7 @@ var HostRenderer = MakeReduce(
 	CustomRenderer{RenderFunc: nodes2Hosts, Renderer: ContainerRenderer},
 	CustomRenderer{RenderFunc: nodes2Hosts, Renderer: ContainerImageRenderer},
 	CustomRenderer{RenderFunc: nodes2Hosts, Renderer: PodRenderer},
+	CustomRenderer{RenderFunc: nodes2Hosts, Renderer: SelectKubernetesNode},
 	MapEndpoints(endpoint2Host, report.Host),
 )
 
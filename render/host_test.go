@@ -6,6 +6,7 @@ import (
 	"github.com/weaveworks/common/test"
 	"github.com/weaveworks/scope/render"
 	"github.com/weaveworks/scope/render/expected"
+	"github.com/weaveworks/scope/report"
 	"github.com/weaveworks/scope/test/fixture"
 	"github.com/weaveworks/scope/test/reflect"
 	"github.com/weaveworks/scope/test/utils"
@@ -18,3 +19,31 @@ func TestHostRenderer(t *testing.T) {
 		t.Error(test.Diff(want, have))
 	}
 }
+
+func TestHostRendererKubernetesNodes(t *testing.T) {
+	var (
+		input        = fixture.Report.Copy()
+		clientNodeID = report.MakeKubernetesNodeID("client")
+		lostNodeID   = report.MakeKubernetesNodeID("lost")
+		lostHostID   = report.MakeHostNodeID("lost.hostname.com")
+	)
+	input.KubernetesNode.AddNode(report.MakeNode(clientNodeID).WithTopology(report.KubernetesNode).
+		WithParents(report.MakeSets().Add(report.Host, report.MakeStringSet(fixture.ClientHostNodeID))))
+	input.KubernetesNode.AddNode(report.MakeNode(lostNodeID).WithTopology(report.KubernetesNode).
+		WithParents(report.MakeSets().Add(report.Host, report.MakeStringSet(lostHostID))))
+
+	have := render.HostRenderer.Render(input).Nodes
+	for hostID, nodeID := range map[string]string{
+		fixture.ClientHostNodeID: clientNodeID,
+		lostHostID:               lostNodeID,
+	} {
+		host, ok := have[hostID]
+		if !ok {
+			t.Errorf("Expected output to have host %q", hostID)
+			continue
+		}
+		if _, ok := host.Children.Lookup(nodeID); !ok {
+			t.Errorf("Expected host %q to have kubernetes node %q as a child", hostID, nodeID)
+		}
+	}
+}
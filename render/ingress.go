@@ -0,0 +1,44 @@
+package render
+
+import (
+	"github.com/weaveworks/scope/probe/kubernetes"
+	"github.com/weaveworks/scope/report"
+)
+
+// IngressRenderer is a Renderer which produces a renderable kubernetes
+// ingress graph: traffic flows from the internet into the ingresses, and
+// from those to the services graph.
+//
+// not memoised
+var IngressRenderer = ConditionalRenderer(renderKubernetesTopologies,
+	MakeReduce(
+		ingressRenderer{},
+		PodServiceRenderer,
+	),
+)
+
+// ingressRenderer renders the ingress topology, with edges from the
+// internet to every ingress, and from each ingress to the services it
+// routes to.
+type ingressRenderer struct{}
+
+// Render implements Renderer
+func (ingressRenderer) Render(rpt report.Report) Nodes {
+	if len(rpt.Ingress.Nodes) == 0 {
+		return Nodes{}
+	}
+	output := make(report.Nodes, len(rpt.Ingress.Nodes)+1)
+	internet := report.MakeNode(IncomingInternetID).WithTopology(Pseudo)
+	for id, n := range rpt.Ingress.Nodes {
+		serviceIDs, _ := n.Sets.Lookup(kubernetes.IngressServices)
+		for _, serviceID := range serviceIDs {
+			if _, ok := rpt.Service.Nodes[serviceID]; ok {
+				n = n.WithAdjacent(serviceID)
+			}
+		}
+		output[id] = n
+		internet = internet.WithAdjacent(id)
+	}
+	output[IncomingInternetID] = internet
+	return Nodes{Nodes: output}
+}
@@ -0,0 +1,59 @@
+package render
+
+import (
+	"github.com/weaveworks/scope/report"
+)
+
+// PodVolumeRenderer is a Renderer which produces a renderable kubernetes
+// storage graph: pods are attached to the persistent volume claims they
+// mount, claims to the volumes they are bound to, and volumes, as well
+// as claims still waiting for one, to their storage classes.
+//
+// not memoised
+var PodVolumeRenderer = ConditionalRenderer(renderKubernetesTopologies,
+	podVolumeRenderer{},
+)
+
+type podVolumeRenderer struct{}
+
+// Render implements Renderer
+func (podVolumeRenderer) Render(rpt report.Report) Nodes {
+	output := report.Nodes{}
+	for id, n := range PodRenderer.Render(rpt).Nodes {
+		claimIDs := parentsIn(n, report.PersistentVolumeClaim, rpt.PersistentVolumeClaim)
+		if len(claimIDs) == 0 {
+			continue
+		}
+		// Connections between pods are shown elsewhere
+		n.Adjacency = report.MakeIDList(claimIDs...)
+		output[id] = n
+	}
+	for id, n := range rpt.PersistentVolumeClaim.Nodes {
+		if volumeIDs := parentsIn(n, report.PersistentVolume, rpt.PersistentVolume); len(volumeIDs) > 0 {
+			n = n.WithAdjacent(volumeIDs...)
+		} else {
+			n = n.WithAdjacent(parentsIn(n, report.StorageClass, rpt.StorageClass)...)
+		}
+		output[id] = n
+	}
+	for id, n := range rpt.PersistentVolume.Nodes {
+		output[id] = n.WithAdjacent(parentsIn(n, report.StorageClass, rpt.StorageClass)...)
+	}
+	for id, n := range rpt.StorageClass.Nodes {
+		output[id] = n
+	}
+	return Nodes{Nodes: output}
+}
+
+// parentsIn returns the IDs of the parents of n in topology which are
+// present in t.
+func parentsIn(n report.Node, topology string, t report.Topology) []string {
+	ids := []string{}
+	parents, _ := n.Parents.Lookup(topology)
+	for _, id := range parents {
+		if _, ok := t.Nodes[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
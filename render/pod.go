@@ -26,6 +26,12 @@ func renderKubernetesTopologies(rpt report.Report) bool {
 		&rpt.DaemonSet,
 		&rpt.StatefulSet,
 		&rpt.CronJob,
+		&rpt.Job,
+		&rpt.Ingress,
+		&rpt.PersistentVolumeClaim,
+		&rpt.PersistentVolume,
+		&rpt.StorageClass,
+		&rpt.KubernetesNode,
 	}
 	for _, t := range topologies {
 		if len(t.Nodes) > 0 {
@@ -83,11 +89,19 @@ var PodServiceRenderer = ConditionalRenderer(renderKubernetesTopologies,
 // not memoised
 var KubeControllerRenderer = ConditionalRenderer(renderKubernetesTopologies,
 	renderParents(
-		report.Pod, []string{report.Deployment, report.DaemonSet, report.StatefulSet, report.CronJob}, UnmanagedID,
+		report.Pod, []string{report.Deployment, report.DaemonSet, report.StatefulSet, report.CronJob, report.Job}, UnmanagedID,
 		PodRenderer,
 	),
 )
 
+// KubernetesNodeRenderer is a Renderer which produces a renderable graph of
+// the nodes of kubernetes clusters, including those without a probe.
+//
+// not memoised
+var KubernetesNodeRenderer = ConditionalRenderer(renderKubernetesTopologies,
+	SelectKubernetesNode,
+)
+
 // renderParents produces a 'standard' renderer for mapping from some child topology to some parent topologies,
 // by taking a child renderer, mapping to parents, propagating single metrics, and joining with full parent topology.
 // Other options are as per Map2Parent.
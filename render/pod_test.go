@@ -7,6 +7,7 @@ import (
 	"github.com/weaveworks/scope/probe/kubernetes"
 	"github.com/weaveworks/scope/render"
 	"github.com/weaveworks/scope/render/expected"
+	"github.com/weaveworks/scope/report"
 	"github.com/weaveworks/scope/test/fixture"
 	"github.com/weaveworks/scope/test/reflect"
 	"github.com/weaveworks/scope/test/utils"
@@ -65,3 +66,67 @@ func TestPodServiceFilterRenderer(t *testing.T) {
 		t.Error(test.Diff(want, have))
 	}
 }
+
+func TestIngressRenderer(t *testing.T) {
+	input := fixture.Report.Copy()
+	ingressID := report.MakeIngressNodeID("ingress1234")
+	input.Ingress.AddNode(report.MakeNode(ingressID).WithTopology(report.Ingress).
+		WithSets(report.MakeSets().Add(kubernetes.IngressServices, report.MakeStringSet(
+			fixture.ServiceNodeID,
+			report.MakeServiceNodeID("unknown"),
+		))))
+
+	have := render.IngressRenderer.Render(input).Nodes
+	ingress, ok := have[ingressID]
+	if !ok {
+		t.Fatalf("Expected output to have ingress %q", ingressID)
+	}
+	if want := report.MakeIDList(fixture.ServiceNodeID); !reflect.DeepEqual(want, ingress.Adjacency) {
+		t.Error(test.Diff(want, ingress.Adjacency))
+	}
+	if internet, ok := have[render.IncomingInternetID]; !ok || !internet.Adjacency.Contains(ingressID) {
+		t.Errorf("Expected the internet to be adjacent to ingress %q", ingressID)
+	}
+	if _, ok := have[fixture.ServiceNodeID]; !ok {
+		t.Errorf("Expected output to have service %q", fixture.ServiceNodeID)
+	}
+}
+
+func TestPodVolumeRenderer(t *testing.T) {
+	var (
+		input   = fixture.Report.Copy()
+		pvcID   = report.MakePersistentVolumeClaimNodeID("pvc1234")
+		pvID    = report.MakePersistentVolumeNodeID("pv1234")
+		classID = report.MakeStorageClassNodeID("class1234")
+	)
+	input.Pod.Nodes[fixture.ClientPodNodeID] = input.Pod.Nodes[fixture.ClientPodNodeID].
+		WithParents(input.Pod.Nodes[fixture.ClientPodNodeID].Parents.Add(report.PersistentVolumeClaim, report.MakeStringSet(pvcID)))
+	input.PersistentVolumeClaim.AddNode(report.MakeNode(pvcID).WithTopology(report.PersistentVolumeClaim).
+		WithParents(report.MakeSets().
+			Add(report.PersistentVolume, report.MakeStringSet(pvID)).
+			Add(report.StorageClass, report.MakeStringSet(classID))))
+	input.PersistentVolume.AddNode(report.MakeNode(pvID).WithTopology(report.PersistentVolume).
+		WithParents(report.MakeSets().Add(report.StorageClass, report.MakeStringSet(classID))))
+	input.StorageClass.AddNode(report.MakeNode(classID).WithTopology(report.StorageClass))
+
+	have := render.PodVolumeRenderer.Render(input).Nodes
+	want := map[string]report.IDList{
+		fixture.ClientPodNodeID: report.MakeIDList(pvcID),
+		pvcID:                   report.MakeIDList(pvID),
+		pvID:                    report.MakeIDList(classID),
+		classID:                 report.MakeIDList(),
+	}
+	if len(have) != len(want) {
+		t.Errorf("Expected %d nodes, got %d", len(want), len(have))
+	}
+	for id, adjacency := range want {
+		node, ok := have[id]
+		if !ok {
+			t.Errorf("Expected output to have node %q", id)
+			continue
+		}
+		if !reflect.DeepEqual(adjacency, node.Adjacency) {
+			t.Errorf("%s: %s", id, test.Diff(adjacency, node.Adjacency))
+		}
+	}
+}
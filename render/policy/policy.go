@@ -0,0 +1,350 @@
+// Package policy generates network policies allowing exactly the flows
+// observed between pods: Kubernetes NetworkPolicies, and iptables rules
+// for the DOCKER-USER chain.
+package policy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/weaveworks/scope/probe/endpoint"
+	"github.com/weaveworks/scope/probe/kubernetes"
+	"github.com/weaveworks/scope/render"
+	"github.com/weaveworks/scope/report"
+)
+
+// Pod is a pod policies are generated for.
+type Pod struct {
+	ID          string
+	Name        string
+	Namespace   string
+	IP          string
+	Labels      map[string]string
+	HostNetwork bool
+}
+
+// Peer is one end of a flow: a pod, or an address outside of any pod.
+type Peer struct {
+	PodID string
+	IP    string
+}
+
+// Flow is a connection observed from Source to a port of Destination.
+type Flow struct {
+	Source      Peer
+	Destination Peer
+	Protocol    apiv1.Protocol
+	Port        int
+}
+
+// Flows accumulates the pods and flows observed in reports, and the
+// labels of the namespaces of the pods, by name.
+type Flows struct {
+	Pods       map[string]Pod
+	Flows      map[Flow]struct{}
+	Namespaces map[string]map[string]string
+}
+
+// MakeFlows makes a new, empty Flows.
+func MakeFlows() Flows {
+	return Flows{
+		Pods:       map[string]Pod{},
+		Flows:      map[Flow]struct{}{},
+		Namespaces: map[string]map[string]string{},
+	}
+}
+
+// Add adds the pods rendered from rpt, and the connections from the
+// endpoint topology of rpt to or from them.
+func (f Flows) Add(rpt report.Report) {
+	for _, n := range rpt.Namespace.Nodes {
+		if name, ok := n.Latest.Lookup(kubernetes.Name); ok {
+			f.Namespaces[name] = nodeLabels(n)
+		}
+	}
+	owners := map[string]string{}
+	for _, n := range render.PodRenderer.Render(rpt).Nodes {
+		if n.Topology != report.Pod {
+			continue
+		}
+		f.Pods[n.ID] = makePod(n)
+		n.Children.ForEach(func(child report.Node) {
+			if child.Topology == report.Endpoint {
+				owners[child.ID] = n.ID
+			}
+		})
+	}
+
+	for _, n := range rpt.Endpoint.Nodes {
+		_, srcIP, _, ok := report.ParseEndpointNodeID(n.ID)
+		if !ok {
+			continue
+		}
+		for _, dst := range n.Adjacency {
+			_, dstIP, dstPort, ok := report.ParseEndpointNodeID(dst)
+			if !ok {
+				continue
+			}
+			port, err := strconv.Atoi(dstPort)
+			if err != nil {
+				continue
+			}
+			flow := Flow{
+				Source:      Peer{PodID: owners[n.ID], IP: srcIP},
+				Destination: Peer{PodID: owners[dst], IP: dstIP},
+				Protocol:    apiv1.ProtocolTCP,
+				Port:        port,
+			}
+			if transport, ok := n.Latest.Lookup(endpoint.Transport); ok && transport == "udp" {
+				flow.Protocol = apiv1.ProtocolUDP
+			}
+			// Flows between addresses outside of pods are none of our business.
+			if flow.Source.PodID == "" && flow.Destination.PodID == "" {
+				continue
+			}
+			f.Flows[flow] = struct{}{}
+		}
+	}
+}
+
+func makePod(n report.Node) Pod {
+	pod := Pod{ID: n.ID, Labels: nodeLabels(n)}
+	pod.Name, _ = n.Latest.Lookup(kubernetes.Name)
+	pod.Namespace, _ = n.Latest.Lookup(kubernetes.Namespace)
+	pod.IP, _ = n.Latest.Lookup(kubernetes.IP)
+	_, pod.HostNetwork = n.Latest.Lookup(kubernetes.IsInHostNetwork)
+	return pod
+}
+
+// nodeLabels returns the Kubernetes labels of n.
+func nodeLabels(n report.Node) map[string]string {
+	result := map[string]string{}
+	n.Latest.ForEach(func(key string, _ time.Time, value string) {
+		if strings.HasPrefix(key, kubernetes.LabelPrefix) {
+			result[strings.TrimPrefix(key, kubernetes.LabelPrefix)] = value
+		}
+	})
+	return result
+}
+
+// PodIDs returns the IDs of the pods in namespace, or of all pods if
+// namespace is empty, sorted.
+func (f Flows) PodIDs(namespace string) []string {
+	ids := []string{}
+	for id, pod := range f.Pods {
+		if namespace == "" || pod.Namespace == namespace {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// List returns the flows, sorted.
+func (f Flows) List() []Flow {
+	flows := make([]Flow, 0, len(f.Flows))
+	for flow := range f.Flows {
+		flows = append(flows, flow)
+	}
+	sort.Sort(byEnds(flows))
+	return flows
+}
+
+type byEnds []Flow
+
+func (s byEnds) Len() int      { return len(s) }
+func (s byEnds) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byEnds) Less(i, j int) bool {
+	a, b := s[i], s[j]
+	switch {
+	case a.Destination != b.Destination:
+		return a.Destination.PodID+a.Destination.IP < b.Destination.PodID+b.Destination.IP
+	case a.Protocol != b.Protocol:
+		return a.Protocol < b.Protocol
+	case a.Port != b.Port:
+		return a.Port < b.Port
+	}
+	return a.Source.PodID+a.Source.IP < b.Source.PodID+b.Source.IP
+}
+
+// CheckPolicy returns an error if no NetworkPolicy can select the pod.
+func (f Flows) CheckPolicy(podID string) error {
+	pod, ok := f.Pods[podID]
+	switch {
+	case !ok:
+		return fmt.Errorf("pod not found: %s", podID)
+	case pod.HostNetwork:
+		return fmt.Errorf("pod %s/%s is in the host network, which network policies do not apply to", pod.Namespace, pod.Name)
+	case len(pod.Labels) == 0:
+		return fmt.Errorf("pod %s/%s has no labels to select it by", pod.Namespace, pod.Name)
+	}
+	return nil
+}
+
+// NetworkPolicies returns NetworkPolicies allowing the flows observed
+// to and from the given pods, and denying everything else. Pods with
+// the same labels are selected by the same policy, which allows the
+// flows of all of them. Pods which no policy can select are skipped.
+func (f Flows) NetworkPolicies(podIDs []string) []networkingv1.NetworkPolicy {
+	// Group the pods by namespace and labels
+	groups := map[string][]Pod{}
+	keys := []string{}
+	for _, id := range podIDs {
+		if f.CheckPolicy(id) != nil {
+			continue
+		}
+		pod := f.Pods[id]
+		key := pod.Namespace + "/" + labels.Set(pod.Labels).String()
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], pod)
+	}
+	sort.Strings(keys)
+
+	flows := f.List()
+	policies := make([]networkingv1.NetworkPolicy, 0, len(keys))
+	for _, key := range keys {
+		policies = append(policies, f.networkPolicy(groups[key], flows))
+	}
+	return policies
+}
+
+func (f Flows) networkPolicy(pods []Pod, flows []Flow) networkingv1.NetworkPolicy {
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	var (
+		pod     = pods[0]
+		members = map[string]bool{}
+		names   = []string{}
+		ingress = rules{}
+		egress  = rules{}
+	)
+	for _, p := range pods {
+		members[p.ID] = true
+		names = append(names, p.Name)
+	}
+	for _, flow := range flows {
+		if members[flow.Destination.PodID] {
+			ingress.add(flow, f.peer(pod.Namespace, flow.Source))
+		}
+		if members[flow.Source.PodID] {
+			egress.add(flow, f.peer(pod.Namespace, flow.Destination))
+		}
+	}
+
+	policy := networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "scope-" + pod.Name,
+			Namespace:   pod.Namespace,
+			Annotations: map[string]string{"scope.weave.works/pods": strings.Join(names, ",")},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: pod.Labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+	for _, rule := range ingress.list {
+		policy.Spec.Ingress = append(policy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: rule.ports(),
+			From:  rule.peers,
+		})
+	}
+	for _, rule := range egress.list {
+		policy.Spec.Egress = append(policy.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			Ports: rule.ports(),
+			To:    rule.peers,
+		})
+	}
+	return policy
+}
+
+// peer returns the NetworkPolicyPeer for a peer of a pod in namespace.
+// Pods are selected by their labels, and those in other namespaces by
+// the labels of their namespace too; anything else by its address.
+func (f Flows) peer(namespace string, p Peer) networkingv1.NetworkPolicyPeer {
+	pod, ok := f.Pods[p.PodID]
+	if !ok || f.CheckPolicy(p.PodID) != nil {
+		return networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: hostCIDR(p.IP)},
+		}
+	}
+	peer := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: pod.Labels},
+	}
+	if pod.Namespace != namespace {
+		peer.NamespaceSelector = &metav1.LabelSelector{MatchLabels: f.namespaceLabels(pod.Namespace)}
+	}
+	return peer
+}
+
+// namespaceLabels returns the labels to select namespace by: its own,
+// or else the label Kubernetes sets on every namespace from 1.21.
+func (f Flows) namespaceLabels(namespace string) map[string]string {
+	if selector := f.Namespaces[namespace]; len(selector) > 0 {
+		return selector
+	}
+	return map[string]string{namespaceNameLabel: namespace}
+}
+
+// namespaceNameLabel is set by Kubernetes on namespaces, to their name.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+func hostCIDR(ip string) string {
+	if isIPv6(ip) {
+		return ip + "/128"
+	}
+	return ip + "/32"
+}
+
+func isIPv6(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.To4() == nil
+}
+
+// rule is the peers allowed to or from a port.
+type rule struct {
+	protocol apiv1.Protocol
+	port     int
+	peers    []networkingv1.NetworkPolicyPeer
+	seen     map[string]bool
+}
+
+func (r *rule) ports() []networkingv1.NetworkPolicyPort {
+	protocol, port := r.protocol, intstr.FromInt(r.port)
+	return []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &port}}
+}
+
+// rules are the rules of a policy, in the order of their first flow.
+type rules struct {
+	list   []*rule
+	byPort map[string]*rule
+}
+
+func (rs *rules) add(flow Flow, peer networkingv1.NetworkPolicyPeer) {
+	key := fmt.Sprintf("%s/%d", flow.Protocol, flow.Port)
+	r, ok := rs.byPort[key]
+	if !ok {
+		if rs.byPort == nil {
+			rs.byPort = map[string]*rule{}
+		}
+		r = &rule{protocol: flow.Protocol, port: flow.Port, seen: map[string]bool{}}
+		rs.byPort[key] = r
+		rs.list = append(rs.list, r)
+	}
+	peerKey := peer.String()
+	if !r.seen[peerKey] {
+		r.seen[peerKey] = true
+		r.peers = append(r.peers, peer)
+	}
+}
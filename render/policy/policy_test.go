@@ -0,0 +1,199 @@
+package policy_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/weaveworks/scope/probe/kubernetes"
+	"github.com/weaveworks/scope/render/policy"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/fixture"
+)
+
+// labelledReport is the fixture report, with labels on the pods.
+func labelledReport() report.Report {
+	rpt := fixture.Report.Copy()
+	rpt.Pod.Nodes = rpt.Pod.Nodes.Copy()
+	for id, app := range map[string]string{
+		fixture.ClientPodNodeID: "pong-a",
+		fixture.ServerPodNodeID: "pong-b",
+	} {
+		rpt.Pod.Nodes[id] = rpt.Pod.Nodes[id].WithLatests(map[string]string{
+			kubernetes.LabelPrefix + "app": app,
+		})
+	}
+	return rpt
+}
+
+func TestNetworkPolicies(t *testing.T) {
+	flows := policy.MakeFlows()
+	flows.Add(labelledReport())
+
+	policies := flows.NetworkPolicies(flows.PodIDs(fixture.KubernetesNamespace))
+	if len(policies) != 2 {
+		t.Fatalf("Expected 2 policies, got %d", len(policies))
+	}
+	byName := map[string]networkingv1.NetworkPolicy{}
+	for _, p := range policies {
+		byName[p.Name] = p
+	}
+
+	server, ok := byName["scope-pong-b"]
+	if !ok {
+		t.Fatalf("Expected a policy for pong-b, got %v", byName)
+	}
+	if want, have := "pong-b", server.Spec.PodSelector.MatchLabels["app"]; want != have {
+		t.Errorf("Expected selector app=%s, got %s", want, have)
+	}
+	if len(server.Spec.Ingress) != 1 {
+		t.Fatalf("Expected 1 ingress rule, got %v", server.Spec.Ingress)
+	}
+	rule := server.Spec.Ingress[0]
+	if len(rule.Ports) != 1 || rule.Ports[0].Port.IntValue() != 80 {
+		t.Errorf("Expected ingress on port 80, got %v", rule.Ports)
+	}
+	var (
+		fromPod bool
+		cidrs   = map[string]bool{}
+	)
+	for _, peer := range rule.From {
+		if peer.PodSelector != nil && peer.PodSelector.MatchLabels["app"] == "pong-a" {
+			fromPod = true
+		}
+		if peer.IPBlock != nil {
+			cidrs[peer.IPBlock.CIDR] = true
+		}
+	}
+	if !fromPod {
+		t.Errorf("Expected ingress from pong-a, got %v", rule.From)
+	}
+	for _, ip := range []string{fixture.UnknownClient1IP, fixture.UnknownClient3IP, fixture.RandomClientIP} {
+		if !cidrs[ip+"/32"] {
+			t.Errorf("Expected ingress from %s, got %v", ip, rule.From)
+		}
+	}
+
+	client := byName["scope-pong-a"]
+	if len(client.Spec.Ingress) != 0 {
+		t.Errorf("Expected no ingress rules, got %v", client.Spec.Ingress)
+	}
+	if len(client.Spec.Egress) != 1 || client.Spec.Egress[0].To[0].PodSelector.MatchLabels["app"] != "pong-b" {
+		t.Errorf("Expected egress to pong-b, got %v", client.Spec.Egress)
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	flows := policy.MakeFlows()
+	flows.Add(fixture.Report)
+	if err := flows.CheckPolicy(fixture.ServerPodNodeID); err == nil {
+		t.Error("Expected an error for a pod without labels")
+	}
+	if err := flows.CheckPolicy("foo"); err == nil {
+		t.Error("Expected an error for an unknown pod")
+	}
+	if policies := flows.NetworkPolicies(flows.PodIDs("")); len(policies) != 0 {
+		t.Errorf("Expected no policies, got %v", policies)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	flows := policy.MakeFlows()
+	flows.Add(labelledReport())
+	podIDs := []string{fixture.ServerPodNodeID}
+
+	var buf bytes.Buffer
+	if err := flows.Write(&buf, policy.YAML, podIDs); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"kind: NetworkPolicy", "name: scope-pong-b", "port: 80"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := flows.Write(&buf, policy.JSON, podIDs); err != nil {
+		t.Fatal(err)
+	}
+	var list networkingv1.NetworkPolicyList
+	if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "scope-pong-b" {
+		t.Errorf("Unexpected policies: %v", list.Items)
+	}
+
+	buf.Reset()
+	if err := flows.Write(&buf, policy.IPTables, podIDs); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"-s " + fixture.ClientIP + "/32 -d " + fixture.ServerIP + "/32 -p tcp -m tcp --dport 80 -j RETURN",
+		"-d " + fixture.ServerIP + "/32 -j DROP",
+		"iptables -I DOCKER-USER -j " + policy.IPTablesChain,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestCrossNamespacePeers(t *testing.T) {
+	flows := policy.MakeFlows()
+	flows.Pods["client"] = policy.Pod{ID: "client", Name: "client", Namespace: "front", IP: "10.0.0.1", Labels: map[string]string{"app": "client"}}
+	flows.Pods["server"] = policy.Pod{ID: "server", Name: "server", Namespace: "back", IP: "10.0.0.2", Labels: map[string]string{"app": "server"}}
+	flows.Namespaces["front"] = map[string]string{"team": "front"}
+	flows.Flows[policy.Flow{
+		Source:      policy.Peer{PodID: "client", IP: "10.0.0.1"},
+		Destination: policy.Peer{PodID: "server", IP: "10.0.0.2"},
+		Protocol:    "TCP",
+		Port:        80,
+	}] = struct{}{}
+
+	byName := map[string]networkingv1.NetworkPolicy{}
+	for _, p := range flows.NetworkPolicies(flows.PodIDs("")) {
+		byName[p.Name] = p
+	}
+	from := byName["scope-server"].Spec.Ingress[0].From[0]
+	if from.IPBlock != nil || from.PodSelector.MatchLabels["app"] != "client" || from.NamespaceSelector.MatchLabels["team"] != "front" {
+		t.Errorf("Expected ingress from the client pod of namespace front, got %v", from)
+	}
+	// Namespaces without labels are selected by the label of their name
+	to := byName["scope-client"].Spec.Egress[0].To[0]
+	if to.IPBlock != nil || to.PodSelector.MatchLabels["app"] != "server" || to.NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"] != "back" {
+		t.Errorf("Expected egress to the server pod of namespace back, got %v", to)
+	}
+}
+
+func TestWriteIPTablesIPv6(t *testing.T) {
+	flows := policy.MakeFlows()
+	flows.Pods["server"] = policy.Pod{ID: "server", Name: "server", Namespace: "default", IP: "fd00::2", Labels: map[string]string{"app": "server"}}
+	flows.Flows[policy.Flow{
+		Source:      policy.Peer{IP: "fd00::1"},
+		Destination: policy.Peer{PodID: "server", IP: "fd00::2"},
+		Protocol:    "TCP",
+		Port:        80,
+	}] = struct{}{}
+
+	var buf bytes.Buffer
+	if err := flows.Write(&buf, policy.IPTables, []string{"server"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"ip6tables -N " + policy.IPTablesChain,
+		"ip6tables -A " + policy.IPTablesChain + " -s fd00::1/128 -d fd00::2/128 -p tcp -m tcp --dport 80 -j RETURN",
+		"ip6tables -A " + policy.IPTablesChain + " -d fd00::2/128 -j DROP",
+		"ip6tables -I DOCKER-USER -j " + policy.IPTablesChain,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "iptables -A "+policy.IPTablesChain+" -s") {
+		t.Errorf("Expected no iptables rules for IPv6 addresses in:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Formats policies can be written in.
+const (
+	YAML     = "yaml"
+	JSON     = "json"
+	IPTables = "iptables"
+)
+
+var contentTypes = map[string]string{
+	YAML:     "application/x-yaml",
+	JSON:     "application/json",
+	IPTables: "text/plain; charset=utf-8",
+}
+
+// ContentType returns the content type of format, and whether format
+// is known.
+func ContentType(format string) (string, bool) {
+	contentType, ok := contentTypes[format]
+	return contentType, ok
+}
+
+// Write writes the policies for the given pods to w in format: YAML, a
+// stream of NetworkPolicy manifests; JSON, a NetworkPolicyList; or
+// IPTables, a script of iptables commands.
+func (f Flows) Write(w io.Writer, format string, podIDs []string) error {
+	switch format {
+	case YAML:
+		for _, policy := range f.NetworkPolicies(podIDs) {
+			buf, err := yaml.Marshal(policy)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "---\n%s", buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	case JSON:
+		return json.NewEncoder(w).Encode(networkingv1.NetworkPolicyList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicyList"},
+			Items:    f.NetworkPolicies(podIDs),
+		})
+	case IPTables:
+		return f.writeIPTables(w, podIDs)
+	}
+	return fmt.Errorf("unknown format: %q", format)
+}
+
+// IPTablesChain is the chain the iptables rules are added to. It is
+// jumped to from DOCKER-USER, which Docker evaluates before its own
+// rules for traffic to and from containers.
+const IPTablesChain = "SCOPE-POLICY"
+
+// writeIPTables writes iptables commands allowing the flows observed to
+// and from the addresses of the given pods, and dropping any other new
+// connections to or from them. Rules for IPv6 addresses are written as
+// ip6tables commands, in a chain of their own.
+func (f Flows) writeIPTables(w io.Writer, podIDs []string) error {
+	var (
+		members = map[string]bool{}
+		rules   = map[string][]string{}
+		seen    = map[string]bool{}
+		ips     = map[string]bool{}
+	)
+	for _, id := range podIDs {
+		members[id] = true
+		if pod, ok := f.Pods[id]; ok && pod.IP != "" && !pod.HostNetwork {
+			ips[pod.IP] = true
+		}
+	}
+	for _, flow := range f.List() {
+		if !members[flow.Source.PodID] && !members[flow.Destination.PodID] {
+			continue
+		}
+		if members[flow.Source.PodID] && !f.Pods[flow.Source.PodID].HostNetwork {
+			ips[flow.Source.IP] = true
+		}
+		if members[flow.Destination.PodID] && !f.Pods[flow.Destination.PodID].HostNetwork {
+			ips[flow.Destination.IP] = true
+		}
+		command := iptablesCommand(flow.Destination.IP)
+		line := fmt.Sprintf("%s -A %s -s %s -d %s -p %s -m %s --dport %d -j RETURN",
+			command, IPTablesChain, hostCIDR(flow.Source.IP), hostCIDR(flow.Destination.IP),
+			strings.ToLower(string(flow.Protocol)), strings.ToLower(string(flow.Protocol)), flow.Port)
+		if !seen[line] {
+			seen[line] = true
+			rules[command] = append(rules[command], line)
+		}
+	}
+	sortedIPs := []string{}
+	for ip := range ips {
+		sortedIPs = append(sortedIPs, ip)
+	}
+	sort.Strings(sortedIPs)
+	for _, ip := range sortedIPs {
+		command := iptablesCommand(ip)
+		rules[command] = append(rules[command],
+			fmt.Sprintf("%s -A %s -d %s -j DROP", command, IPTablesChain, hostCIDR(ip)),
+			fmt.Sprintf("%s -A %s -s %s -j DROP", command, IPTablesChain, hostCIDR(ip)),
+		)
+	}
+	lines := []string{}
+	for _, command := range []string{"iptables", "ip6tables"} {
+		// The IPv4 chain is always set up; the IPv6 one only if needed
+		if command == "ip6tables" && len(rules[command]) == 0 {
+			continue
+		}
+		lines = append(lines,
+			command+" -N "+IPTablesChain,
+			command+" -A "+IPTablesChain+" -m conntrack --ctstate ESTABLISHED,RELATED -j RETURN",
+		)
+		lines = append(lines, rules[command]...)
+		lines = append(lines, command+" -I DOCKER-USER -j "+IPTablesChain)
+	}
+	_, err := fmt.Fprintf(w, "%s\n", strings.Join(lines, "\n"))
+	return err
+}
+
+// iptablesCommand returns the command setting up the rules for ip:
+// ip6tables for IPv6 addresses, iptables for the others.
+func iptablesCommand(ip string) string {
+	if isIPv6(ip) {
+		return "ip6tables"
+	}
+	return "iptables"
+}
@@ -64,8 +64,113 @@ var ProcessWithContainerNameRenderer = processWithContainerNameRenderer{ProcessR
 // not memoised
 var ProcessNameRenderer = CustomRenderer{RenderFunc: processes2Names, Renderer: ProcessRenderer}
 
+// ProcessTreeRenderer is a Renderer which produces the process graph
+// with edges from parent to child processes, rather than connections,
+// including processes with no network activity.
+//
+// not memoised
+var ProcessTreeRenderer = CustomRenderer{RenderFunc: processes2Tree, Renderer: ProcessWithContainerNameRenderer}
+
+// processes2Tree replaces the adjacencies of process Nodes with edges
+// to their child processes.
+func processes2Tree(processes Nodes) Nodes {
+	output := make(report.Nodes, len(processes.Nodes))
+	for id, n := range processes.Nodes {
+		if n.Topology == Pseudo {
+			continue
+		}
+		n.Adjacency = nil
+		n.Edges = report.MakeEdgeMetadatas()
+		output[id] = n
+	}
+	for id, n := range output {
+		ppid, ok := n.Latest.Lookup(process.PPID)
+		if !ok {
+			continue
+		}
+		hostID, _, ok := report.ParseProcessNodeID(id)
+		if !ok {
+			continue
+		}
+		parentID := report.MakeProcessNodeID(hostID, ppid)
+		if parent, ok := output[parentID]; ok && parentID != id {
+			output[parentID] = parent.WithAdjacent(id)
+		}
+	}
+	return Nodes{Nodes: output, Filtered: processes.Filtered}
+}
+
+// CollapseProcessSubtrees is a Transformer which collapses the
+// descendants of each of the given processes of the process tree into
+// it, as its children.
+type CollapseProcessSubtrees []string
+
+// Transform implements Transformer
+func (c CollapseProcessSubtrees) Transform(input Nodes) Nodes {
+	// The root each collapsed process goes into
+	roots := map[string]string{}
+	for _, root := range c {
+		if _, ok := input.Nodes[root]; !ok {
+			continue
+		}
+		queue := []string{root}
+		for len(queue) > 0 {
+			n := input.Nodes[queue[0]]
+			queue = queue[1:]
+			for _, child := range n.Adjacency {
+				if _, seen := roots[child]; seen || child == root {
+					continue
+				}
+				roots[child] = root
+				queue = append(queue, child)
+			}
+		}
+	}
+	// Collapsed roots below other collapsed roots go into the topmost
+	rootOf := func(id string) string {
+		for seen := 0; seen <= len(c); seen++ {
+			root, ok := roots[id]
+			if !ok {
+				break
+			}
+			id = root
+		}
+		return id
+	}
+
+	output := make(report.Nodes, len(input.Nodes))
+	for id, n := range input.Nodes {
+		if _, ok := roots[id]; ok {
+			continue
+		}
+		n.Adjacency = nil
+		for _, dst := range input.Nodes[id].Adjacency {
+			if dst = rootOf(dst); dst != id {
+				n = n.WithAdjacent(dst)
+			}
+		}
+		output[id] = n
+	}
+	for id, n := range input.Nodes {
+		if _, ok := roots[id]; !ok {
+			continue
+		}
+		rootID := rootOf(id)
+		root := output[rootID]
+		for _, dst := range n.Adjacency {
+			if dst = rootOf(dst); dst != rootID {
+				root = root.WithAdjacent(dst)
+			}
+		}
+		n.Adjacency = nil
+		output[rootID] = root.WithChild(n).WithChildren(n.Children)
+	}
+	return Nodes{Nodes: output, Filtered: input.Filtered}
+}
+
 // endpoints2Processes joins the endpoint topology to the process
-// topology, matching on hostID and pid.
+// topology, matching on hostID and pid, keeping the adjacencies
+// between processes of the process topology.
 type endpoints2Processes struct {
 }
 
@@ -74,7 +179,7 @@ func (e endpoints2Processes) Render(rpt report.Report) Nodes {
 		return Nodes{}
 	}
 	endpoints := SelectEndpoint.Render(rpt).Nodes
-	return MapEndpoints(
+	processes := MapEndpoints(
 		func(n report.Node) string {
 			pid, ok := n.Latest.Lookup(process.PID)
 			if !ok {
@@ -89,6 +194,26 @@ func (e endpoints2Processes) Render(rpt report.Report) Nodes {
 			}
 			return report.MakeProcessNodeID(hostID, pid)
 		}, report.Process).Render(rpt)
+	return addProcessAdjacency(processes, rpt.Process)
+}
+
+// addProcessAdjacency adds the adjacencies between processes reported
+// in the process topology itself, e.g. over Unix sockets, rather than
+// through endpoints.
+func addProcessAdjacency(processes Nodes, topology report.Topology) Nodes {
+	for id, n := range topology.Nodes {
+		out, ok := processes.Nodes[id]
+		if !ok {
+			continue
+		}
+		for _, dst := range n.Adjacency {
+			if _, ok := processes.Nodes[dst]; ok {
+				out = out.WithAdjacent(dst)
+			}
+		}
+		processes.Nodes[id] = out
+	}
+	return processes
 }
 
 // When there is more than one connection originating from a source
@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/probe/process"
 	"github.com/weaveworks/scope/render"
 	"github.com/weaveworks/scope/render/expected"
+	"github.com/weaveworks/scope/report"
 	"github.com/weaveworks/scope/test/fixture"
 	"github.com/weaveworks/scope/test/reflect"
 	"github.com/weaveworks/scope/test/utils"
@@ -34,3 +36,80 @@ func TestProcessNameRenderer(t *testing.T) {
 		t.Error(test.Diff(want, have))
 	}
 }
+
+func TestProcessRendererProcessAdjacency(t *testing.T) {
+	rpt := fixture.Report.Copy()
+	rpt.ID = "process-adjacency"
+	rpt.Process.AddNode(report.MakeNode(fixture.NonContainerProcessNodeID).WithAdjacent(fixture.ServerProcessNodeID, "unknown"))
+
+	have := render.ProcessRenderer.Render(rpt).Nodes[fixture.NonContainerProcessNodeID]
+	if !have.Adjacency.Contains(fixture.ServerProcessNodeID) {
+		t.Errorf("Expected adjacency to %s, got %v", fixture.ServerProcessNodeID, have.Adjacency)
+	}
+	if have.Adjacency.Contains("unknown") {
+		t.Errorf("Expected no adjacency to unknown processes, got %v", have.Adjacency)
+	}
+}
+
+func processTreeReport() report.Report {
+	rpt := fixture.Report.Copy()
+	rpt.ID = "process-tree"
+	for id, ppid := range map[string]string{
+		fixture.ServerProcessNodeID:                            fixture.NonContainerPID,
+		fixture.ClientProcess2NodeID:                           fixture.Client1PID,
+		report.MakeProcessNodeID(fixture.ServerHostID, "4321"): fixture.ServerPID,
+	} {
+		rpt.Process.AddNode(report.MakeNodeWith(id, map[string]string{process.PPID: ppid}).WithTopology(report.Process))
+	}
+	return rpt
+}
+
+func TestProcessTreeRenderer(t *testing.T) {
+	var (
+		rpt       = processTreeReport()
+		workerID  = report.MakeProcessNodeID(fixture.ServerHostID, "4321")
+		have      = render.ProcessTreeRenderer.Render(rpt).Nodes
+		wantEdges = map[string][]string{
+			fixture.NonContainerProcessNodeID: {fixture.ServerProcessNodeID},
+			fixture.ServerProcessNodeID:       {workerID},
+			fixture.ClientProcess1NodeID:      {fixture.ClientProcess2NodeID},
+			fixture.ClientProcess2NodeID:      nil,
+			workerID:                          nil,
+		}
+	)
+	if len(have) != len(wantEdges) {
+		t.Errorf("Expected %d processes, got %v", len(wantEdges), have)
+	}
+	for id, want := range wantEdges {
+		n, ok := have[id]
+		if !ok {
+			t.Errorf("Expected process %s", id)
+			continue
+		}
+		if !reflect.DeepEqual(report.MakeIDList(want...), n.Adjacency) {
+			t.Errorf("%s: expected adjacency %v, got %v", id, want, n.Adjacency)
+		}
+	}
+}
+
+func TestCollapseProcessSubtrees(t *testing.T) {
+	var (
+		rpt      = processTreeReport()
+		workerID = report.MakeProcessNodeID(fixture.ServerHostID, "4321")
+		have     = render.CollapseProcessSubtrees{fixture.NonContainerProcessNodeID}.Transform(render.ProcessTreeRenderer.Render(rpt)).Nodes
+	)
+	for _, id := range []string{fixture.ServerProcessNodeID, workerID} {
+		if _, ok := have[id]; ok {
+			t.Errorf("Expected process %s to be collapsed", id)
+		}
+		if _, ok := have[fixture.NonContainerProcessNodeID].Children.Lookup(id); !ok {
+			t.Errorf("Expected process %s to be a child of its root", id)
+		}
+	}
+	if adjacency := have[fixture.NonContainerProcessNodeID].Adjacency; len(adjacency) != 0 {
+		t.Errorf("Expected no adjacency of the collapsed root, got %v", adjacency)
+	}
+	if !have[fixture.ClientProcess1NodeID].Adjacency.Contains(fixture.ClientProcess2NodeID) {
+		t.Errorf("Expected other subtrees to be left alone")
+	}
+}
@@ -155,6 +155,7 @@ func newJoinResults(inputNodes report.Nodes) joinResults {
 	for id, n := range inputNodes {
 		n.Adjacency = nil              // result() assumes all nodes start with no adjacencies
 		n.Children = n.Children.Copy() // so we can do unsafe adds
+		n.Edges = report.MakeEdgeMetadatas()
 		nodes[id] = n
 	}
 	return joinResults{nodes: nodes, mapped: map[string]string{}, multi: map[string][]string{}}
@@ -212,6 +213,7 @@ func (ret *joinResults) addChildAndChildren(m report.Node, id string, topology s
 // Add a copy of n straight into the results
 func (ret *joinResults) passThrough(n report.Node) {
 	n.Adjacency = nil // result() assumes all nodes start with no adjacencies
+	n.Edges = report.MakeEdgeMetadatas()
 	ret.nodes[n.ID] = n
 	n.Children = n.Children.Copy() // so we can do unsafe adds
 	ret.mapChild(n.ID, n.ID)
@@ -225,22 +227,29 @@ func (ret *joinResults) result(input Nodes) Nodes {
 		if !ok {
 			continue
 		}
-		ret.rewriteAdjacency(outID, n.Adjacency)
+		ret.rewriteAdjacency(outID, n)
 		for _, outID := range ret.multi[n.ID] {
-			ret.rewriteAdjacency(outID, n.Adjacency)
+			ret.rewriteAdjacency(outID, n)
 		}
 	}
 	return Nodes{Nodes: ret.nodes}
 }
 
-func (ret *joinResults) rewriteAdjacency(outID string, adjacency report.IDList) {
+func (ret *joinResults) rewriteAdjacency(outID string, in report.Node) {
 	out := ret.nodes[outID]
 	// for each adjacency in the original node, find out what it maps
-	// to (if any), and add that to the new node
-	for _, a := range adjacency {
+	// to (if any), and add that to the new node, aggregating the
+	// metadata of all the original edges ending up on the same new edge
+	for _, a := range in.Adjacency {
 		if mappedDest, found := ret.mapped[a]; found {
 			out.Adjacency = out.Adjacency.Add(mappedDest)
 			out.Adjacency = out.Adjacency.Add(ret.multi[a]...)
+			if md, ok := in.Edges.Lookup(a); ok {
+				out.Edges = out.Edges.Aggregate(mappedDest, md)
+				for _, dest := range ret.multi[a] {
+					out.Edges = out.Edges.Aggregate(dest, md)
+				}
+			}
 		}
 	}
 	ret.nodes[outID] = out
@@ -93,3 +93,26 @@ func TestMapRender3(t *testing.T) {
 }
 
 func newu64(value uint64) *uint64 { return &value }
+
+func TestMapRenderEdges(t *testing.T) {
+	// Check edges mapped onto the same destination are aggregated
+	mapper := render.Map{
+		MapFunc: func(nodes report.Node) report.Node {
+			return report.MakeNode(nodes.ID[:1])
+		},
+		Renderer: mockRenderer{Nodes: report.Nodes{
+			"a1": report.MakeNode("a1").WithEdge("b1", report.EdgeMetadata{Connections: 1, EgressByteCount: 10}),
+			"a2": report.MakeNode("a2").WithEdge("b2", report.EdgeMetadata{Connections: 1, EgressByteCount: 20}),
+			"b1": report.MakeNode("b1"),
+			"b2": report.MakeNode("b2"),
+		}},
+	}
+	want := report.Nodes{
+		"a": report.MakeNode("a").WithEdge("b", report.EdgeMetadata{Connections: 2, EgressByteCount: 30}),
+		"b": report.MakeNode("b"),
+	}
+	have := mapper.Render(report.MakeReport()).Nodes
+	if !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
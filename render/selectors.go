@@ -17,19 +17,25 @@ func (t TopologySelector) Render(r report.Report) Nodes {
 // The topology selectors implement a Renderer which fetch the nodes from the
 // various report topologies.
 var (
-	SelectEndpoint       = TopologySelector(report.Endpoint)
-	SelectProcess        = TopologySelector(report.Process)
-	SelectContainer      = TopologySelector(report.Container)
-	SelectContainerImage = TopologySelector(report.ContainerImage)
-	SelectHost           = TopologySelector(report.Host)
-	SelectPod            = TopologySelector(report.Pod)
-	SelectService        = TopologySelector(report.Service)
-	SelectDeployment     = TopologySelector(report.Deployment)
-	SelectDaemonSet      = TopologySelector(report.DaemonSet)
-	SelectStatefulSet    = TopologySelector(report.StatefulSet)
-	SelectCronJob        = TopologySelector(report.CronJob)
-	SelectECSTask        = TopologySelector(report.ECSTask)
-	SelectECSService     = TopologySelector(report.ECSService)
-	SelectSwarmService   = TopologySelector(report.SwarmService)
-	SelectOverlay        = TopologySelector(report.Overlay)
+	SelectEndpoint              = TopologySelector(report.Endpoint)
+	SelectProcess               = TopologySelector(report.Process)
+	SelectContainer             = TopologySelector(report.Container)
+	SelectContainerImage        = TopologySelector(report.ContainerImage)
+	SelectHost                  = TopologySelector(report.Host)
+	SelectKubernetesNode        = TopologySelector(report.KubernetesNode)
+	SelectPod                   = TopologySelector(report.Pod)
+	SelectService               = TopologySelector(report.Service)
+	SelectDeployment            = TopologySelector(report.Deployment)
+	SelectDaemonSet             = TopologySelector(report.DaemonSet)
+	SelectStatefulSet           = TopologySelector(report.StatefulSet)
+	SelectCronJob               = TopologySelector(report.CronJob)
+	SelectJob                   = TopologySelector(report.Job)
+	SelectIngress               = TopologySelector(report.Ingress)
+	SelectPersistentVolumeClaim = TopologySelector(report.PersistentVolumeClaim)
+	SelectPersistentVolume      = TopologySelector(report.PersistentVolume)
+	SelectStorageClass          = TopologySelector(report.StorageClass)
+	SelectECSTask               = TopologySelector(report.ECSTask)
+	SelectECSService            = TopologySelector(report.ECSService)
+	SelectSwarmService          = TopologySelector(report.SwarmService)
+	SelectOverlay               = TopologySelector(report.Overlay)
 )
@@ -0,0 +1,206 @@
+package report
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/ugorji/go/codec"
+	"github.com/weaveworks/ps"
+)
+
+// EdgeMetadata describes what probes know about the traffic on an edge
+// between two nodes. Egress is traffic flowing from the node holding
+// the edge to the adjacent node, ingress is traffic flowing back.
+type EdgeMetadata struct {
+	Connections        uint64    `json:"connections,omitempty"`
+	EgressPacketCount  uint64    `json:"egress_packet_count,omitempty"`
+	IngressPacketCount uint64    `json:"ingress_packet_count,omitempty"`
+	EgressByteCount    uint64    `json:"egress_byte_count,omitempty"`
+	IngressByteCount   uint64    `json:"ingress_byte_count,omitempty"`
+	FirstSeen          time.Time `json:"first_seen,omitempty"`
+	LastSeen           time.Time `json:"last_seen,omitempty"`
+}
+
+// Merge merges two observations of the same edge, e.g. from successive
+// reports. Counters are cumulative, so the larger value is kept.
+func (e EdgeMetadata) Merge(other EdgeMetadata) EdgeMetadata {
+	return EdgeMetadata{
+		Connections:        maxUint64(e.Connections, other.Connections),
+		EgressPacketCount:  maxUint64(e.EgressPacketCount, other.EgressPacketCount),
+		IngressPacketCount: maxUint64(e.IngressPacketCount, other.IngressPacketCount),
+		EgressByteCount:    maxUint64(e.EgressByteCount, other.EgressByteCount),
+		IngressByteCount:   maxUint64(e.IngressByteCount, other.IngressByteCount),
+		FirstSeen:          earliest(e.FirstSeen, other.FirstSeen),
+		LastSeen:           latest(e.LastSeen, other.LastSeen),
+	}
+}
+
+// Flatten combines two distinct edges into one, e.g. the edges of all
+// the endpoints of a process. Counters are summed.
+func (e EdgeMetadata) Flatten(other EdgeMetadata) EdgeMetadata {
+	return EdgeMetadata{
+		Connections:        e.Connections + other.Connections,
+		EgressPacketCount:  e.EgressPacketCount + other.EgressPacketCount,
+		IngressPacketCount: e.IngressPacketCount + other.IngressPacketCount,
+		EgressByteCount:    e.EgressByteCount + other.EgressByteCount,
+		IngressByteCount:   e.IngressByteCount + other.IngressByteCount,
+		FirstSeen:          earliest(e.FirstSeen, other.FirstSeen),
+		LastSeen:           latest(e.LastSeen, other.LastSeen),
+	}
+}
+
+// Reversed returns the metadata as seen from the other end of the edge.
+func (e EdgeMetadata) Reversed() EdgeMetadata {
+	e.EgressPacketCount, e.IngressPacketCount = e.IngressPacketCount, e.EgressPacketCount
+	e.EgressByteCount, e.IngressByteCount = e.IngressByteCount, e.EgressByteCount
+	return e
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// EdgeMetadatas is a map of destination node ID to EdgeMetadata.
+// It is immutable.
+type EdgeMetadatas struct {
+	psMap ps.Map
+}
+
+var emptyEdgeMetadatas = EdgeMetadatas{ps.NewMap()}
+
+// MakeEdgeMetadatas returns EmptyEdgeMetadatas
+func MakeEdgeMetadatas() EdgeMetadatas {
+	return emptyEdgeMetadatas
+}
+
+// Add the edge to dst, merging it with any existing edge to dst.
+func (c EdgeMetadatas) Add(dst string, md EdgeMetadata) EdgeMetadatas {
+	return c.add(dst, md, EdgeMetadata.Merge)
+}
+
+// Aggregate the edge to dst into the existing edge to dst, as
+// different edges being combined into one.
+func (c EdgeMetadatas) Aggregate(dst string, md EdgeMetadata) EdgeMetadatas {
+	return c.add(dst, md, EdgeMetadata.Flatten)
+}
+
+func (c EdgeMetadatas) add(dst string, md EdgeMetadata, combine func(EdgeMetadata, EdgeMetadata) EdgeMetadata) EdgeMetadatas {
+	if c.psMap == nil {
+		c = emptyEdgeMetadatas
+	}
+	if existing, ok := c.psMap.Lookup(dst); ok {
+		md = combine(existing.(EdgeMetadata), md)
+	}
+	return EdgeMetadatas{c.psMap.Set(dst, md)}
+}
+
+// Lookup the edge to dst.
+func (c EdgeMetadatas) Lookup(dst string) (EdgeMetadata, bool) {
+	if c.psMap != nil {
+		existing, ok := c.psMap.Lookup(dst)
+		if ok {
+			return existing.(EdgeMetadata), true
+		}
+	}
+	return EdgeMetadata{}, false
+}
+
+// Size returns the number of edges
+func (c EdgeMetadatas) Size() int {
+	if c.psMap == nil {
+		return 0
+	}
+	return c.psMap.Size()
+}
+
+// ForEach executes f on each edge.
+func (c EdgeMetadatas) ForEach(f func(dst string, md EdgeMetadata)) {
+	if c.psMap != nil {
+		c.psMap.ForEach(func(key string, value interface{}) {
+			f(key, value.(EdgeMetadata))
+		})
+	}
+}
+
+// Merge produces a fresh EdgeMetadatas, containing the edges from both
+// inputs. Edges present in both are merged with EdgeMetadata.Merge.
+func (c EdgeMetadatas) Merge(other EdgeMetadatas) EdgeMetadatas {
+	var (
+		cSize     = c.Size()
+		otherSize = other.Size()
+		output    = c.psMap
+		iter      = other.psMap
+	)
+	switch {
+	case cSize == 0:
+		return other
+	case otherSize == 0:
+		return c
+	case cSize < otherSize:
+		output, iter = iter, output
+	}
+	iter.ForEach(func(key string, otherVal interface{}) {
+		if val, ok := output.Lookup(key); ok {
+			output = output.Set(key, otherVal.(EdgeMetadata).Merge(val.(EdgeMetadata)))
+		} else {
+			output = output.Set(key, otherVal)
+		}
+	})
+	return EdgeMetadatas{output}
+}
+
+func (c EdgeMetadatas) String() string {
+	return mapToString(c.psMap)
+}
+
+// DeepEqual tests equality with other EdgeMetadatas
+func (c EdgeMetadatas) DeepEqual(d EdgeMetadatas) bool {
+	return mapEqual(c.psMap, d.psMap, reflect.DeepEqual)
+}
+
+// CodecEncodeSelf implements codec.Selfer
+func (c *EdgeMetadatas) CodecEncodeSelf(encoder *codec.Encoder) {
+	mapWrite(c.psMap, encoder, func(encoder *codec.Encoder, val interface{}) {
+		md := val.(EdgeMetadata)
+		encoder.Encode(&md)
+	})
+}
+
+// CodecDecodeSelf implements codec.Selfer
+func (c *EdgeMetadatas) CodecDecodeSelf(decoder *codec.Decoder) {
+	out := mapRead(decoder, func(isNil bool) interface{} {
+		var value EdgeMetadata
+		if !isNil {
+			decoder.Decode(&value)
+		}
+		return value
+	})
+	*c = EdgeMetadatas{out}
+}
+
+// MarshalJSON shouldn't be used, use CodecEncodeSelf instead
+func (EdgeMetadatas) MarshalJSON() ([]byte, error) {
+	panic("MarshalJSON shouldn't be used, use CodecEncodeSelf instead")
+}
+
+// UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead
+func (*EdgeMetadatas) UnmarshalJSON(b []byte) error {
+	panic("UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead")
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ugorji/go/codec"
+
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+var (
+	edgeT1 = time.Unix(1500000000, 0).UTC()
+	edgeT2 = edgeT1.Add(time.Minute)
+)
+
+func TestEdgeMetadatasAdd(t *testing.T) {
+	// Adding the same edge twice, e.g. from conntrack and /proc, must
+	// not double count it.
+	have := MakeEdgeMetadatas().
+		Add("foo", EdgeMetadata{Connections: 1, EgressByteCount: 10, LastSeen: edgeT1}).
+		Add("foo", EdgeMetadata{Connections: 1, EgressByteCount: 20, FirstSeen: edgeT1, LastSeen: edgeT2})
+	want := EdgeMetadata{Connections: 1, EgressByteCount: 20, FirstSeen: edgeT1, LastSeen: edgeT2}
+	if md, ok := have.Lookup("foo"); !ok || !reflect.DeepEqual(want, md) {
+		t.Error(test.Diff(want, md))
+	}
+	if _, ok := have.Lookup("bar"); ok {
+		t.Errorf("bar != nil")
+	}
+}
+
+func TestEdgeMetadatasAggregate(t *testing.T) {
+	have := MakeEdgeMetadatas().
+		Aggregate("foo", EdgeMetadata{Connections: 1, EgressByteCount: 10, IngressPacketCount: 1, FirstSeen: edgeT2, LastSeen: edgeT2}).
+		Aggregate("foo", EdgeMetadata{Connections: 2, EgressByteCount: 20, IngressPacketCount: 2, FirstSeen: edgeT1, LastSeen: edgeT1})
+	want := EdgeMetadata{Connections: 3, EgressByteCount: 30, IngressPacketCount: 3, FirstSeen: edgeT1, LastSeen: edgeT2}
+	if md, ok := have.Lookup("foo"); !ok || !reflect.DeepEqual(want, md) {
+		t.Error(test.Diff(want, md))
+	}
+}
+
+func TestEdgeMetadataReversed(t *testing.T) {
+	have := EdgeMetadata{
+		Connections:        1,
+		EgressPacketCount:  1,
+		IngressPacketCount: 2,
+		EgressByteCount:    3,
+		IngressByteCount:   4,
+	}.Reversed()
+	want := EdgeMetadata{
+		Connections:        1,
+		EgressPacketCount:  2,
+		IngressPacketCount: 1,
+		EgressByteCount:    4,
+		IngressByteCount:   3,
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestEdgeMetadatasMerge(t *testing.T) {
+	for name, c := range map[string]struct {
+		a, b, want EdgeMetadatas
+	}{
+		"Empty a": {
+			a:    MakeEdgeMetadatas(),
+			b:    MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1}),
+			want: MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1}),
+		},
+		"Nil b": {
+			a:    MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1}),
+			b:    EdgeMetadatas{},
+			want: MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1}),
+		},
+		"Disjoint a & b": {
+			a: MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1}),
+			b: MakeEdgeMetadatas().Add("bar", EdgeMetadata{Connections: 2}),
+			want: MakeEdgeMetadatas().
+				Add("foo", EdgeMetadata{Connections: 1}).
+				Add("bar", EdgeMetadata{Connections: 2}),
+		},
+		"Overlapping a & b": {
+			a:    MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1, EgressByteCount: 5}),
+			b:    MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1, EgressByteCount: 7}),
+			want: MakeEdgeMetadatas().Add("foo", EdgeMetadata{Connections: 1, EgressByteCount: 7}),
+		},
+	} {
+		if have := c.a.Merge(c.b); !reflect.DeepEqual(c.want, have) {
+			t.Errorf("%s:\n%s", name, test.Diff(c.want, have))
+		}
+	}
+}
+
+func TestEdgeMetadatasEncoding(t *testing.T) {
+	want := MakeEdgeMetadatas().
+		Add("foo", EdgeMetadata{Connections: 1, EgressByteCount: 10, IngressByteCount: 20}).
+		Add("bar", EdgeMetadata{Connections: 2, EgressPacketCount: 3, IngressPacketCount: 4})
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		encoder := codec.NewEncoder(buf, h)
+		want.CodecEncodeSelf(encoder)
+		decoder := codec.NewDecoder(buf, h)
+		have := MakeEdgeMetadatas()
+		have.CodecDecodeSelf(decoder)
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
@@ -32,6 +32,18 @@ func MakeEndpointNodeID(hostID, namespaceID, address, port string) string {
 	return makeAddressID(hostID, namespaceID, address) + ScopeDelim + port
 }
 
+// MakeTransportEndpointNodeID produces the node ID of an endpoint of
+// connections over transport. TCP endpoints have the IDs made by
+// MakeEndpointNodeID; others are suffixed with their transport, so that
+// they aren't merged with the TCP endpoint on the same port.
+func MakeTransportEndpointNodeID(hostID, namespaceID, address, port, transport string) string {
+	id := MakeEndpointNodeID(hostID, namespaceID, address, port)
+	if transport != "" && transport != "tcp" {
+		id += ScopeDelim + transport
+	}
+	return id
+}
+
 // MakeAddressNodeID produces an address node ID from its composite parts.
 func MakeAddressNodeID(hostID, address string) string {
 	return makeAddressID(hostID, "", address)
@@ -140,6 +152,42 @@ var (
 	// ParseCronJobNodeID parses a cronjob node ID
 	ParseCronJobNodeID = parseSingleComponentID("cronjob")
 
+	// MakeJobNodeID produces a job node ID from its composite parts.
+	MakeJobNodeID = makeSingleComponentID("job")
+
+	// ParseJobNodeID parses a job node ID
+	ParseJobNodeID = parseSingleComponentID("job")
+
+	// MakeKubernetesNodeID produces a kubernetes node ID from its composite parts.
+	MakeKubernetesNodeID = makeSingleComponentID("kubernetes_node")
+
+	// ParseKubernetesNodeID parses a kubernetes node ID
+	ParseKubernetesNodeID = parseSingleComponentID("kubernetes_node")
+
+	// MakeIngressNodeID produces an ingress node ID from its composite parts.
+	MakeIngressNodeID = makeSingleComponentID("ingress")
+
+	// ParseIngressNodeID parses an ingress node ID
+	ParseIngressNodeID = parseSingleComponentID("ingress")
+
+	// MakePersistentVolumeClaimNodeID produces a persistent volume claim node ID from its composite parts.
+	MakePersistentVolumeClaimNodeID = makeSingleComponentID("persistentvolumeclaim")
+
+	// ParsePersistentVolumeClaimNodeID parses a persistent volume claim node ID
+	ParsePersistentVolumeClaimNodeID = parseSingleComponentID("persistentvolumeclaim")
+
+	// MakePersistentVolumeNodeID produces a persistent volume node ID from its composite parts.
+	MakePersistentVolumeNodeID = makeSingleComponentID("persistentvolume")
+
+	// ParsePersistentVolumeNodeID parses a persistent volume node ID
+	ParsePersistentVolumeNodeID = parseSingleComponentID("persistentvolume")
+
+	// MakeStorageClassNodeID produces a storage class node ID from its composite parts.
+	MakeStorageClassNodeID = makeSingleComponentID("storageclass")
+
+	// ParseStorageClassNodeID parses a storage class node ID
+	ParseStorageClassNodeID = parseSingleComponentID("storageclass")
+
 	// MakeNamespaceNodeID produces a namespace node ID from its composite parts.
 	MakeNamespaceNodeID = makeSingleComponentID("namespace")
 
@@ -216,7 +264,8 @@ func ParseNodeID(nodeID string) (id string, tag string, ok bool) {
 }
 
 // ParseEndpointNodeID produces the scope, address, and port and remainder.
-// Note that scope may be blank.
+// Note that scope may be blank. The transport of endpoints made by
+// MakeTransportEndpointNodeID is not part of the port.
 func ParseEndpointNodeID(endpointNodeID string) (scope, address, port string, ok bool) {
 	// Not using strings.SplitN() to avoid a heap allocation
 	first := strings.Index(endpointNodeID, ScopeDelim)
@@ -227,7 +276,11 @@ func ParseEndpointNodeID(endpointNodeID string) (scope, address, port string, ok
 	if second == -1 {
 		return "", "", "", false
 	}
-	return endpointNodeID[:first], endpointNodeID[first+1 : first+1+second], endpointNodeID[first+1+second+1:], true
+	port = endpointNodeID[first+1+second+1:]
+	if third := strings.Index(port, ScopeDelim); third != -1 {
+		port = port[:third]
+	}
+	return endpointNodeID[:first], endpointNodeID[first+1 : first+1+second], port, true
 }
 
 // ParseAddressNodeID produces the host ID, address from an address node ID.
@@ -53,6 +53,7 @@ func TestEndpointNodeID(t *testing.T) {
 		report.MakeEndpointNodeID("host.com", "namespaceid", "127.0.0.1", "c"): {"host.com-namespaceid", "127.0.0.1", "c"},
 		report.MakeEndpointNodeID("host.com", "", "1.2.3.4", "c"):              {"", "1.2.3.4", "c"},
 		"a;b;c": {"a", "b", "c"},
+		report.MakeTransportEndpointNodeID("host.com", "", "1.2.3.4", "53", "udp"): {"", "1.2.3.4", "53"},
 	} {
 		haveName, haveAddress, havePort, ok := report.ParseEndpointNodeID(input)
 		if !ok {
@@ -67,6 +68,17 @@ func TestEndpointNodeID(t *testing.T) {
 	}
 }
 
+func TestTransportEndpointNodeID(t *testing.T) {
+	tcp := report.MakeTransportEndpointNodeID("host.com", "", "1.2.3.4", "53", "tcp")
+	udp := report.MakeTransportEndpointNodeID("host.com", "", "1.2.3.4", "53", "udp")
+	if want := report.MakeEndpointNodeID("host.com", "", "1.2.3.4", "53"); tcp != want {
+		t.Errorf("Expected TCP endpoint ID %q, got %q", want, tcp)
+	}
+	if tcp == udp {
+		t.Errorf("Expected TCP and UDP endpoints to have different IDs, both got %q", tcp)
+	}
+}
+
 func TestECSServiceNodeIDCompat(t *testing.T) {
 	testID := "my-service;<ecs_service>"
 	testName := "my-service"
@@ -6,6 +6,7 @@ const (
 	ReverseDNSNames = "reverse_dns_names"
 	SnoopedDNSNames = "snooped_dns_names"
 	CopyOf          = "copy_of"
+	Transport       = "transport"
 	// probe/process
 	PID     = "pid"
 	Name    = "name" // also used by probe/docker
@@ -43,6 +44,10 @@ const (
 	DockerContainerUptime        = "docker_container_uptime"
 	DockerContainerRestartCount  = "docker_container_restart_count"
 	DockerContainerNetworkMode   = "docker_container_network_mode"
+	DockerContainerEventsPrefix  = "docker_container_events_"
+	DockerContainerEventTime     = "docker_container_event_time"
+	DockerContainerEventType     = "docker_container_event_type"
+	DockerContainerEventDetail   = "docker_container_event_detail"
 	// probe/kubernetes
 	KubernetesName                 = "kubernetes_name"
 	KubernetesNamespace            = "kubernetes_namespace"
@@ -56,6 +61,11 @@ const (
 	KubernetesDeletePod            = "kubernetes_delete_pod"
 	KubernetesScaleUp              = "kubernetes_scale_up"
 	KubernetesScaleDown            = "kubernetes_scale_down"
+	KubernetesRestartRollout       = "kubernetes_restart_rollout"
+	KubernetesRollback             = "kubernetes_rollback"
+	KubernetesExecContainer        = "kubernetes_exec_container"
+	KubernetesResizeExecTTY        = "kubernetes_resize_exec_tty"
+	KubernetesDescribe             = "kubernetes_describe"
 	KubernetesUpdatedReplicas      = "kubernetes_updated_replicas"
 	KubernetesAvailableReplicas    = "kubernetes_available_replicas"
 	KubernetesUnavailableReplicas  = "kubernetes_unavailable_replicas"
@@ -72,6 +82,23 @@ const (
 	KubernetesActiveJobs           = "kubernetes_active_jobs"
 	KubernetesType                 = "kubernetes_type"
 	KubernetesPorts                = "kubernetes_ports"
+	KubernetesIngressHosts         = "kubernetes_ingress_hosts"
+	KubernetesIngressAddresses     = "kubernetes_ingress_addresses"
+	KubernetesIngressTLSSecrets    = "kubernetes_ingress_tls_secrets"
+	KubernetesIngressServices      = "kubernetes_ingress_services"
+	KubernetesStorageCapacity      = "kubernetes_storage_capacity"
+	KubernetesStorageRequest       = "kubernetes_storage_request"
+	KubernetesAccessModes          = "kubernetes_access_modes"
+	KubernetesReclaimPolicy        = "kubernetes_reclaim_policy"
+	KubernetesStorageClassName     = "kubernetes_storage_class_name"
+	KubernetesVolumeName           = "kubernetes_volume_name"
+	KubernetesVolumeClaim          = "kubernetes_volume_claim"
+	KubernetesProvisioner          = "kubernetes_provisioner"
+	KubernetesCompletions          = "kubernetes_completions"
+	KubernetesFailures             = "kubernetes_failures"
+	KubernetesDuration             = "kubernetes_duration"
+	KubernetesKubeletVersion       = "kubernetes_kubelet_version"
+	KubernetesTaints               = "kubernetes_taints"
 	// probe/awsecs
 	ECSCluster             = "ecs_cluster"
 	ECSCreatedAt           = "ecs_created_at"
@@ -88,22 +115,28 @@ const (
    getting clogged with values that are only used once.
 */
 var commonKeys = map[string]string{
-	Endpoint:       Endpoint,
-	Process:        Process,
-	Container:      Container,
-	Pod:            Pod,
-	Service:        Service,
-	Deployment:     Deployment,
-	ReplicaSet:     ReplicaSet,
-	DaemonSet:      DaemonSet,
-	StatefulSet:    StatefulSet,
-	CronJob:        CronJob,
-	ContainerImage: ContainerImage,
-	Host:           Host,
-	Overlay:        Overlay,
-	ECSService:     ECSService,
-	ECSTask:        ECSTask,
-	SwarmService:   SwarmService,
+	Endpoint:              Endpoint,
+	Process:               Process,
+	Container:             Container,
+	Pod:                   Pod,
+	Service:               Service,
+	Deployment:            Deployment,
+	ReplicaSet:            ReplicaSet,
+	DaemonSet:             DaemonSet,
+	StatefulSet:           StatefulSet,
+	CronJob:               CronJob,
+	Job:                   Job,
+	Ingress:               Ingress,
+	PersistentVolumeClaim: PersistentVolumeClaim,
+	PersistentVolume:      PersistentVolume,
+	StorageClass:          StorageClass,
+	ContainerImage:        ContainerImage,
+	Host:                  Host,
+	KubernetesNode:        KubernetesNode,
+	Overlay:               Overlay,
+	ECSService:            ECSService,
+	ECSTask:               ECSTask,
+	SwarmService:          SwarmService,
 
 	HostNodeID:             HostNodeID,
 	ControlProbeID:         ControlProbeID,
@@ -162,6 +195,11 @@ var commonKeys = map[string]string{
 	KubernetesDeletePod:            KubernetesDeletePod,
 	KubernetesScaleUp:              KubernetesScaleUp,
 	KubernetesScaleDown:            KubernetesScaleDown,
+	KubernetesRestartRollout:       KubernetesRestartRollout,
+	KubernetesRollback:             KubernetesRollback,
+	KubernetesExecContainer:        KubernetesExecContainer,
+	KubernetesResizeExecTTY:        KubernetesResizeExecTTY,
+	KubernetesDescribe:             KubernetesDescribe,
 	KubernetesUpdatedReplicas:      KubernetesUpdatedReplicas,
 	KubernetesAvailableReplicas:    KubernetesAvailableReplicas,
 	KubernetesUnavailableReplicas:  KubernetesUnavailableReplicas,
@@ -178,6 +216,23 @@ var commonKeys = map[string]string{
 	KubernetesActiveJobs:           KubernetesActiveJobs,
 	KubernetesType:                 KubernetesType,
 	KubernetesPorts:                KubernetesPorts,
+	KubernetesIngressHosts:         KubernetesIngressHosts,
+	KubernetesIngressAddresses:     KubernetesIngressAddresses,
+	KubernetesIngressTLSSecrets:    KubernetesIngressTLSSecrets,
+	KubernetesIngressServices:      KubernetesIngressServices,
+	KubernetesStorageCapacity:      KubernetesStorageCapacity,
+	KubernetesStorageRequest:       KubernetesStorageRequest,
+	KubernetesAccessModes:          KubernetesAccessModes,
+	KubernetesReclaimPolicy:        KubernetesReclaimPolicy,
+	KubernetesStorageClassName:     KubernetesStorageClassName,
+	KubernetesVolumeName:           KubernetesVolumeName,
+	KubernetesVolumeClaim:          KubernetesVolumeClaim,
+	KubernetesProvisioner:          KubernetesProvisioner,
+	KubernetesCompletions:          KubernetesCompletions,
+	KubernetesFailures:             KubernetesFailures,
+	KubernetesDuration:             KubernetesDuration,
+	KubernetesKubeletVersion:       KubernetesKubeletVersion,
+	KubernetesTaints:               KubernetesTaints,
 
 	ECSCluster:             ECSCluster,
 	ECSCreatedAt:           ECSCreatedAt,
@@ -1,7 +1,7 @@
 package report
 
 import (
-	"encoding/binary"
+	"math/bits"
 	"net"
 	"strings"
 
@@ -59,7 +59,7 @@ func LocalAddresses() ([]net.IP, error) {
 			return []net.IP{}, err
 		}
 
-		for _, ipnet := range ipv4Nets(addrs) {
+		for _, ipnet := range ipNets(addrs) {
 			result = append(result, ipnet.IP)
 		}
 	}
@@ -81,7 +81,7 @@ func AddLocalBridge(name string) error {
 		return err
 	}
 
-	for _, ipnet := range ipv4Nets(addrs) {
+	for _, ipnet := range ipNets(addrs) {
 		LocalNetworks.Add(ipnet)
 	}
 
@@ -94,15 +94,23 @@ func GetLocalNetworks() ([]*net.IPNet, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ipv4Nets(addrs), nil
+	return ipNets(addrs), nil
 }
 
-func ipv4Nets(addrs []net.Addr) []*net.IPNet {
+// ipNets returns the IPv4 and IPv6 networks of addrs. IPv6 link-local
+// and loopback networks are left out: every host has the same ones, so
+// they don't tell hosts apart.
+func ipNets(addrs []net.Addr) []*net.IPNet {
 	nets := []*net.IPNet{}
 	for _, addr := range addrs {
-		if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
-			nets = append(nets, ipnet)
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || ipnet.IP.To16() == nil {
+			continue
+		}
+		if ipnet.IP.To4() == nil && (ipnet.IP.IsLinkLocalUnicast() || ipnet.IP.IsLoopback()) {
+			continue
 		}
+		nets = append(nets, ipnet)
 	}
 	return nets
 }
@@ -111,32 +119,45 @@ func ipv4Nets(addrs []net.Addr) []*net.IPNet {
 // the given IPv4 addresses. When no addresses are specified, nil is
 // returned.
 func ContainingIPv4Network(ips []net.IP) *net.IPNet {
+	return containingNetwork(ips, net.IPv4len)
+}
+
+// ContainingIPv6Network determines the smallest network containing
+// the given IPv6 addresses. When no addresses are specified, nil is
+// returned.
+func ContainingIPv6Network(ips []net.IP) *net.IPNet {
+	return containingNetwork(ips, net.IPv6len)
+}
+
+// containingNetwork determines the smallest network containing ips,
+// which are all ipLen bytes long.
+func containingNetwork(ips []net.IP, ipLen int) *net.IPNet {
 	if len(ips) == 0 {
 		return nil
 	}
-	cpl := net.IPv4len * 8
-	network := networkFromPrefix(ips[0], cpl)
+	cpl := ipLen * 8
+	network := networkFromPrefix(ips[0], cpl, ipLen)
 	for _, ip := range ips[1:] {
-		if ncpl := commonIPv4PrefixLen(network.IP, ip); ncpl < cpl {
+		if ncpl := commonPrefixLen(network.IP, ip); ncpl < cpl {
 			cpl = ncpl
-			network = networkFromPrefix(network.IP, cpl)
+			network = networkFromPrefix(network.IP, cpl, ipLen)
 		}
 	}
 	return network
 }
 
-func networkFromPrefix(ip net.IP, prefixLen int) *net.IPNet {
-	mask := net.CIDRMask(prefixLen, net.IPv4len*8)
+func networkFromPrefix(ip net.IP, prefixLen, ipLen int) *net.IPNet {
+	mask := net.CIDRMask(prefixLen, ipLen*8)
 	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
 }
 
-func commonIPv4PrefixLen(a, b net.IP) int {
-	x := binary.BigEndian.Uint32(a)
-	y := binary.BigEndian.Uint32(b)
-	cpl := 32
-	for ; x != y; cpl-- {
-		x >>= 1
-		y >>= 1
+func commonPrefixLen(a, b net.IP) int {
+	cpl := 0
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if x := a[i] ^ b[i]; x != 0 {
+			return cpl + bits.LeadingZeros8(x)
+		}
+		cpl += 8
 	}
 	return cpl
 }
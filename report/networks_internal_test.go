@@ -0,0 +1,24 @@
+package report
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPNets(t *testing.T) {
+	addrs := []net.Addr{}
+	for _, cidr := range []string{"127.0.0.1/8", "10.0.0.1/24", "::1/128", "fe80::1/64", "fd00::1/64"} {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, ipnet)
+	}
+	have := []string{}
+	for _, ipnet := range ipNets(addrs) {
+		have = append(have, ipnet.String())
+	}
+	assert.Equal(t, []string{"127.0.0.0/8", "10.0.0.0/24", "fd00::/64"}, have)
+}
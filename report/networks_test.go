@@ -23,6 +23,18 @@ func TestContains(t *testing.T) {
 	if !networks.Contains(net.ParseIP("10.0.0.1")) {
 		t.Errorf("10.0.0.1 in %v", networks)
 	}
+
+	if err := networks.AddCIDR("fd00:1::/64"); err != nil {
+		panic(err)
+	}
+
+	if !networks.Contains(net.ParseIP("fd00:1::5")) {
+		t.Errorf("fd00:1::5 in %v", networks)
+	}
+
+	if networks.Contains(net.ParseIP("fd00:2::5")) {
+		t.Errorf("fd00:2::5 not in %v", networks)
+	}
 }
 
 func TestContainingIPv4Network(t *testing.T) {
@@ -33,6 +45,22 @@ func TestContainingIPv4Network(t *testing.T) {
 	assert.Equal(t, "0.0.0.0/0", containingIPv4Networks([]string{"10.0.0.1", "192.168.0.1"}).String())
 }
 
+func TestContainingIPv6Network(t *testing.T) {
+	assert.Nil(t, containingIPv6Networks([]string{}))
+	assert.Equal(t, "fd00::1/128", containingIPv6Networks([]string{"fd00::1"}).String())
+	assert.Equal(t, "fd00::/120", containingIPv6Networks([]string{"fd00::1", "fd00::ff"}).String())
+	assert.Equal(t, "fd00:0:0:1::/64", containingIPv6Networks([]string{"fd00:0:0:1::1", "fd00:0:0:1:8000::1"}).String())
+	assert.Equal(t, "::/0", containingIPv6Networks([]string{"fd00::1", "2001:db8::1"}).String())
+}
+
+func containingIPv6Networks(ipstrings []string) *net.IPNet {
+	ips := make([]net.IP, len(ipstrings))
+	for i, ip := range ipstrings {
+		ips[i] = net.ParseIP(ip)
+	}
+	return report.ContainingIPv6Network(ips)
+}
+
 func containingIPv4Networks(ipstrings []string) *net.IPNet {
 	ips := make([]net.IP, len(ipstrings))
 	for i, ip := range ipstrings {
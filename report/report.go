@@ -12,23 +12,29 @@ import (
 
 // Names of the various topologies.
 const (
-	Endpoint       = "endpoint"
-	Process        = "process"
-	Container      = "container"
-	Pod            = "pod"
-	Service        = "service"
-	Deployment     = "deployment"
-	ReplicaSet     = "replica_set"
-	DaemonSet      = "daemon_set"
-	StatefulSet    = "stateful_set"
-	CronJob        = "cron_job"
-	Namespace      = "namespace"
-	ContainerImage = "container_image"
-	Host           = "host"
-	Overlay        = "overlay"
-	ECSService     = "ecs_service"
-	ECSTask        = "ecs_task"
-	SwarmService   = "swarm_service"
+	Endpoint              = "endpoint"
+	Process               = "process"
+	Container             = "container"
+	Pod                   = "pod"
+	Service               = "service"
+	Deployment            = "deployment"
+	ReplicaSet            = "replica_set"
+	DaemonSet             = "daemon_set"
+	StatefulSet           = "stateful_set"
+	CronJob               = "cron_job"
+	Job                   = "job"
+	Ingress               = "ingress"
+	PersistentVolumeClaim = "persistent_volume_claim"
+	PersistentVolume      = "persistent_volume"
+	StorageClass          = "storage_class"
+	Namespace             = "namespace"
+	ContainerImage        = "container_image"
+	Host                  = "host"
+	KubernetesNode        = "kubernetes_node"
+	Overlay               = "overlay"
+	ECSService            = "ecs_service"
+	ECSTask               = "ecs_task"
+	SwarmService          = "swarm_service"
 
 	// Shapes used for different nodes
 	Circle   = "circle"
@@ -57,8 +63,14 @@ var topologyNames = []string{
 	DaemonSet,
 	StatefulSet,
 	CronJob,
+	Job,
+	Ingress,
+	PersistentVolumeClaim,
+	PersistentVolume,
+	StorageClass,
 	Namespace,
 	Host,
+	KubernetesNode,
 	Overlay,
 	ECSTask,
 	ECSService,
@@ -117,6 +129,33 @@ type Report struct {
 	// present.
 	CronJob Topology
 
+	// Job nodes represent all Kubernetes Jobs running on hosts running probes.
+	// Metadata includes things like Job id, name, completions, etc. Edges are not
+	// present.
+	Job Topology
+
+	// Ingress nodes represent all Kubernetes Ingresses running on hosts running probes.
+	// Metadata includes things like Ingress id, name, hosts, etc. Edges are not
+	// present; the Services backing an Ingress are recorded in a set instead.
+	Ingress Topology
+
+	// PersistentVolumeClaim nodes represent all Kubernetes Persistent Volume Claims
+	// running on hosts running probes. Metadata includes things like claim id, name,
+	// capacity, etc. Edges are not present; the claim's volume and storage class
+	// are its parents.
+	PersistentVolumeClaim Topology
+
+	// PersistentVolume nodes represent all Kubernetes Persistent Volumes running on
+	// hosts running probes. Metadata includes things like volume id, name, capacity,
+	// reclaim policy, etc. Edges are not present; the volume's storage class is its
+	// parent.
+	PersistentVolume Topology
+
+	// StorageClass nodes represent all Kubernetes Storage Classes running on hosts
+	// running probes. Metadata includes things like class id, name, provisioner, etc.
+	// Edges are not present.
+	StorageClass Topology
+
 	// Namespace nodes represent all Kubernetes Namespaces running on hosts running probes.
 	// Metadata includes things like Namespace id, name, etc. Edges are not
 	// present.
@@ -132,6 +171,12 @@ type Report struct {
 	// probes with each published report. Edges are not present.
 	Host Topology
 
+	// KubernetesNode nodes represent all Kubernetes Nodes of the clusters of
+	// hosts running probes. Metadata includes things like Node id, name,
+	// conditions, kubelet version, etc. Edges are not present; the Host of the
+	// same name is their parent.
+	KubernetesNode Topology
+
 	// ECS Task nodes are AWS ECS tasks, which represent a group of containers.
 	// Metadata is limited for now, more to come later. Edges are not present.
 	ECSTask Topology
@@ -226,8 +271,32 @@ func MakeReport() Report {
 			WithShape(Triangle).
 			WithLabel("cron job", "cron jobs"),
 
+		Job: MakeTopology().
+			WithShape(Triangle).
+			WithLabel("job", "jobs"),
+
+		Ingress: MakeTopology().
+			WithShape(Hexagon).
+			WithLabel("ingress", "ingresses"),
+
+		PersistentVolumeClaim: MakeTopology().
+			WithShape(Square).
+			WithLabel("persistent volume claim", "persistent volume claims"),
+
+		PersistentVolume: MakeTopology().
+			WithShape(Heptagon).
+			WithLabel("persistent volume", "persistent volumes"),
+
+		StorageClass: MakeTopology().
+			WithShape(Pentagon).
+			WithLabel("storage class", "storage classes"),
+
 		Namespace: MakeTopology(),
 
+		KubernetesNode: MakeTopology().
+			WithShape(Circle).
+			WithLabel("kubernetes node", "kubernetes nodes"),
+
 		Overlay: MakeTopology().
 			WithShape(Circle).
 			WithLabel("peer", "peers"),
@@ -333,10 +402,22 @@ func (r *Report) topology(name string) *Topology {
 		return &r.StatefulSet
 	case CronJob:
 		return &r.CronJob
+	case Job:
+		return &r.Job
+	case Ingress:
+		return &r.Ingress
+	case PersistentVolumeClaim:
+		return &r.PersistentVolumeClaim
+	case PersistentVolume:
+		return &r.PersistentVolume
+	case StorageClass:
+		return &r.StorageClass
 	case Namespace:
 		return &r.Namespace
 	case Host:
 		return &r.Host
+	case KubernetesNode:
+		return &r.KubernetesNode
 	case Overlay:
 		return &r.Overlay
 	case ECSTask:
@@ -452,7 +533,7 @@ func (r Report) upgradeNamespaces() Report {
 	}
 
 	namespaces := map[string]struct{}{}
-	for _, t := range []Topology{r.Pod, r.Service, r.Deployment, r.DaemonSet, r.StatefulSet, r.CronJob} {
+	for _, t := range []Topology{r.Pod, r.Service, r.Deployment, r.DaemonSet, r.StatefulSet, r.CronJob, r.Job, r.Ingress, r.PersistentVolumeClaim} {
 		for _, n := range t.Nodes {
 			if state, ok := n.Latest.Lookup(KubernetesState); ok && state == "deleted" {
 				continue